@@ -0,0 +1,65 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+	"time"
+)
+
+// thresholdAlertHook counts messages at or above level in a sliding window
+type thresholdAlertHook struct {
+	level  LevelType
+	count  int
+	window time.Duration
+	notify func(n int)
+
+	// times of messages counted inside window, oldest first
+	times []time.Time
+
+	lock *sync.Mutex
+}
+
+// NewThresholdAlertHook creates a hook calling notify with number of messages
+// when more than count messages at or above level are logged within window,
+// e.g. to page somebody on an error spike. messages counted are forgotten
+// once notify is called, so that it is called again only after another
+// count+1 messages. notify is called without lock held, it may log.
+// set hook level of writers no higher than level, so that every message
+// concerned reaches the hook
+func NewThresholdAlertHook(level LevelType, count int, window time.Duration, notify func(n int)) Hook {
+	return &thresholdAlertHook{
+		level:  level,
+		count:  count,
+		window: window,
+		notify: notify,
+		lock:   new(sync.Mutex),
+	}
+}
+
+// Fire counts the message and calls notify when threshold is crossed
+func (hook *thresholdAlertHook) Fire(t time.Time, level LevelType, message string) {
+	if level < hook.level {
+		return
+	}
+
+	hook.lock.Lock()
+
+	// forget messages out of window
+	expired := 0
+	for expired < len(hook.times) && !hook.times[expired].After(t.Add(-hook.window)) {
+		expired++
+	}
+	hook.times = append(hook.times[:0], hook.times[expired:]...)
+	hook.times = append(hook.times, t)
+
+	n := len(hook.times)
+	if n <= hook.count {
+		hook.lock.Unlock()
+		return
+	}
+	hook.times = hook.times[:0]
+	hook.lock.Unlock()
+
+	hook.notify(n)
+}
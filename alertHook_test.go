@@ -0,0 +1,61 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThresholdAlertHook(t *testing.T) {
+	var notified []int
+	hook := NewThresholdAlertHook(ERROR, 3, time.Minute, func(n int) {
+		notified = append(notified, n)
+	})
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		hook.Fire(now, ERROR, "failed")
+		hook.Fire(now, WARNING, "below level")
+	}
+	if 0 != len(notified) {
+		t.Errorf("notify should not be called before threshold is crossed. notified: %v", notified)
+	}
+
+	hook.Fire(now, CRITICAL, "failed")
+	if 1 != len(notified) || 4 != notified[0] {
+		t.Errorf("notify should be called after threshold is crossed. notified: %v", notified)
+	}
+
+	// messages out of window are not counted
+	for i := 0; i < 3; i++ {
+		hook.Fire(now.Add(time.Duration(i)*time.Minute), ERROR, "failed")
+	}
+	hook.Fire(now.Add(3*time.Minute), ERROR, "failed")
+	if 1 != len(notified) {
+		t.Errorf("messages out of window should not be counted. notified: %v", notified)
+	}
+}
+
+func TestThresholdAlertHookWithWriter(t *testing.T) {
+	notified := make(chan int, 1)
+	hook := NewThresholdAlertHook(ERROR, 1, time.Minute, func(n int) {
+		notified <- n
+	})
+
+	writer := NewSinkWriter(newMySink())
+	writer.SetHook(hook)
+	writer.SetHookLevel(ERROR)
+
+	writer.Error("first")
+	writer.Error("second")
+
+	select {
+	case n := <-notified:
+		if 2 != n {
+			t.Errorf("notify count wrong. n: %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("notify should be called by hooks fired from writer")
+	}
+}
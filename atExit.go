@@ -0,0 +1,107 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// flushAll flushes the singleton writer and writers, best effort
+func flushAll(writers []Writer) {
+	singltonLock.Lock()
+	if nil != blog() {
+		blog().flush()
+	}
+	singltonLock.Unlock()
+
+	for _, writer := range writers {
+		writer.flush()
+	}
+}
+
+// RegisterAtExit flushes the singleton writer and writers when the process
+// receives SIGINT or SIGTERM, so that buffered lines are not lost when the
+// program is stopped. It is opt-in, call it once in main. After flushing,
+// the signal is raised again, which terminates the process as default when
+// nothing else handles it; handlers registered by signal.Notify keep
+// receiving signals, including the one raised again.
+// Go has no hook on normal exit, keep defer blog4go.Close() in main for it.
+// The returned function unregisters it
+func RegisterAtExit(writers ...Writer) (unregister func()) {
+	return onShutdown(func() { flushAll(writers) })
+}
+
+// drainAll drains the singleton writer and writers at the same time, async
+// queues included, and return false if it is not done within timeout
+func drainAll(writers []Writer, timeout time.Duration) bool {
+	if singlton := blog(); nil != singlton {
+		writers = append([]Writer{singlton}, writers...)
+	}
+
+	wg := new(sync.WaitGroup)
+	for _, writer := range writers {
+		wg.Add(1)
+		go func(writer Writer) {
+			defer wg.Done()
+			writer.Drain()
+		}(writer)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// FlushOnShutdown drains the singleton writer and writers, async queues
+// included, when the process receives SIGINT or SIGTERM, waiting at most
+// timeout, e.g. the termination grace period of a kubernetes pod. The signal
+// is raised again afterwards as RegisterAtExit does, handlers registered by
+// signal.Notify keep receiving signals.
+// The returned function unregisters it
+func FlushOnShutdown(timeout time.Duration, writers ...Writer) (unregister func()) {
+	return onShutdown(func() {
+		if !drainAll(writers, timeout) {
+			internalError("drain on shutdown not done within %s", timeout)
+		}
+	})
+}
+
+// onShutdown calls shutdown once on SIGINT or SIGTERM, then raises the
+// signal again
+func onShutdown(shutdown func()) (unregister func()) {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-sigs:
+			shutdown()
+			signal.Stop(sigs)
+
+			// let default behavior or other handlers take it
+			if p, err := os.FindProcess(os.Getpid()); nil == err {
+				p.Signal(sig)
+			}
+		case <-done:
+			signal.Stop(sigs)
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
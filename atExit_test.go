@@ -0,0 +1,113 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegisterAtExit(t *testing.T) {
+	// keep the test process alive, signals are handled here as well
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	writer, err := newBaseFileWriter("/tmp/atexit.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	defer RegisterAtExit(writer)()
+
+	writer.Info("buffered")
+	if content, _ := ioutil.ReadFile("/tmp/atexit.log"); 0 != len(content) {
+		t.Fatalf("line should be buffered. content: %s", string(content))
+	}
+
+	// the signal sent and the one raised again after flushing
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sigs:
+		case <-time.After(time.Second):
+			t.Fatalf("signal should be raised again after flushing")
+		}
+	}
+
+	if content, _ := ioutil.ReadFile("/tmp/atexit.log"); !strings.Contains(string(content), "buffered") {
+		t.Errorf("buffered line should be flushed on signal. content: %s", string(content))
+	}
+}
+
+// stuckSink is a sink whose Flush blocks until stuck is closed
+type stuckSink struct {
+	*mySink
+	stuck chan struct{}
+}
+
+func (sink *stuckSink) Flush() error {
+	<-sink.stuck
+	return sink.mySink.Flush()
+}
+
+func TestFlushOnShutdown(t *testing.T) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	writer, err := newBaseFileWriter("/tmp/shutdown.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	sink := &stuckSink{mySink: newMySink(), stuck: make(chan struct{})}
+	stuckWriter := NewSinkWriter(sink)
+	defer func() {
+		close(sink.stuck)
+		stuckWriter.Close()
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	defer FlushOnShutdown(100*time.Millisecond, stuckWriter, writer)()
+
+	writer.Info("buffered")
+
+	// raised again after timeout even if draining is stuck
+	start := time.Now()
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sigs:
+		case <-time.After(time.Second):
+			t.Fatalf("signal should be raised again after timeout")
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("signal should be raised again after draining or timeout. elapsed: %s", elapsed)
+	}
+
+	if content, _ := ioutil.ReadFile("/tmp/shutdown.log"); !strings.Contains(string(content), "buffered") {
+		t.Errorf("buffered line should be drained on signal. content: %s", string(content))
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// AuditHashKey precedes hash appended to every line of audit logs
+	AuditHashKey = " h="
+)
+
+// AuditError describes where an audit log is found modified
+type AuditError struct {
+	// Line is the line number, starting from 1
+	Line int
+	// Reason describes the mismatch
+	Reason string
+}
+
+// Error implements error interface
+func (err *AuditError) Error() string {
+	return fmt.Sprintf("blog4go: audit log %s at line %d", err.Reason, err.Line)
+}
+
+// auditChain appends hash chaining every line to the previous one before
+// writing it to w. bytes flushed by BLog may end in the middle of a line, the
+// unfinished part is kept until its EOL arrives
+type auditChain struct {
+	w    io.Writer
+	prev []byte
+	tail []byte
+	out  []byte
+}
+
+// newAuditChain create an auditChain continuing hash prev of the last line
+func newAuditChain(w io.Writer, prev []byte) *auditChain {
+	return &auditChain{w: w, prev: prev}
+}
+
+// auditHash return hash of line chained to hash of the previous line
+func auditHash(prev, line []byte) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	h.Write(line)
+	return h.Sum(nil)
+}
+
+func (chain *auditChain) Write(p []byte) (int, error) {
+	chain.out = chain.out[:0]
+	data := p
+	for {
+		i := bytes.IndexByte(data, EOL)
+		if i < 0 {
+			chain.tail = append(chain.tail, data...)
+			break
+		}
+
+		line := data[:i]
+		if 0 < len(chain.tail) {
+			line = append(chain.tail, line...)
+			chain.tail = chain.tail[:0]
+		}
+
+		chain.prev = auditHash(chain.prev, line)
+		chain.out = append(chain.out, line...)
+		chain.out = append(chain.out, AuditHashKey...)
+		chain.out = append(chain.out, hex.EncodeToString(chain.prev)...)
+		chain.out = append(chain.out, EOL)
+		data = data[i+1:]
+	}
+
+	if 0 < len(chain.out) {
+		if _, err := chain.w.Write(chain.out); nil != err {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// NewAuditWriter initialize a tamper evident file writer for audit logs,
+// singlton. Every line ends with h=<hex>, sha256 of hash of the previous line
+// and the line itself, so that modifying or deleting a line breaks the chain
+// verified by VerifyAuditLog. Writing an existing file continues its chain,
+// every file of logrotate has a chain of its own
+func NewAuditWriter(path string) (err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+
+	if nil != blog() {
+		return ErrAlreadyInit
+	}
+
+	auditWriter, err := newAuditWriter(path)
+	if nil != err {
+		return err
+	}
+
+	setBlog(auditWriter)
+	return nil
+}
+
+// newAuditWriter create a tamper evident file writer, not singlton
+func newAuditWriter(path string) (auditWriter *baseFileWriter, err error) {
+	prev, err := lastAuditHash(path)
+	if nil != err {
+		return nil, err
+	}
+
+	auditWriter, err = newBaseFileWriter(path, false)
+	if nil != err {
+		return nil, err
+	}
+
+	auditWriter.lock.Lock()
+	defer auditWriter.lock.Unlock()
+
+	auditWriter.audit = true
+	auditWriter.blog.resetFile(newAuditChain(auditWriter.file, prev))
+	return auditWriter, nil
+}
+
+// input return what BLog writes to for file, an auditChain in audit mode
+func (writer *baseFileWriter) input(file *logFile, fileName string) (io.Writer, error) {
+	if !writer.audit {
+		return file, nil
+	}
+
+	prev, err := lastAuditHash(fileName)
+	if nil != err {
+		return nil, err
+	}
+	return newAuditChain(file, prev), nil
+}
+
+// lastAuditHash verifies audit log of path and return hash of its last line,
+// nil if path does not exist or is empty
+func lastAuditHash(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+
+	var prev []byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+
+		i := bytes.LastIndex(line, []byte(AuditHashKey))
+		if i < 0 {
+			return nil, &AuditError{Line: n, Reason: "hash missing"}
+		}
+
+		hash, err := hex.DecodeString(string(line[i+len(AuditHashKey):]))
+		if nil != err || !bytes.Equal(hash, auditHash(prev, line[:i])) {
+			return nil, &AuditError{Line: n, Reason: "hash mismatch"}
+		}
+		prev = hash
+	}
+	return prev, scanner.Err()
+}
+
+// VerifyAuditLog re-verifies hash chain of an audit log written by
+// NewAuditWriter, an *AuditError tells the first line modified, or the line
+// following one deleted
+func VerifyAuditLog(path string) error {
+	if _, err := os.Stat(path); nil != err {
+		return err
+	}
+
+	_, err := lastAuditHash(path)
+	return err
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestAuditWriter(t *testing.T) {
+	writer, err := newAuditWriter("/tmp/audit.log")
+	if nil != err {
+		t.Fatalf("Failed when initializing audit writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		writer.Infof("transfer %d", i)
+	}
+	writer.Close()
+
+	if err = VerifyAuditLog("/tmp/audit.log"); nil != err {
+		t.Fatalf("audit log should be verified. err: %s", err.Error())
+	}
+
+	// chain continues on existing file
+	writer, err = newAuditWriter("/tmp/audit.log")
+	if nil != err {
+		t.Fatalf("Failed when reopening audit writer. err: %s", err.Error())
+	}
+	writer.Info("transfer 5")
+	writer.Close()
+
+	content, _ := ioutil.ReadFile("/tmp/audit.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 6 != len(lines) {
+		t.Fatalf("audit log should have 6 lines. got: %q", lines)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, AuditHashKey) {
+			t.Errorf("audit line should end with hash. got: %s", line)
+		}
+	}
+	if err = VerifyAuditLog("/tmp/audit.log"); nil != err {
+		t.Fatalf("reopened audit log should be verified. err: %s", err.Error())
+	}
+
+	// modify a middle line
+	lines[2] = strings.Replace(lines[2], "transfer 2", "transfer 9", 1)
+	ioutil.WriteFile("/tmp/audit.log", []byte(strings.Join(lines, "\n")+"\n"), 0644)
+
+	err = VerifyAuditLog("/tmp/audit.log")
+	if auditErr, ok := err.(*AuditError); !ok || 3 != auditErr.Line {
+		t.Errorf("modified line should fail verification. err: %v", err)
+	}
+}
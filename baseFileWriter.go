@@ -5,7 +5,13 @@ package blog4go
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,6 +35,12 @@ const (
 
 	// DefaultLogRetentionCount is the default days of logs to be keeped
 	DefaultLogRetentionCount = 7
+
+	// DefaultFileMode is the default permission of log files
+	DefaultFileMode os.FileMode = 0644
+
+	// BannerFormat is the format of banner line, with opening time and pid
+	BannerFormat = "==== log opened at %s pid=%d ===="
 )
 
 // baseFileWriter defines a writer for single file.
@@ -36,13 +48,18 @@ const (
 // logrotate, user defined hook for every logging action, change configuration
 // on the fly and logging with colors.
 type baseFileWriter struct {
+	// sizes sent to and handled from logSizeChan, used in Drain
+	// accessed atomically, keep them first for 64-bit alignment
+	queuedSizes  int64
+	handledSizes int64
+
 	// configuration about file
 	// full path of the file, the same as configuration
 	fileName string
 	// current file name of the writer, may be changed with logrotate
 	currentFileName string
 	// the file object
-	file *os.File
+	file *logFile
 
 	// the BLog
 	blog *BLog
@@ -51,6 +68,15 @@ type baseFileWriter struct {
 	// set this tag true if writer is closed
 	closed bool
 
+	// flush buffer every second in daemon, default true
+	autoFlush bool
+
+	// stack traces written by ErrorStack
+	stacks stackDedup
+
+	// lines are chained with hashes, see NewAuditWriter
+	audit bool
+
 	// configuration about user defined logging hook
 	// actual hook instance
 	hook Hook
@@ -93,9 +119,28 @@ type baseFileWriter struct {
 
 	// number of logs retention when time base logrotate or size base logrotate
 	retentions int64
+	// max total size of all archives, oldest archives are removed after
+	// logrotate until total size is under it. 0 means no limit, default 0
+	maxTotalSize int64
 
 	// sign decided logging with colors or not, default false
 	colored bool
+
+	// permission of log files, default DefaultFileMode
+	fileMode os.FileMode
+	// owner of log files, -1 means not changed, default -1
+	uid int
+	gid int
+
+	// write a banner line whenever log file is opened, default false
+	openBanner bool
+	// whether banner is written to current file
+	bannerWritten bool
+
+	// user defined logrotate policy, default nil
+	rotateHook RotateHook
+	// total size written after last rotation of rotateHook
+	hookSize int64
 }
 
 // NewBaseFileWriter initialize a base file writer
@@ -103,7 +148,7 @@ func NewBaseFileWriter(fileName string, timeRotated bool) (err error) {
 	singltonLock.Lock()
 	defer singltonLock.Unlock()
 
-	if nil != blog {
+	if nil != blog() {
 		return ErrAlreadyInit
 	}
 
@@ -112,10 +157,17 @@ func NewBaseFileWriter(fileName string, timeRotated bool) (err error) {
 		return err
 	}
 
-	blog = baseFileWriter
+	setBlog(baseFileWriter)
 	return err
 }
 
+// openLogFile opens fileName for logging, every path opening a log file must
+// use it. O_APPEND makes every write go to the end of file even if other
+// writers or processes hold the same file, so nothing is overwritten.
+func openLogFile(fileName string, mode os.FileMode) (*os.File, error) {
+	return os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, mode)
+}
+
 // newbaseFileWriter create a single file writer instance and return the poionter
 // of it. When any errors happened during creation, a null writer and appropriate
 // will be returned.
@@ -128,15 +180,17 @@ func newBaseFileWriter(fileName string, timeRotated bool) (fileWriter *baseFileW
 	if timeRotated {
 		fileName = fmt.Sprintf("%s.%s", fileName, timeCache.Date())
 	}
-	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+	file, err := newLogFile(fileName, DefaultFileMode)
 	fileWriter.file = file
 	fileWriter.currentFileName = fileName
 	if nil != err {
 		return nil, err
 	}
 	fileWriter.blog = NewBLog(file)
+	fileWriter.blog.name = fileWriter.fileName
 
 	fileWriter.closed = false
+	fileWriter.autoFlush = true
 
 	// about logrotate
 	fileWriter.lock = new(sync.RWMutex)
@@ -153,9 +207,20 @@ func newBaseFileWriter(fileName string, timeRotated bool) (fileWriter *baseFileW
 	fileWriter.rotateLines = DefaultRotateLines
 	fileWriter.currentLines = 0
 	fileWriter.retentions = DefaultLogRetentionCount
+	fileWriter.maxTotalSize = 0
 
 	fileWriter.colored = false
 
+	fileWriter.fileMode = DefaultFileMode
+	fileWriter.uid = -1
+	fileWriter.gid = -1
+
+	fileWriter.openBanner = false
+	fileWriter.bannerWritten = false
+
+	fileWriter.rotateHook = nil
+	fileWriter.hookSize = 0
+
 	// log hook
 	fileWriter.hook = nil
 	fileWriter.hookLevel = DEBUG
@@ -187,7 +252,9 @@ DaemonLoop:
 				break DaemonLoop
 			}
 
-			writer.blog.flush()
+			if writer.autoFlush {
+				writer.blog.flush()
+			}
 		case <-t:
 			if writer.Closed() {
 				break DaemonLoop
@@ -197,7 +264,6 @@ DaemonLoop:
 				// if fileName not equal to currentFileName, it needs a time base logrotate
 				if fileName := fmt.Sprintf("%s.%s", writer.fileName, timeCache.Date()); writer.currentFileName != fileName {
 					writer.resetFile()
-					writer.currentFileName = fileName
 
 					// when it needs to expire logs
 					if writer.retentions > 0 {
@@ -209,6 +275,10 @@ DaemonLoop:
 							os.Remove(expiredFileName)
 						}
 					}
+
+					writer.lock.Lock()
+					writer.prune()
+					writer.lock.Unlock()
 				}
 			}
 
@@ -219,57 +289,283 @@ DaemonLoop:
 				break DaemonLoop
 			}
 
-			if !writer.sizeRotated && !writer.lineRotated {
-				continue
-			}
+			writer.handleSize(size)
+			atomic.AddInt64(&writer.handledSizes, 1)
+		}
+	}
+}
 
-			// TODO have any better solution?
-			// use func to ensure writer.lock will be released
-			writer.lock.Lock()
-			writer.currentSize += int64(size)
-			writer.currentLines++
-			writer.lock.Unlock()
-
-			if (writer.sizeRotated && writer.currentSize >= writer.rotateSize) || (writer.lineRotated && writer.currentLines >= writer.rotateLines) {
-				// need lines && size base logrotate
-				var oldName, newName string
-				oldName = fmt.Sprintf("%s.%d", writer.currentFileName, writer.retentions)
-				// check if expired log exists
-				if _, err := os.Stat(oldName); os.IsNotExist(err) {
-					os.Remove(oldName)
-				}
-				if writer.retentions > 0 {
+// queueSize sends size written to daemon
+func (writer *baseFileWriter) queueSize(size int) {
+	atomic.AddInt64(&writer.queuedSizes, 1)
+	writer.logSizeChan <- size
+}
 
-					for i := writer.retentions - 1; i > 0; i-- {
-						oldName = fmt.Sprintf("%s.%d", writer.currentFileName, i)
-						newName = fmt.Sprintf("%s.%d", writer.currentFileName, i+1)
-						os.Rename(oldName, newName)
-					}
-					os.Rename(writer.currentFileName, oldName)
+// handleSize sums up lines && sizes written, and does lines && size base
+// logrotate if needed
+func (writer *baseFileWriter) handleSize(size int) {
+	if !writer.sizeRotated && !writer.lineRotated {
+		return
+	}
 
-					writer.resetFile()
-				}
-			}
+	// TODO have any better solution?
+	// use func to ensure writer.lock will be released
+	writer.lock.Lock()
+	writer.currentSize += int64(size)
+	writer.currentLines++
+	writer.lock.Unlock()
+
+	if (writer.sizeRotated && writer.currentSize >= writer.rotateSize) || (writer.lineRotated && writer.currentLines >= writer.rotateLines) {
+		// need lines && size base logrotate
+		writer.rotate()
+	}
+}
+
+// Drain blocks until sizes queued before it are handled by daemon, then
+// flushes buffered logs and commits the file to disk, without closing the
+// writer. logs written during Drain are not waited for.
+// It is designed for checkpoints, e.g. ensure logs of a batch are durable
+// before acknowledging it
+func (writer *baseFileWriter) Drain() error {
+	target := atomic.LoadInt64(&writer.queuedSizes)
+	for atomic.LoadInt64(&writer.handledSizes) < target {
+		if writer.Closed() {
+			return ErrWriterClosed
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	writer.blog.flush()
+	return writer.file.Sync()
+}
+
+// Ping checks whether the log file is still writable
+func (writer *baseFileWriter) Ping() error {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	return writer.blog.Ping()
+}
+
+// Rotate forces a logrotate on demand without waiting for size, lines or
+// time thresholds. It flushes buffered logs, shifts archives as xxx.1, xxx.2
+// and reopens the log file, the same as size && lines base logrotate.
+// It is safe to be called while writing logs.
+func (writer *baseFileWriter) Rotate() error {
+	return writer.rotate()
+}
+
+// rotate shifts archives of current file and reopens it.
+// the newest archive is xxx.1 and the oldest one is xxx.retentions
+func (writer *baseFileWriter) rotate() (err error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	defer func() {
+		if nil != err {
+			internalError("rotate %s failed: %s", writer.currentFileName, err.Error())
+		}
+	}()
+
+	if writer.retentions < 1 {
+		return
+	}
+
+	writer.blog.flush()
+
+	var oldName, newName string
+	oldName = fmt.Sprintf("%s.%d", writer.currentFileName, writer.retentions)
+	for i := writer.retentions - 1; i > 0; i-- {
+		oldName = fmt.Sprintf("%s.%d", writer.currentFileName, i)
+		newName = fmt.Sprintf("%s.%d", writer.currentFileName, i+1)
+		os.Rename(oldName, newName)
+	}
+
+	if err = os.Rename(writer.currentFileName, oldName); nil != err {
+		return
+	}
+
+	if err = writer.reopen(); nil != err {
+		return
+	}
+
+	writer.prune()
+	return
+}
+
+// prune removes oldest archives until total size of archives is under
+// maxTotalSize. archives are files named with the file name as prefix,
+// except the current writing one.
+// writer.lock must be held by the caller
+func (writer *baseFileWriter) prune() {
+	if writer.maxTotalSize <= 0 {
+		return
+	}
+
+	names, err := filepath.Glob(writer.fileName + ".*")
+	if nil != err {
+		return
+	}
+
+	var total int64
+	archives := make(archiveList, 0, len(names))
+	for _, name := range names {
+		if name == writer.currentFileName {
+			continue
+		}
+
+		info, err := os.Stat(name)
+		if nil != err || info.IsDir() {
+			continue
+		}
+		total += info.Size()
+		archives = append(archives, archive{name: name, info: info})
+	}
+
+	// remove from the oldest one
+	sort.Sort(archives)
+	for _, a := range archives {
+		if total <= writer.maxTotalSize {
+			break
+		}
+
+		if nil == os.Remove(a.name) {
+			total -= a.info.Size()
 		}
 	}
 }
 
+// archive is a rotated log file
+type archive struct {
+	name string
+	info os.FileInfo
+}
+
+// archiveList sorts archives from the oldest to the newest
+type archiveList []archive
+
+func (list archiveList) Len() int      { return len(list) }
+func (list archiveList) Swap(i, j int) { list[i], list[j] = list[j], list[i] }
+func (list archiveList) Less(i, j int) bool {
+	ti, tj := list[i].info.ModTime(), list[j].info.ModTime()
+	if !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+
+	// xxx.2 is older than xxx.1 in size && lines base logrotate
+	ni, ei := strconv.Atoi(path.Ext(list[i].name)[1:])
+	nj, ej := strconv.Atoi(path.Ext(list[j].name)[1:])
+	if nil == ei && nil == ej {
+		return ni > nj
+	}
+	return list[i].name < list[j].name
+}
+
+// hookRotate consults rotateHook with total size written since last rotation
+// and resets the BLog with the io.Writer returned if rotation is needed
+func (writer *baseFileWriter) hookRotate(size int) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed || nil == writer.rotateHook {
+		return
+	}
+
+	writer.hookSize += int64(size)
+	if in, ok := writer.rotateHook(writer.hookSize); ok && nil != in {
+		writer.blog.resetFile(in)
+		writer.hookSize = 0
+	}
+}
+
 // resetFile reset current writing file
-func (writer *baseFileWriter) resetFile() {
+func (writer *baseFileWriter) resetFile() (err error) {
 	writer.lock.Lock()
 	defer writer.lock.Unlock()
 
+	if err = writer.reopen(); nil != err {
+		internalError("reopen %s failed: %s", writer.fileName, err.Error())
+	}
+	return
+}
+
+// reopen opens the log file again and resets the BLog with it.
+// writer.lock must be held by the caller
+func (writer *baseFileWriter) reopen() (err error) {
 	fileName := writer.fileName
 	if writer.timeRotated {
 		fileName = fmt.Sprintf("%s.%s", fileName, timeCache.Date())
 	}
-	file, _ := os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
-	writer.blog.resetFile(file)
+	file, err := newLogFile(fileName, writer.fileMode)
+	if nil != err {
+		return
+	}
+
+	if writer.audit {
+		// chain continues from lines already in the file
+		writer.blog.flush()
+	}
+	in, err := writer.input(file, fileName)
+	if nil != err {
+		file.Close()
+		return
+	}
+
+	writer.blog.resetFile(in)
 	writer.file.Close()
 	writer.file = file
+	writer.currentFileName = fileName
 
 	writer.currentSize = 0
 	writer.currentLines = 0
+
+	writer.bannerWritten = false
+	if writer.openBanner {
+		writer.writeBanner()
+	}
+
+	// reopened file inherits mode && owner
+	return writer.applyFileAttributes()
+}
+
+// writeBanner writes banner line to current file once, regardless of level.
+// writer.lock must be held by the caller
+func (writer *baseFileWriter) writeBanner() {
+	if writer.bannerWritten {
+		return
+	}
+
+	writer.blog.writeRaw(TRACE, fmt.Sprintf(BannerFormat, timeCache.Now().Format(time.RFC3339), os.Getpid()))
+	writer.bannerWritten = true
+}
+
+// applyFileAttributes sets mode && owner of current file, mode given while
+// opening file is masked by umask, so it is set again.
+// writer.lock must be held by the caller
+func (writer *baseFileWriter) applyFileAttributes() (err error) {
+	if err = writer.file.Chmod(writer.fileMode); nil != err {
+		return
+	}
+
+	if -1 != writer.uid || -1 != writer.gid {
+		err = writer.file.Chown(writer.uid, writer.gid)
+	}
+	return
 }
 
 // write writes pure message with specific level
@@ -281,21 +577,23 @@ func (writer *baseFileWriter) write(level LevelType, args ...interface{}) {
 	}
 
 	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+
 		// 异步调用log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, args ...interface{}) {
-					writer.hook.Fire(level, args...)
-				}(level, args...)
-
-			} else {
-				writer.hook.Fire(level, args...)
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, fmt.Sprint(args...))
 		}
 
 		// logrotate
 		if writer.sizeRotated || writer.lineRotated {
-			writer.logSizeChan <- size
+			writer.queueSize(size)
+		}
+
+		if nil != writer.rotateHook {
+			writer.hookRotate(size)
 		}
 	}()
 
@@ -310,32 +608,130 @@ func (writer *baseFileWriter) writef(level LevelType, format string, args ...int
 
 	// 统计日志size
 	var size = 0
+	// message body written, only kept for log hook
+	var message string
 
 	if writer.closed {
 		return
 	}
 
 	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+
 		// 异步调用log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, format string, args ...interface{}) {
-					writer.hook.Fire(level, fmt.Sprintf(format, args...))
-				}(level, format, args...)
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+		}
 
-			} else {
-				writer.hook.Fire(level, fmt.Sprintf(format, args...))
+		// logrotate
+		if writer.sizeRotated || writer.lineRotated {
+			writer.queueSize(size)
+		}
 
-			}
+		if nil != writer.rotateHook {
+			writer.hookRotate(size)
+		}
+	}()
+
+	if nil != writer.hook && !(level < writer.hookLevel) {
+		size, message = writer.blog.writefBody(level, format, args...)
+		return
+	}
+	size = writer.blog.writef(level, format, args...)
+}
+
+// writeLines writes a multi-line message with specific level
+func (writer *baseFileWriter) writeLines(level LevelType, message string) {
+	var size = 0
+
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
 		}
 
 		// logrotate
 		if writer.sizeRotated || writer.lineRotated {
-			writer.logSizeChan <- size
+			writer.queueSize(size)
+		}
+
+		if nil != writer.rotateHook {
+			writer.hookRotate(size)
 		}
 	}()
 
-	size = writer.blog.writef(level, format, args...)
+	size = writer.blog.writeLines(level, message)
+}
+
+// writeJSON writes fields as a json line with specific level
+func (writer *baseFileWriter) writeJSON(level LevelType, fields map[string]interface{}) {
+	var size = 0
+
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, jsonFields(fields))
+		}
+
+		// logrotate
+		if writer.sizeRotated || writer.lineRotated {
+			writer.queueSize(size)
+		}
+
+		if nil != writer.rotateHook {
+			writer.hookRotate(size)
+		}
+	}()
+
+	size = writer.blog.writeJSON(level, fields)
+}
+
+// writeRaw writes message verbatim with RawLevel
+func (writer *baseFileWriter) writeRaw(message string) {
+	var size = 0
+
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(RawLevel < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), RawLevel, message)
+		}
+
+		// logrotate
+		if writer.sizeRotated || writer.lineRotated {
+			writer.queueSize(size)
+		}
+
+		if nil != writer.rotateHook {
+			writer.hookRotate(size)
+		}
+	}()
+
+	size = writer.blog.writeRaw(RawLevel, message)
 }
 
 // Closed get writer status
@@ -395,6 +791,91 @@ func (writer *baseFileWriter) SetRetentions(retentions int64) {
 	writer.retentions = retentions
 }
 
+// MaxTotalSize get max total size of all archives
+func (writer *baseFileWriter) MaxTotalSize() int64 {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.maxTotalSize
+}
+
+// SetMaxTotalSize set max total size of all archives, oldest archives will be
+// removed after logrotate once total size exceeds it. It works along with
+// retentions, whichever removes more wins. 0 means no limit
+func (writer *baseFileWriter) SetMaxTotalSize(maxTotalSize int64) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if maxTotalSize < 0 {
+		return
+	}
+	writer.maxTotalSize = maxTotalSize
+}
+
+// SetRotateHook set a user defined logrotate policy, it is consulted after
+// every logging action. The io.Writer returned is owned by the caller and not
+// closed by the writer. Built-in logrotate reopens the log file and replaces
+// it, nil removes the hook
+func (writer *baseFileWriter) SetRotateHook(hook RotateHook) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.rotateHook = hook
+	writer.hookSize = 0
+}
+
+// FileMode get permission of log files
+func (writer *baseFileWriter) FileMode() os.FileMode {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.fileMode
+}
+
+// SetFileMode set permission of current and rotated log files
+func (writer *baseFileWriter) SetFileMode(mode os.FileMode) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	writer.fileMode = mode.Perm()
+	return writer.applyFileAttributes()
+}
+
+// SetOpenBanner toggle writing a banner line like
+// "==== log opened at 2016-07-17T08:05:02+08:00 pid=1234 ====" whenever log
+// file is opened, so that readers can find boundaries of restarts and
+// logrotate. it is written to current file at once if not yet
+func (writer *baseFileWriter) SetOpenBanner(banner bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return
+	}
+
+	writer.openBanner = banner
+	if banner {
+		writer.writeBanner()
+	}
+}
+
+// SetFileOwner set owner of current and rotated log files, -1 means not
+// changed. it fails when the process is not permitted to change owner, such
+// as not running as root, or not supported on the platform
+func (writer *baseFileWriter) SetFileOwner(uid, gid int) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	if err := writer.file.Chown(uid, gid); nil != err {
+		return err
+	}
+
+	writer.uid = uid
+	writer.gid = gid
+	return nil
+}
+
 // RotateSize get log rotate size
 func (writer *baseFileWriter) RotateSize() int64 {
 	writer.lock.RLock()
@@ -452,18 +933,351 @@ func (writer *baseFileWriter) SetColored(colored bool) {
 	initPrefix(colored)
 }
 
-// Level get log level
+// MultilinePrefix get whether every line of a multi-line message is prefixed
+func (writer *baseFileWriter) MultilinePrefix() bool {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.MultilinePrefix()
+}
+
+// SetMultilinePrefix toggle prefixing every line of a multi-line message
+func (writer *baseFileWriter) SetMultilinePrefix(multiline bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetMultilinePrefix(multiline)
+}
+
+// AtomicWrite get whether every line is written with a single Write call
+func (writer *baseFileWriter) AtomicWrite() bool {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.AtomicWrite()
+}
+
+// SetAtomicWrite toggle writing every line with a single Write call, so that
+// lines appended to the same file by multiple processes never interleave
+func (writer *baseFileWriter) SetAtomicWrite(atomic bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetAtomicWrite(atomic)
+}
+
+// SetReorderWindow hold lines for d and write them sorted by time
+func (writer *baseFileWriter) SetReorderWindow(d time.Duration) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetReorderWindow(d)
+}
+
+// SetDevMode toggle rendering json lines in human readable format on terminal
+func (writer *baseFileWriter) SetDevMode(dev bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetDevMode(dev)
+}
+
+// StrictFormat get whether it is in strict format mode
+func (writer *baseFileWriter) StrictFormat() bool {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.StrictFormat()
+}
+
+// SetStrictFormat toggle strict format mode
+func (writer *baseFileWriter) SetStrictFormat(strict bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetStrictFormat(strict)
+}
+
+// SetErrorHandler set handler called when writer meets an error
+func (writer *baseFileWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetErrorHandler(handler)
+}
+
+// SetWriteTimeout do nothing
+func (writer *baseFileWriter) SetWriteTimeout(d time.Duration) {
+	return
+}
+
+// Placeholder get placeholder character used in formatting
+func (writer *baseFileWriter) Placeholder() byte {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.Placeholder()
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func (writer *baseFileWriter) SetPlaceholder(placeholder byte) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPlaceholder(placeholder)
+}
+
+// SetEOL set character ending every line
+func (writer *baseFileWriter) SetEOL(eol byte) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetEOL(eol)
+}
+
+// SetEscape set escape character used in formatting
+func (writer *baseFileWriter) SetEscape(escape byte) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetEscape(escape)
+}
+
+// SetTimeFormat set layout of time prefix
+func (writer *baseFileWriter) SetTimeFormat(layout string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetTimeFormat(layout)
+}
+
+// SetTimeFormatPreset set time prefix as a preset format
+func (writer *baseFileWriter) SetTimeFormatPreset(preset TimePreset) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetTimeFormatPreset(preset)
+}
+
+// SetFraming set how every line is framed
+func (writer *baseFileWriter) SetFraming(framing Framing) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetFraming(framing)
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly
+func (writer *baseFileWriter) SetSmartTimeVerb(smart bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetSmartTimeVerb(smart)
+}
+
+// SetFormatter set formatter producing the whole line written for every message
+func (writer *baseFileWriter) SetFormatter(formatter Formatter) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetFormatter(formatter)
+}
+
+// SetNilString set the token nil args are written as in formatting
+func (writer *baseFileWriter) SetNilString(s string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetNilString(s)
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with recover
+func (writer *baseFileWriter) SetSafeStringer(safe bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetSafeStringer(safe)
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args
+func (writer *baseFileWriter) SetRawStringer(raw bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetRawStringer(raw)
+}
+
+// SetDebounce logs messages with format at most once per window
+func (writer *baseFileWriter) SetDebounce(format string, window time.Duration) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetDebounce(format, window)
+}
+
+// SetSamplerPolicy logs the first messages of every format in every tick, then only every thereafter-th of them
+func (writer *baseFileWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetSamplerPolicy(first, thereafter, tick)
+}
+
+// SetPrintSequence toggle writing an increasing sequence number ahead every
+// message, the counter keeps counting across logrotate
+func (writer *baseFileWriter) SetPrintSequence(sequence bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintSequence(sequence)
+}
+
+// SetPrintElapsed toggle writing milliseconds since start ahead every message
+func (writer *baseFileWriter) SetPrintElapsed(elapsed bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintElapsed(elapsed)
+}
+
+// SetSequenceWidth set width sequence numbers are zero padded to
+func (writer *baseFileWriter) SetSequenceWidth(width int) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetSequenceWidth(width)
+}
+
+// SetDefaultFields sets static fields prepended to every message
+func (writer *baseFileWriter) SetDefaultFields(fields map[string]string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetDefaultFields(fields)
+}
+
+// AddDefaultField appends a static field prepended to every message
+func (writer *baseFileWriter) AddDefaultField(key, value string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.AddDefaultField(key, value)
+}
+
+// separators return separators in use, default ones if the writer is closed
+func (writer *baseFileWriter) separators() *fieldSeparator {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	if writer.closed {
+		return defaultSeparator
+	}
+	return writer.blog.separators()
+}
+
+// SetFieldSeparator set what is written between structured fields
+func (writer *baseFileWriter) SetFieldSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+	return writer.blog.SetFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields
+func (writer *baseFileWriter) SetKVSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+	return writer.blog.SetKVSeparator(sep)
+}
+
+// SetPrintHostname toggle writing hostname ahead every message
+func (writer *baseFileWriter) SetPrintHostname(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintHostname(print)
+}
+
+// SetHostname overrides hostname written ahead messages
+func (writer *baseFileWriter) SetHostname(name string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetHostname(name)
+}
+
+// SetPrintPID toggle writing process id ahead every message
+func (writer *baseFileWriter) SetPrintPID(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintPID(print)
+}
+
+// SetPrintProgram toggle writing program name ahead every message
+func (writer *baseFileWriter) SetPrintProgram(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintProgram(print)
+}
+
+// SetPrintCallerFunc toggle writing the function calling the logger ahead every message
+func (writer *baseFileWriter) SetPrintCallerFunc(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintCallerFunc(print)
+}
+
+// SetPrintCallerLine toggle writing file:line calling the logger ahead every message
+func (writer *baseFileWriter) SetPrintCallerLine(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetPrintCallerLine(print)
+}
+
+// AddDropSubstring drops messages containing s
+func (writer *baseFileWriter) AddDropSubstring(s string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.AddDropSubstring(s)
+}
+
+// AddDropRegexp drops messages matching re
+func (writer *baseFileWriter) AddDropRegexp(re *regexp.Regexp) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.AddDropRegexp(re)
+}
+
+// Dropped return number of messages dropped by drop rules
+func (writer *baseFileWriter) Dropped() int64 {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.Dropped()
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately
+func (writer *baseFileWriter) FlushLevel() LevelType {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.FlushLevel()
+}
+
+// SetFlushLevel set the level at or above which messages are flushed immediately
+func (writer *baseFileWriter) SetFlushLevel(level LevelType) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetFlushLevel(level)
+}
+
+// SetBufferSize resize buffer keeping bytes buffered
+func (writer *baseFileWriter) SetBufferSize(size int) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetBufferSize(size)
+}
+
+// Level get log level
 func (writer *baseFileWriter) Level() LevelType {
 	writer.lock.RLock()
 	defer writer.lock.RUnlock()
 	return writer.blog.Level()
 }
 
+// Name get name of the writer, file path by default
+func (writer *baseFileWriter) Name() string {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	return writer.blog.Name()
+}
+
+// SetName set name of the writer
+func (writer *baseFileWriter) SetName(name string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.blog.SetName(name)
+}
+
 // SetLevel set logging level threshold
-func (writer *baseFileWriter) SetLevel(level LevelType) {
+func (writer *baseFileWriter) SetLevel(level LevelType) Writer {
 	writer.lock.Lock()
 	defer writer.lock.Unlock()
 	writer.blog.SetLevel(level)
+	return writer
 }
 
 // SetHook set hook for the base file writer
@@ -494,60 +1308,260 @@ func (writer *baseFileWriter) flush() {
 
 // Trace trace
 func (writer *baseFileWriter) Trace(args ...interface{}) {
+	if TRACE < CompileLevel {
+		return
+	}
+
 	writer.write(TRACE, args...)
 }
 
 // Tracef tracef
 func (writer *baseFileWriter) Tracef(format string, args ...interface{}) {
+	if TRACE < CompileLevel {
+		return
+	}
+
 	writer.writef(TRACE, format, args...)
 }
 
 // Debug debug
 func (writer *baseFileWriter) Debug(args ...interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
 	writer.write(DEBUG, args...)
 }
 
 // Debugf debugf
 func (writer *baseFileWriter) Debugf(format string, args ...interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
 	writer.writef(DEBUG, format, args...)
 }
 
+// DebugPretty debug pretty
+func (writer *baseFileWriter) DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
+	writer.writeLines(DEBUG, prettyFormat(v))
+}
+
+// ErrorStack writes message with stack trace of the caller, a stack
+// written before is written as its fingerprint
+func (writer *baseFileWriter) ErrorStack(message string) {
+	if ERROR < CompileLevel {
+		return
+	}
+
+	writer.writeLines(ERROR, writer.stacks.message(message))
+}
+
+// Entry starts building a structured message with specific level
+func (writer *baseFileWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
 // Info info
 func (writer *baseFileWriter) Info(args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
 	writer.write(INFO, args...)
 }
 
+// InfoJSON writes fields as a json line with info level
+func (writer *baseFileWriter) InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	writer.writeJSON(INFO, fields)
+}
+
+// WriteBatch writes messages with specific level contiguously, taking the
+// lock once, e.g. replaying buffered events. It return total size written
+func (writer *baseFileWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	if level < CompileLevel || !level.valid() || writer.closed {
+		return 0
+	}
+
+	for i, s := range writer.blog.writeBatch(level, messages) {
+		// message dropped by drop rules
+		if 0 == s {
+			continue
+		}
+		size += s
+
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, messages[i])
+		}
+
+		// logrotate
+		if writer.sizeRotated || writer.lineRotated {
+			writer.queueSize(s)
+		}
+
+		if nil != writer.rotateHook {
+			writer.hookRotate(s)
+		}
+	}
+	return
+}
+
+// LogAt writes message with specific level prefixed with time t instead of
+// now, e.g. replaying events recorded earlier
+func (writer *baseFileWriter) LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel || !level.valid() || writer.closed {
+		return
+	}
+
+	writer.logged(t, level, message, writer.blog.writeAt(t, level, message))
+}
+
+// LogfAt formats message with specific level prefixed with time t instead of
+// now
+func (writer *baseFileWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	if level < CompileLevel || !level.valid() || writer.closed {
+		return
+	}
+
+	size, message := writer.blog.writefAt(t, level, format, args...)
+	writer.logged(t, level, message, size)
+}
+
+// logged calls log hook with message written at t and counts size of it for
+// logrotate
+func (writer *baseFileWriter) logged(t time.Time, level LevelType, message string, size int) {
+	// message dropped by drop rules
+	if 0 == size {
+		return
+	}
+
+	// 异步调用log hook
+	if nil != writer.hook && !(level < writer.hookLevel) {
+		fireHook(writer.hook, writer.hookAsync, t, level, message)
+	}
+
+	// logrotate
+	if writer.sizeRotated || writer.lineRotated {
+		writer.queueSize(size)
+	}
+
+	if nil != writer.rotateHook {
+		writer.hookRotate(size)
+	}
+}
+
+// Raw writes message verbatim without time && level prefix
+func (writer *baseFileWriter) Raw(message string) {
+	if RawLevel < CompileLevel {
+		return
+	}
+
+	writer.writeRaw(message)
+}
+
 // Infof infof
 func (writer *baseFileWriter) Infof(format string, args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	writer.writef(INFO, format, args...)
+}
+
+// InfoSync writes message with info level and flushes it immediately
+func (writer *baseFileWriter) InfoSync(message string) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	writer.write(INFO, message)
+	writer.commit()
+}
+
+// InfofSync formats message with info level and flushes it immediately
+func (writer *baseFileWriter) InfofSync(format string, args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
 	writer.writef(INFO, format, args...)
+	writer.commit()
+}
+
+// commit flushes buffered logs, and commits the file to disk when SyncFsync
+func (writer *baseFileWriter) commit() {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+
+	if writer.closed {
+		return
+	}
+
+	writer.blog.flush()
+	if SyncFsync {
+		writer.file.Sync()
+	}
 }
 
 // Warn warn
 func (writer *baseFileWriter) Warn(args ...interface{}) {
+	if WARNING < CompileLevel {
+		return
+	}
+
 	writer.write(WARNING, args...)
 }
 
 // Warnf warn
 func (writer *baseFileWriter) Warnf(format string, args ...interface{}) {
+	if WARNING < CompileLevel {
+		return
+	}
+
 	writer.writef(WARNING, format, args...)
 }
 
 // Error error
 func (writer *baseFileWriter) Error(args ...interface{}) {
+	if ERROR < CompileLevel {
+		return
+	}
+
 	writer.write(ERROR, args...)
 }
 
 // Errorf errorf
 func (writer *baseFileWriter) Errorf(format string, args ...interface{}) {
+	if ERROR < CompileLevel {
+		return
+	}
+
 	writer.writef(ERROR, format, args...)
 }
 
 // Critical critical
 func (writer *baseFileWriter) Critical(args ...interface{}) {
+	if CRITICAL < CompileLevel {
+		return
+	}
+
 	writer.write(CRITICAL, args...)
 }
 
 // Criticalf criticalf
 func (writer *baseFileWriter) Criticalf(format string, args ...interface{}) {
+	if CRITICAL < CompileLevel {
+		return
+	}
+
 	writer.writef(CRITICAL, format, args...)
 }
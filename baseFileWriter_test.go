@@ -3,7 +3,15 @@
 package blog4go
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -32,11 +40,11 @@ func TestBaseFileWriterBasicOperation(t *testing.T) {
 	// test file writer hook
 	hook := NewMyHook()
 
-	blog.SetHook(hook)
-	blog.SetHookLevel(INFO)
+	blog().SetHook(hook)
+	blog().SetHookLevel(INFO)
 
-	blog.Debug("something")
-	blog.Debugf("%s", "something")
+	blog().Debug("something")
+	blog().Debugf("%s", "something")
 	// async
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
@@ -48,7 +56,7 @@ func TestBaseFileWriterBasicOperation(t *testing.T) {
 		t.Errorf("hook parameters wrong. level: %s, message: %s", hook.Level().String(), hook.Message())
 	}
 
-	blog.Info("yes")
+	blog().Info("yes")
 	// async
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
@@ -61,8 +69,8 @@ func TestBaseFileWriterBasicOperation(t *testing.T) {
 	}
 
 	// sync
-	blog.SetHookAsync(false)
-	blog.Warn("warn")
+	blog().SetHookAsync(false)
+	blog().Warn("warn")
 	if 2 != hook.Cnt() {
 		t.Error("hook not called")
 	}
@@ -72,53 +80,605 @@ func TestBaseFileWriterBasicOperation(t *testing.T) {
 	}
 
 	// test basic operations
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-	blog.flush()
-
-	blog.Colored()
-	blog.SetColored(true)
-	blog.TimeRotated()
-	blog.SetTimeRotated(true)
-	blog.Level()
-	blog.SetLevel(CRITICAL)
-	blog.Retentions()
-	blog.SetRetentions(0)
-	blog.SetRetentions(7)
-	blog.RotateLines()
-	blog.SetRotateLines(0)
-	blog.SetRotateLines(100000)
-	blog.RotateSize()
-	blog.SetRotateSize(0)
-	blog.SetRotateSize(1024 * 1024 * 500)
-
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s\\", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+	blog().flush()
+
+	blog().Colored()
+	blog().SetColored(true)
+	blog().TimeRotated()
+	blog().SetTimeRotated(true)
+	blog().Level()
+	blog().SetLevel(CRITICAL)
+	blog().Retentions()
+	blog().SetRetentions(0)
+	blog().SetRetentions(7)
+	blog().RotateLines()
+	blog().SetRotateLines(0)
+	blog().SetRotateLines(100000)
+	blog().RotateSize()
+	blog().SetRotateSize(0)
+	blog().SetRotateSize(1024 * 1024 * 500)
+
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s\\", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
 
 	// wait for timeRotate run
 	time.Sleep(1 * time.Second)
 
-	blog.Close()
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
+	blog().Close()
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+}
+
+// TestAtomicWriteHelperProcess is not a real test. It is the child process
+// started by TestBaseFileWriterAtomicWrite to append lines to a shared file.
+func TestAtomicWriteHelperProcess(t *testing.T) {
+	if "1" != os.Getenv("BLOG4GO_ATOMIC_HELPER") {
+		return
+	}
+
+	writer, err := newBaseFileWriter(os.Getenv("BLOG4GO_ATOMIC_FILE"), false)
+	if nil != err {
+		os.Exit(1)
+	}
+	writer.SetAtomicWrite(true)
+
+	// every line is longer than half of the buffer size, so that it straddles
+	// the buffer boundary without atomic mode
+	line := strings.Repeat(os.Getenv("BLOG4GO_ATOMIC_ID"), 3000)
+	for i := 0; i < 200; i++ {
+		writer.Infof("%s", line)
+	}
+	writer.Close()
+	os.Exit(0)
+}
+
+func TestBaseFileWriterAtomicWrite(t *testing.T) {
+	fileName := "/tmp/atomic.log"
+	defer func() {
+		// clean logs
+		_, err := exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+	os.Remove(fileName)
+
+	var wg sync.WaitGroup
+	ids := []string{"a", "b", "c", "d"}
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			cmd := exec.Command(os.Args[0], "-test.run=TestAtomicWriteHelperProcess")
+			cmd.Env = append(os.Environ(), "BLOG4GO_ATOMIC_HELPER=1", "BLOG4GO_ATOMIC_FILE="+fileName, "BLOG4GO_ATOMIC_ID="+id)
+			if err := cmd.Run(); nil != err {
+				t.Errorf("helper process %s failed. err: %s", id, err.Error())
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	file, err := os.Open(fileName)
+	if nil != err {
+		t.Fatal(err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 8192), 8192)
+	line := 0
+	for scanner.Scan() {
+		line++
+		lineStr := scanner.Text()
+		arrs := strings.Split(lineStr, "[INFO] ")
+		if 2 != len(arrs) || 3000 != len(arrs[1]) || strings.Repeat(arrs[1][:1], 3000) != arrs[1] {
+			t.Errorf("line %d is torn. length: %d", line, len(lineStr))
+			return
+		}
+	}
+
+	if err := scanner.Err(); nil != err {
+		t.Error(err.Error())
+	}
+
+	if len(ids)*200 != line {
+		t.Errorf("it loses %d lines.", len(ids)*200-line)
+	}
+}
+
+func TestBaseFileWriterRotate(t *testing.T) {
+	err := NewBaseFileWriter("/tmp/rotate.log", false)
+	if nil != err {
+		t.Errorf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	blog().Info("before rotate")
+	if err = Rotate(); nil != err {
+		t.Errorf("rotate failed. err: %s", err.Error())
+	}
+
+	content, err := ioutil.ReadFile("/tmp/rotate.log.1")
+	if nil != err {
+		t.Errorf("archive not found after rotate. err: %s", err.Error())
+	}
+	if !strings.Contains(string(content), "before rotate") {
+		t.Errorf("archive content wrong. content: %s", string(content))
+	}
+
+	blog().Info("after rotate")
+	Flush()
+
+	content, err = ioutil.ReadFile("/tmp/rotate.log")
+	if nil != err {
+		t.Errorf("new file not found after rotate. err: %s", err.Error())
+	}
+	if strings.Contains(string(content), "before rotate") || !strings.Contains(string(content), "after rotate") {
+		t.Errorf("new file content wrong. content: %s", string(content))
+	}
+
+	// rotate while writing
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			blog().Infof("concurrent %d", i)
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		if err = Rotate(); nil != err {
+			t.Errorf("rotate failed. err: %s", err.Error())
+		}
+	}
+	wg.Wait()
+
+	blog().Close()
+	if ErrWriterClosed != Rotate() {
+		t.Error("rotate a closed writer should fail.")
+	}
+}
+
+func TestBaseFileWriterMaxTotalSize(t *testing.T) {
+	err := NewBaseFileWriter("/tmp/quota.log", false)
+	if nil != err {
+		t.Errorf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	initPrefix(false)
+
+	// every archive is 100 bytes
+	message := strings.Repeat("q", 100-len(timeCache.Format())-len(INFO.prefix())-1)
+	var quota int64 = 250
+	SetMaxTotalSize(quota)
+	if quota != MaxTotalSize() {
+		t.Errorf("max total size wrong. expected: %d, got: %d", quota, MaxTotalSize())
+	}
+
+	for i := 0; i < 5; i++ {
+		blog().Info(message)
+		if err = Rotate(); nil != err {
+			t.Errorf("rotate failed. err: %s", err.Error())
+		}
+	}
+
+	var total int64
+	for i := 1; i <= 5; i++ {
+		if info, err := os.Stat(fmt.Sprintf("/tmp/quota.log.%d", i)); nil == err {
+			total += info.Size()
+		}
+	}
+
+	if total > quota {
+		t.Errorf("archives exceed quota. total: %d, quota: %d", total, quota)
+	}
+
+	// the newest archives should be kept
+	for i := 1; i <= 2; i++ {
+		if _, err := os.Stat(fmt.Sprintf("/tmp/quota.log.%d", i)); nil != err {
+			t.Errorf("newest archive %d should be kept. err: %s", i, err.Error())
+		}
+	}
+	if _, err := os.Stat("/tmp/quota.log.3"); nil == err {
+		t.Error("oldest archives should be removed.")
+	}
+}
+
+func TestBaseFileWriterFlushLevel(t *testing.T) {
+	err := NewBaseFileWriter("/tmp/flush.log", false)
+	if nil != err {
+		t.Errorf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	if FlushLevel().valid() {
+		t.Error("flush level should be disabled by default.")
+	}
+
+	SetFlushLevel(ERROR)
+	if ERROR != FlushLevel() {
+		t.Errorf("flush level wrong. level: %s", FlushLevel().String())
+	}
+
+	blog().Info("buffered")
+	content, _ := ioutil.ReadFile("/tmp/flush.log")
+	if strings.Contains(string(content), "buffered") {
+		t.Error("info message should keep buffered.")
+	}
+
+	blog().Errorf("%s", "flushed")
+	content, _ = ioutil.ReadFile("/tmp/flush.log")
+	if !strings.Contains(string(content), "buffered") || !strings.Contains(string(content), "flushed") {
+		t.Errorf("error message should be flushed. content: %s", string(content))
+	}
+}
+
+func TestBaseFileWriterRotateHook(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/hook.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	// rotate every 3 lines
+	var lines int
+	var lastSize int64
+	parts := make([]*bytes.Buffer, 0)
+	writer.SetRotateHook(func(currentSize int64) (io.Writer, bool) {
+		lines++
+		if 1 != lines && currentSize <= lastSize {
+			t.Errorf("current size should grow between rotations. size: %d, last size: %d", currentSize, lastSize)
+		}
+		lastSize = currentSize
+
+		if 3 != lines {
+			return nil, false
+		}
+
+		lines = 0
+		part := new(bytes.Buffer)
+		parts = append(parts, part)
+		return part, true
+	})
+
+	for i := 0; i < 7; i++ {
+		writer.Infof("line %d", i)
+	}
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/hook.log")
+	if 3 != strings.Count(string(content), "\n") || !strings.Contains(string(content), "line 2") {
+		t.Errorf("log file should keep the first 3 lines. content: %s", string(content))
+	}
+
+	if 2 != len(parts) {
+		t.Fatalf("rotate hook should rotate twice. rotations: %d", len(parts))
+	}
+
+	if 3 != strings.Count(parts[0].String(), "\n") || !strings.Contains(parts[0].String(), "line 5") {
+		t.Errorf("first part content wrong. content: %s", parts[0].String())
+	}
+
+	if 1 != strings.Count(parts[1].String(), "\n") || !strings.Contains(parts[1].String(), "line 6") {
+		t.Errorf("second part content wrong. content: %s", parts[1].String())
+	}
+}
+
+func TestBaseFileWriterAppendAfterRotate(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/append.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	// a second writer holding the file, like another process does
+	other, err := openLogFile("/tmp/append.log", DefaultFileMode)
+	if nil != err {
+		t.Fatalf("open file failed. err: %s", err.Error())
+	}
+	defer other.Close()
+
+	writer.Info("first")
+	writer.flush()
+	other.WriteString("other first\n")
+
+	if err = writer.Rotate(); nil != err {
+		t.Fatalf("rotate failed. err: %s", err.Error())
+	}
+	other.WriteString("other archived\n")
+
+	// the second writer opens the reopened file too
+	another, err := openLogFile("/tmp/append.log", DefaultFileMode)
+	if nil != err {
+		t.Fatalf("open file failed. err: %s", err.Error())
+	}
+	defer another.Close()
+
+	another.WriteString("another first\n")
+	writer.Info("second")
+	writer.flush()
+	another.WriteString("another second\n")
+	writer.Info("third")
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/append.log.1")
+	for _, expected := range []string{"first", "other first", "other archived"} {
+		if !strings.Contains(string(content), expected+"\n") {
+			t.Errorf("archive content overwritten. expected: %s, content: %s", expected, string(content))
+		}
+	}
+
+	content, _ = ioutil.ReadFile("/tmp/append.log")
+	for _, expected := range []string{"another first", "second", "another second", "third"} {
+		if !strings.Contains(string(content), expected+"\n") {
+			t.Errorf("file content overwritten. expected: %s, content: %s", expected, string(content))
+		}
+	}
+}
+
+func TestBaseFileWriterFileMode(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/mode.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	if DefaultFileMode != writer.FileMode() {
+		t.Errorf("default file mode wrong. mode: %s", writer.FileMode())
+	}
+
+	if err = writer.SetFileMode(0600); nil != err {
+		t.Fatalf("set file mode failed. err: %s", err.Error())
+	}
+
+	info, _ := os.Stat("/tmp/mode.log")
+	if os.FileMode(0600) != info.Mode().Perm() {
+		t.Errorf("file mode wrong. mode: %s", info.Mode().Perm())
+	}
+
+	// rotated files inherit mode
+	writer.Info("before rotate")
+	if err = writer.Rotate(); nil != err {
+		t.Fatalf("rotate failed. err: %s", err.Error())
+	}
+
+	info, _ = os.Stat("/tmp/mode.log")
+	if os.FileMode(0600) != info.Mode().Perm() {
+		t.Errorf("rotated file mode wrong. mode: %s", info.Mode().Perm())
+	}
+
+	if err = writer.SetFileOwner(os.Getuid(), os.Getgid()); nil != err {
+		t.Errorf("set file owner to current user failed. err: %s", err.Error())
+	}
+
+	if 0 != os.Getuid() {
+		if err = writer.SetFileOwner(0, 0); nil == err {
+			t.Error("chown to root should fail when not running as root.")
+		}
+	}
+}
+
+func TestBaseFileWriterOpenBanner(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/banner.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.SetLevel(ERROR)
+	writer.SetOpenBanner(true)
+	writer.SetOpenBanner(true)
+	writer.Error("first")
+	if err = writer.Rotate(); nil != err {
+		t.Fatalf("rotate failed. err: %s", err.Error())
+	}
+	writer.Error("second")
+	writer.flush()
+
+	banner := fmt.Sprintf("pid=%d ====", os.Getpid())
+	for fileName, message := range map[string]string{"/tmp/banner.log.1": "first", "/tmp/banner.log": "second"} {
+		content, _ := ioutil.ReadFile(fileName)
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		if 2 != len(lines) || !strings.HasPrefix(lines[0], "==== log opened at ") || !strings.HasSuffix(lines[0], banner) {
+			t.Errorf("file should start with a single banner line. file: %s, content: %s", fileName, string(content))
+		}
+
+		if !strings.HasSuffix(lines[len(lines)-1], message) {
+			t.Errorf("message should follow banner. file: %s, content: %s", fileName, string(content))
+		}
+	}
+}
+
+func TestBaseFileWriterDrain(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/drain.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	// sizes are summed up by daemon in size base logrotate
+	writer.SetRotateSize(100 * MB)
+
+	for i := 0; i < 1000; i++ {
+		writer.Infof("line %d", i)
+	}
+
+	if err = writer.Drain(); nil != err {
+		t.Fatalf("drain failed. err: %s", err.Error())
+	}
+
+	content, _ := ioutil.ReadFile("/tmp/drain.log")
+	if 1000 != strings.Count(string(content), "\n") {
+		t.Errorf("logs should be flushed after drain. lines: %d", strings.Count(string(content), "\n"))
+	}
+
+	writer.lock.RLock()
+	currentSize, currentLines := writer.currentSize, writer.currentLines
+	writer.lock.RUnlock()
+	if int64(len(content)) != currentSize || 1000 != currentLines {
+		t.Errorf("sizes should be handled after drain. size: %d, lines: %d", currentSize, currentLines)
+	}
+
+	writer.Close()
+	if ErrWriterClosed != writer.Drain() {
+		t.Error("drain a closed writer should fail.")
+	}
+}
+
+func TestBaseFileWriterPing(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/ping.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.Info("before ping")
+	if err = writer.Ping(); nil != err {
+		t.Fatalf("ping a writable file failed. err: %s", err.Error())
+	}
+
+	content, _ := ioutil.ReadFile("/tmp/ping.log")
+	if 1 != strings.Count(string(content), "\n") || !strings.HasSuffix(string(content), "before ping\n") {
+		t.Errorf("ping should flush logs without writing anything. content: %s", string(content))
+	}
+
+	readOnly, err := os.Open("/tmp/ping.log")
+	if nil != err {
+		t.Fatalf("open read only file failed. err: %s", err.Error())
+	}
+	defer readOnly.Close()
+
+	writer.blog.resetFile(readOnly)
+	if nil == writer.Ping() {
+		t.Error("ping a read only file should fail.")
+	}
+
+	writer.Close()
+	if ErrWriterClosed != writer.Ping() {
+		t.Error("ping a closed writer should fail.")
+	}
+}
+
+func TestBaseFileWriterInfoSync(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/infosync.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		SyncFsync = false
+		writer.Close()
+		exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+	}()
+
+	writer.Info("buffered")
+	writer.InfoSync("payment committed")
+
+	data, _ := ioutil.ReadFile("/tmp/infosync.log")
+	if !strings.Contains(string(data), "payment committed") {
+		t.Errorf("InfoSync line should be on disk immediately. got: %q", string(data))
+	}
+	if !strings.Contains(string(data), "buffered") {
+		t.Errorf("lines buffered before InfoSync should be flushed too. got: %q", string(data))
+	}
+
+	SyncFsync = true
+	writer.Info("buffered again")
+	writer.InfofSync("order %d shipped", 42)
+
+	data, _ = ioutil.ReadFile("/tmp/infosync.log")
+	if !strings.HasSuffix(string(data), "order 42 shipped\n") {
+		t.Errorf("InfofSync line should be on disk immediately. got: %q", string(data))
+	}
 }
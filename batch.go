@@ -0,0 +1,28 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+// writeBatch writes messages with specific level under one lock, so that they
+// are contiguous. It return size written of every message, 0 for the ones
+// dropped by drop rules
+func (blog *BLog) writeBatch(level LevelType, messages []string) (sizes []int) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	sizes = make([]int, len(messages))
+	for i, message := range messages {
+		sizes[i] = blog.writeMessage(level, message)
+	}
+	return
+}
+
+// WriteBatch static function for WriteBatch
+func WriteBatch(level LevelType, messages []string) int {
+	if level < CompileLevel {
+		return 0
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	return holder.writer.WriteBatch(level, messages)
+}
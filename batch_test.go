@@ -0,0 +1,89 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteBatch(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+
+	messages := make([]string, 100)
+	for i := range messages {
+		messages[i] = fmt.Sprintf("batch %d", i)
+	}
+
+	size := writer.WriteBatch(WARNING, messages)
+	writer.flush()
+	if size != buf.Len() {
+		t.Errorf("size of batch wrong. expected: %d, got: %d", buf.Len(), size)
+	}
+
+	// batch written with others at the same time stays contiguous
+	buf.Reset()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			writer.Info("single")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		writer.WriteBatch(WARNING, messages)
+	}()
+	wg.Wait()
+	writer.flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	first := -1
+	for i, line := range lines {
+		if strings.HasSuffix(line, "[WARN] batch 0") {
+			first = i
+			break
+		}
+	}
+	if first < 0 || first+len(messages) > len(lines) {
+		t.Fatalf("batch should be written. lines: %d, first: %d", len(lines), first)
+	}
+	for i, message := range messages {
+		if !strings.HasSuffix(lines[first+i], "[WARN] "+message) {
+			t.Fatalf("batch should be contiguous. line %d: %s", first+i, lines[first+i])
+		}
+	}
+}
+
+func BenchmarkWriteBatch(b *testing.B) {
+	writer := newCaptureWriter(new(bytes.Buffer))
+	messages := make([]string, 100)
+	for i := range messages {
+		messages[i] = fmt.Sprintf("batch %d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		writer.WriteBatch(INFO, messages)
+	}
+}
+
+func BenchmarkWriteIndividually(b *testing.B) {
+	writer := newCaptureWriter(new(bytes.Buffer))
+	messages := make([]string, 100)
+	for i := range messages {
+		messages[i] = fmt.Sprintf("batch %d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, message := range messages {
+			writer.Info(message)
+		}
+	}
+}
@@ -7,12 +7,15 @@ package blog4go
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -25,18 +28,20 @@ const (
 )
 
 var (
-	// blog is the singleton instance use for blog.write/writef
-	blog Writer
-
 	// global mutex log used for singlton
 	singltonLock *sync.Mutex
 
 	// DefaultBufferSize bufio buffer size
 	DefaultBufferSize = 4096 // default memory page size
+	// SyncFsync makes InfoSync && InfofSync of file writers commit the file
+	// to disk besides flushing, default false
+	SyncFsync = false
 	// ErrInvalidFormat invalid format error
 	ErrInvalidFormat = errors.New("Invalid format type")
 	// ErrAlreadyInit show that blog is already initialized once
 	ErrAlreadyInit = errors.New("blog4go has been already initialized")
+	// ErrWriterClosed show that the writer is already closed
+	ErrWriterClosed = errors.New("writer has been already closed")
 )
 
 // Writer interface is a common definition of any writers in this package.
@@ -53,8 +58,13 @@ type Writer interface {
 	// Close do anything end before program end
 	Close()
 
-	// SetLevel set logging level threshold
-	SetLevel(level LevelType)
+	// Name get name of the writer, file path or "console" by default
+	Name() string
+	// SetName set name of the writer, used to tell writers apart
+	SetName(name string)
+
+	// SetLevel set logging level threshold and return the writer for chaining
+	SetLevel(level LevelType) Writer
 	// Level get log level
 	Level() LevelType
 
@@ -74,9 +84,39 @@ type Writer interface {
 	Critical(args ...interface{})
 	Criticalf(format string, args ...interface{})
 
+	// pretty print a value as a multi-line indented json block
+	DebugPretty(v interface{})
+
+	// error with stack trace of the caller, repeated stacks as fingerprints
+	ErrorStack(message string)
+
+	// build a structured message with typed fields
+	Entry(level LevelType) *Entry
+
+	// write fields as a json line
+	InfoJSON(fields map[string]interface{})
+
+	// write message verbatim without time && level prefix
+	Raw(message string)
+
+	// write messages contiguously under one lock, return size written
+	WriteBatch(level LevelType, messages []string) int
+
+	// write message prefixed with time t instead of now, e.g. replaying
+	LogAt(t time.Time, level LevelType, message string)
+	LogfAt(t time.Time, level LevelType, format string, args ...interface{})
+
+	// info flushed immediately regardless of flush level
+	InfoSync(message string)
+	InfofSync(format string, args ...interface{})
+
 	// flush log to disk
 	flush()
 
+	// snapshot and restore all settings
+	Config() WriterConfig
+	ApplyConfig(cfg WriterConfig) error
+
 	// hook
 	SetHook(hook Hook)
 	SetHookLevel(level LevelType)
@@ -91,8 +131,59 @@ type Writer interface {
 	RotateLines() int
 	SetRetentions(retentions int64)
 	Retentions() int64
+	Rotate() error
+	Drain() error
+	Ping() error
+	SetMaxTotalSize(maxTotalSize int64)
+	MaxTotalSize() int64
+	SetRotateHook(hook RotateHook)
+	SetFileMode(mode os.FileMode) error
+	FileMode() os.FileMode
+	SetFileOwner(uid, gid int) error
+	SetOpenBanner(banner bool)
 	SetColored(colored bool)
 	Colored() bool
+	SetAtomicWrite(atomic bool)
+	SetReorderWindow(d time.Duration)
+	SetDevMode(dev bool)
+	AtomicWrite() bool
+	SetMultilinePrefix(multiline bool)
+	MultilinePrefix() bool
+	SetFlushLevel(level LevelType)
+	SetBufferSize(size int)
+	FlushLevel() LevelType
+	SetPlaceholder(placeholder byte)
+	Placeholder() byte
+	SetEOL(eol byte)
+	SetEscape(escape byte)
+	SetNilString(s string)
+	SetSafeStringer(safe bool)
+	SetRawStringer(raw bool)
+	SetDebounce(format string, window time.Duration)
+	SetSamplerPolicy(first, thereafter int, tick time.Duration)
+	SetPrintSequence(sequence bool)
+	SetPrintElapsed(elapsed bool)
+	SetSequenceWidth(width int)
+	SetDefaultFields(fields map[string]string)
+	AddDefaultField(key, value string)
+	SetPrintHostname(print bool)
+	SetHostname(name string)
+	SetPrintPID(print bool)
+	SetPrintProgram(print bool)
+	SetPrintCallerFunc(print bool)
+	SetPrintCallerLine(print bool)
+	AddDropSubstring(s string)
+	AddDropRegexp(re *regexp.Regexp)
+	Dropped() int64
+	SetTimeFormat(layout string)
+	SetTimeFormatPreset(preset TimePreset)
+	SetFraming(framing Framing)
+	SetSmartTimeVerb(smart bool)
+	SetFormatter(formatter Formatter)
+	SetStrictFormat(strict bool)
+	StrictFormat() bool
+	SetErrorHandler(handler ErrorHandler)
+	SetWriteTimeout(d time.Duration)
 }
 
 func init() {
@@ -105,7 +196,7 @@ func init() {
 func NewWriterFromConfigAsFile(configFile string) (err error) {
 	singltonLock.Lock()
 	defer singltonLock.Unlock()
-	if nil != blog {
+	if nil != blog() {
 		return ErrAlreadyInit
 	}
 
@@ -120,6 +211,7 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 	}
 
 	multiWriter := new(MultiWriter)
+	multiWriter.name = "multi"
 
 	multiWriter.level = DEBUG
 	if level := LevelFromString(config.MinLevel); level.valid() {
@@ -127,14 +219,24 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 	}
 
 	multiWriter.closed = false
+	multiWriter.flushLevel = noFlushLevel
+	multiWriter.placeholder = PLACEHOLDER
+	multiWriter.escape = ESCAPE
+	multiWriter.eol = EOL
+	multiWriter.format = newFormatBLog()
+	multiWriter.fileMode = DefaultFileMode
 	multiWriter.writers = make(map[LevelType]Writer)
 
+	if config.MaxOpenFiles > 0 {
+		SetMaxOpenFiles(config.MaxOpenFiles)
+	}
+
 	for _, filter := range config.Filters {
 		var rotate = false
 		var timeRotate = false
 		var isSocket = false
 
-		var f *os.File
+		var f *logFile
 		var blog *BLog
 		var fileLock *sync.RWMutex
 
@@ -145,11 +247,12 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 			filePath = filter.File.Path
 			rotate = false
 
-			f, err = os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+			f, err = newLogFile(filePath, DefaultFileMode)
 			if nil != err {
 				return err
 			}
 			blog = NewBLog(f)
+			blog.name = filePath
 			fileLock = new(sync.RWMutex)
 		} else if (rotateFile{}) != filter.RotateFile {
 			// file need logrotate
@@ -161,11 +264,12 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 			if timeRotate {
 				fileName = fmt.Sprintf("%s.%s", fileName, timeCache.Date())
 			}
-			f, err = os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+			f, err = newLogFile(fileName, DefaultFileMode)
 			if nil != err {
 				return err
 			}
 			blog = NewBLog(f)
+			blog.name = filePath
 			fileLock = new(sync.RWMutex)
 		} else if (socket{}) != filter.Socket {
 			isSocket = true
@@ -208,10 +312,16 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 					writer.SetRotateLines(filter.RotateFile.RotateLines)
 					writer.SetRetentions(filter.RotateFile.Retentions)
 				} else {
+					writer.Close()
 					return ErrInvalidRotateType
 				}
+				writer.SetMaxTotalSize(filter.RotateFile.MaxTotalSize)
 			}
 
+			// file and BLog opened by newBaseFileWriter are replaced by the
+			// shared ones, the BLog is closed to release the time cache
+			writer.blog.Close()
+			writer.file.Close()
 			writer.file = f
 			writer.blog = blog
 			writer.lock = fileLock
@@ -222,12 +332,20 @@ func NewWriterFromConfigAsFile(configFile string) (err error) {
 		}
 	}
 
-	blog = multiWriter
+	if "" != config.TimeFormat {
+		multiWriter.SetTimeFormat(config.TimeFormat)
+	}
+
+	setBlog(multiWriter)
 	return
 }
 
 // BLog struct is a threadsafe log writer inherit bufio.Writer
 type BLog struct {
+	// last sequence number written, accessed atomically, keep it first for
+	// 64-bit alignment
+	seq uint64
+
 	// logging level
 	// every message level exceed this level will be written
 	level LevelType
@@ -235,58 +353,367 @@ type BLog struct {
 	// input io
 	in io.Writer
 
-	// bufio.Writer object of the input io
+	// bufio.Writer object of the input io, writing through relay
 	writer *bufio.Writer
+	relay  *relayWriter
 
 	// exclusive lock while calling write function of bufio.Writer
-	lock *sync.Mutex
+	// it is a nopLocker when not in thread safe mode
+	lock sync.Locker
+	// thread safe mode, default true
+	threadSafe bool
+
+	// atomic mode, every line is assembled in line buffer first and then
+	// written to input io with a single Write call, default false
+	atomic bool
+	// line buffer used in atomic mode
+	line *bytes.Buffer
+
+	// multiline prefix mode, every line of message gets prefixed, default false
+	multiline bool
+	// lineWriter used in multiline prefix mode
+	multi *multilineWriter
+
+	// shadow copy of buffered bytes in snapshot mode, nil if disabled
+	shadow *bytes.Buffer
+	// lineWriter copying lines into shadow buffer in snapshot mode
+	tee *teeLineWriter
+
+	// placeholder and escape characters used in writef, and character
+	// ending every line, default PLACEHOLDER, ESCAPE and EOL
+	placeholder byte
+	escape      byte
+	eol         byte
+
+	// formatter of time prefix, nil means PrefixTimeFormat
+	timeFormat *timeFormatter
+
+	// time of the line being written by LogAt, zero means now
+	at time.Time
+
+	// how every line is framed, default FramingNone
+	framing Framing
+
+	// formatter producing whole lines, nil means the built-in format.
+	// message is reused for formatting messages passed to formatter
+	formatter Formatter
+	message   *bytes.Buffer
+
+	// time.Time and time.Duration args of %v and %s are formatted like
+	// time prefix and in compact form in smart time mode, default false.
+	// timeBuf is reused for formatting times
+	smartTime bool
+	timeBuf   []byte
+
+	// nil args in writef are written as nilString if replaceNil is true
+	// default false, which keeps fmt behavior
+	nilString  string
+	replaceNil bool
+
+	// String and Error methods of args are called with recover in safe
+	// stringer mode, and bypassed in raw stringer mode, both default false
+	safeStringer bool
+	rawStringer  bool
+
+	// encoder used in writeJSON
+	json *jsonEncoder
+	// json lines are rendered in human readable format in dev mode
+	dev bool
+
+	// static fields written ahead every message
+	defaults defaultFields
+
+	// the calling function and file:line are written ahead every message in
+	// caller modes, both default false. callerBuf is reused for formatting
+	// and callerLen is bytes written in the last message
+	callerFunc bool
+	callerLine bool
+	callerBuf  []byte
+	callerLen  int
+
+	// debounce rules keyed by format string, nil if none
+	debounces map[string]*debounceRule
+	// sampling policy of messages, nil if none
+	sampler *sampler
+	// lines held and sorted by time in reorder mode, nil if disabled
+	reorder *reorderBuffer
+
+	// sequence mode, an increasing number is written ahead every message,
+	// default false. seqLen is bytes of the last number written
+	sequence bool
+	seqWidth int
+	seqBuf   []byte
+	seqLen   int
+
+	// elapsed mode, milliseconds since start are written ahead every
+	// message, default false. elapsedLen is bytes of the last one written
+	elapsed    bool
+	elapsedBuf []byte
+	elapsedLen int
+
+	// rules dropping messages, nil if none. lines are assembled in line
+	// buffer first when there are rules, mark is where message begins in
+	// the line, -1 means the line is never dropped
+	drops *dropRules
+	mark  int
+
+	// strict mode, mistakes in format string are written as error markers
+	// and reported to errorHandler, default false
+	strict bool
+	// errorHandler is called when BLog meets an error
+	errorHandler ErrorHandler
+	// first error writing to input io, see Err
+	err error
+	// guards lines written by writef against short writes
+	short shortWriter
+
+	// messages at or above flushLevel are flushed right after written
+	// default noFlushLevel, which means disabled
+	flushLevel LevelType
+
+	// name tells writers apart, such as file path
+	name string
 
 	// closed tag
 	closed bool
 }
 
+// nopLocker is a sync.Locker does nothing, used in not thread safe mode
+type nopLocker struct{}
+
+// Lock do nothing
+func (l nopLocker) Lock() {}
+
+// Unlock do nothing
+func (l nopLocker) Unlock() {}
+
+// lineWriter is the set of bufio.Writer functions used while formatting a
+// line. bytes.Buffer implements it as well, so that a line can be formatted
+// into the line buffer in atomic mode
+type lineWriter interface {
+	Write(p []byte) (int, error)
+	WriteString(s string) (int, error)
+	WriteByte(c byte) error
+}
+
 // NewBLog create a BLog instance and return the pointer of it.
 // fileName must be an absolute path to the destination log file
 func NewBLog(in io.Writer) (blog *BLog) {
+	return newBLogSize(in, DefaultBufferSize)
+}
+
+// newBLogSize create a BLog instance buffering at most size bytes
+func newBLogSize(in io.Writer, size int) (blog *BLog) {
 	blog = new(BLog)
 	blog.in = in
 	blog.level = TRACE
 	blog.lock = new(sync.Mutex)
+	blog.threadSafe = true
 	blog.closed = false
 
-	blog.writer = bufio.NewWriterSize(in, DefaultBufferSize)
+	blog.atomic = false
+	blog.dev = false
+	blog.line = new(bytes.Buffer)
+	blog.multiline = false
+	blog.multi = new(multilineWriter)
+	blog.json = newJSONEncoder()
+	blog.flushLevel = noFlushLevel
+	blog.placeholder = PLACEHOLDER
+	blog.escape = ESCAPE
+	blog.eol = EOL
+	blog.timeFormat = nil
+	blog.formatter = nil
+	blog.message = new(bytes.Buffer)
+	blog.smartTime = false
+	blog.nilString = ""
+	blog.replaceNil = false
+	blog.safeStringer = false
+	blog.rawStringer = false
+	blog.sequence = false
+	blog.elapsed = false
+	blog.callerFunc = false
+	blog.callerLine = false
+	blog.seqWidth = DefaultSequenceWidth
+	blog.strict = false
+	blog.errorHandler = nil
+
+	blog.relay = &relayWriter{w: in}
+	blog.writer = bufio.NewWriterSize(blog.relay, size)
+
+	// time cache is shared by every BLog
+	timeCache.acquire()
 	return
 }
 
+// begin returns where a new line should be formatted into
+func (blog *BLog) begin() lineWriter {
+	if blog.atomic || nil != blog.drops || nil != blog.reorder {
+		blog.line.Reset()
+		blog.mark = 0
+		return blog.line
+	}
+
+	return blog.buffered()
+}
+
+// buffered returns the buffer lines are written into
+func (blog *BLog) buffered() lineWriter {
+	if nil != blog.tee {
+		return blog.tee
+	}
+	return blog.writer
+}
+
+// end writes the assembled line to input io with a single Write call
+// in atomic mode, and flushes the buffer when level reaches flushLevel.
+// It return true if the line is dropped by drop rules
+func (blog *BLog) end(level LevelType) bool {
+	if nil != blog.drops && 0 <= blog.mark && blog.drops.drop(bytes.TrimSuffix(blog.line.Bytes()[blog.mark:], []byte{blog.eol})) {
+		blog.unwriteSequence()
+		return true
+	}
+
+	if nil != blog.reorder {
+		blog.hold(blog.line.Bytes())
+	} else if blog.atomic {
+		blog.in.Write(blog.line.Bytes())
+	} else if nil != blog.drops {
+		blog.buffered().Write(blog.line.Bytes())
+	}
+
+	if level >= blog.flushLevel {
+		blog.writer.Flush()
+	}
+	return false
+}
+
 // write writes pure message with specific level
 func (blog *BLog) write(level LevelType, args ...interface{}) int {
 	blog.lock.Lock()
 	defer blog.lock.Unlock()
 
-	// 统计日志size
-	var size = 0
-	format := fmt.Sprint(args...)
+	return blog.writeMessage(level, fmt.Sprint(blog.stringerArgs(args)...))
+}
 
-	blog.writer.Write(timeCache.Format())
-	blog.writer.WriteString(level.prefix())
-	blog.writer.WriteString(format)
-	blog.writer.WriteByte(EOL)
+// writeMessage writes message with specific level under lock
+func (blog *BLog) writeMessage(level LevelType, format string) (size int) {
+	if nil != blog.formatter {
+		return blog.writeFormatted(level, format)
+	}
 
-	size = len(timeCache.Format()) + len(level.prefix()) + len(format) + 1
+	// 统计日志size
+	w := blog.begin()
+	defer func() {
+		if blog.end(level) {
+			size = 0
+		}
+	}()
+
+	frame := blog.frame(level)
+	w.Write(frame)
+	ts := blog.timestamp()
+	w.Write(ts)
+	prefix := level.prefixBytes()
+	w.Write(prefix)
+	blog.body(w, level, ts).WriteString(format)
+	w.WriteByte(blog.eol)
+
+	size = len(frame) + len(ts) + len(prefix) + len(format) + 1 + blog.extra()
 	return size
 }
 
 // write formats message with specific level and write it
-func (blog *BLog) writef(level LevelType, format string, args ...interface{}) int {
+func (blog *BLog) writef(level LevelType, format string, args ...interface{}) (size int) {
 	// 格式化构造message
 	// 边解析边输出
 	// 使用 % 作占位符
 	blog.lock.Lock()
 	defer blog.lock.Unlock()
 
+	return blog.writefLocked(level, format, args...)
+}
+
+// writefBody is writef returning the message body written as well, for
+// log hooks to be called with the same body
+func (blog *BLog) writefBody(level LevelType, format string, args ...interface{}) (size int, message string) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	return blog.writefBodyLocked(level, format, args...)
+}
+
+// writefBodyLocked is writefBody called under lock of BLog
+func (blog *BLog) writefBodyLocked(level LevelType, format string, args ...interface{}) (size int, message string) {
+	if blog.suppressed(level, format) {
+		return 0, ""
+	}
+
+	message = blog.sprintfLocked(format, args...)
+	return blog.writeMessage(level, message), message
+}
+
+// suppressed determines whether message of format is dropped by debounce or
+// sampler, it is called under lock of BLog
+func (blog *BLog) suppressed(level LevelType, format string) bool {
+	if nil != blog.debounces && blog.debounced(level, format) {
+		CountDropped(DropDeduped)
+		return true
+	}
+
+	if nil != blog.sampler && blog.sampler.sampled(format) {
+		CountDropped(DropSampled)
+		return true
+	}
+	return false
+}
+
+// writefLocked is writef called under lock of BLog
+func (blog *BLog) writefLocked(level LevelType, format string, args ...interface{}) (size int) {
+	if blog.suppressed(level, format) {
+		return 0
+	}
+
+	// 没有参数时无需解析，按原样输出
+	// strict mode still parses so that format mistakes get reported
+	if 0 == len(args) && !blog.strict {
+		return blog.writeMessage(level, format)
+	}
+
+	if nil != blog.formatter {
+		blog.message.Reset()
+		blog.short.reset(blog.message)
+		blog.format(blog.message, format, args)
+		return blog.writeFormatted(level, blog.message.String())
+	}
+
 	// 统计日志size
-	var size = 0
+	// size is what input io accepted, a short write stops the line
+	blog.short.reset(blog.begin())
+	w := &blog.short
+	defer func() {
+		if nil != w.err {
+			blog.writeError(w.err)
+		}
+		if blog.end(level) {
+			size = 0
+		}
+	}()
 
+	w.Write(blog.frame(level))
+	ts := blog.timestamp()
+	w.Write(ts)
+	prefix := level.prefixBytes()
+	w.Write(prefix)
+
+	blog.format(blog.body(w, level, ts), format, args)
+	w.WriteByte(blog.eol)
+
+	return w.n
+}
+
+// format parses format and writes the message formatted into body,
+// it is called under lock of BLog
+func (blog *BLog) format(body lineWriter, format string, args []interface{}) (size int) {
 	// 识别占位符标记
 	var tag = false
 	var tagPos int
@@ -298,54 +725,202 @@ func (blog *BLog) writef(level LevelType, format string, args ...interface{}) in
 	var last int
 	var s int
 
-	blog.writer.Write(timeCache.Format())
-	blog.writer.WriteString(level.prefix())
-
-	size += len(timeCache.Format()) + len(level.prefix())
-
 	for i, v := range format {
+		// input io failed, the rest is not formatted
+		if nil != blog.short.err {
+			return size
+		}
+
 		if tag {
 			switch v {
-			case 'd', 'f', 'v', 'b', 'o', 'x', 'X', 'c', 'p', 't', 's', 'T', 'q', 'U', 'e', 'E', 'g', 'G':
+			case 'd', 'f', 'v', 'b', 'o', 'x', 'X', 'c', 'p', 't', 's', 'T', 'q', 'U', 'e', 'E', 'g', 'G', HEXVERB, JSONVERB:
 				if escape {
 					escape = false
 				}
 
-				s, _ = blog.writer.WriteString(fmt.Sprintf(format[tagPos:i+1], args[n]))
+				if n < len(args) && blog.replaceNil && isNil(args[n]) {
+					s, _ = body.WriteString(blog.nilString)
+				} else if n < len(args) && HEXVERB == v {
+					s = writeHex(body, args[n])
+				} else if n < len(args) && JSONVERB == v {
+					s = writeJSONArg(body, args[n])
+				} else if n < len(args) && blog.smartTime && ('v' == v || 's' == v) && isTimeArg(args[n]) {
+					s = blog.writeTimeArg(body, args[n])
+				} else if n < len(args) && (blog.safeStringer || blog.rawStringer) {
+					verb := blog.verb(format[tagPos : i+1])
+					s, _ = fmt.Fprintf(body, verb, blog.stringerArg(verb, args[n]))
+				} else if n < len(args) {
+					// format into buffer directly with a sub slice of args,
+					// so that nothing is allocated for every arg
+					s, _ = fmt.Fprintf(body, blog.verb(format[tagPos:i+1]), args[n:n+1]...)
+				} else {
+					// 参数不足
+					s, _ = body.WriteString(badVerb(v, "MISSING"))
+					blog.formatError(format, tagPos, "missing argument")
+				}
 				size += s
 				n++
 				last = i + 1
 				tag = false
 			//转义符
-			case ESCAPE:
+			case rune(blog.escape):
 				if escape {
-					blog.writer.WriteByte(ESCAPE)
+					body.WriteByte(blog.escape)
 					size++
 				}
 				escape = !escape
 			//默认
 			default:
+				if !blog.strict || isFlag(v) {
+					break
+				}
 
+				if rune(blog.placeholder) == v && tagPos == i-1 {
+					// 严格模式下 %% 输出 %
+					body.WriteByte(blog.placeholder)
+					size++
+				} else {
+					// 严格模式下不识别的格式
+					s, _ = body.WriteString(badVerb(v, "BADVERB"))
+					size += s
+					blog.formatError(format, tagPos, "unknown verb")
+				}
+				last = i + 1
+				tag = false
 			}
 
 		} else {
 			// 占位符，百分号
-			if PLACEHOLDER == format[i] && !escape {
+			if blog.placeholder == format[i] && !escape {
 				tag = true
 				tagPos = i
-				s, _ = blog.writer.WriteString(format[last:i])
+				s, _ = body.WriteString(format[last:i])
 				size += s
 				escape = false
 			}
 		}
 	}
-	blog.writer.WriteString(format[last:])
-	blog.writer.WriteByte(EOL)
+	if blog.strict {
+		if tag {
+			// 占位符后缺少格式
+			s, _ = body.WriteString(badVerb(0, "NOVERB"))
+			size += s
+			blog.formatError(format, tagPos, "missing verb")
+			last = len(format)
+		}
+
+		if n < len(args) {
+			// 参数过多
+			s, _ = body.WriteString(format[last:])
+			size += s
+			s, _ = body.WriteString(extraArgs(args[n:]))
+			size += s
+			blog.formatError(format, len(format), "extra arguments")
+			last = len(format)
+		}
+	}
 
-	size += len(format[last:]) + 1
+	s, _ = body.WriteString(format[last:])
+	size += s
 	return size
 }
 
+// formatError reports a mistake in format string to error handler
+// in strict mode
+func (blog *BLog) formatError(format string, pos int, reason string) {
+	if !blog.strict || nil == blog.errorHandler {
+		return
+	}
+
+	blog.errorHandler(&FormatError{Format: format, Pos: pos, Reason: reason})
+}
+
+// writeLines writes a multi-line message with specific level.
+// every line of the message is written with its own time and level prefix,
+// the whole block is written under one lock so that it keeps contiguous
+func (blog *BLog) writeLines(level LevelType, message string) (size int) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if nil != blog.formatter {
+		return blog.writeFormatted(level, message)
+	}
+
+	// 统计日志size
+	w := blog.begin()
+	defer func() {
+		if blog.end(level) {
+			size = 0
+		}
+	}()
+
+	frame := blog.frame(level)
+	ts := blog.timestamp()
+	prefix := level.prefixBytes()
+	for i, line := range strings.Split(message, string(blog.eol)) {
+		w.Write(frame)
+		w.Write(ts)
+		w.Write(prefix)
+		if 0 == i {
+			// sequence number, default fields and caller are written ahead
+			// the message once, as writef does in multiline prefix mode
+			blog.body(w, level, ts)
+			size += blog.extra()
+		}
+		w.WriteString(line)
+		w.WriteByte(blog.eol)
+
+		size += len(frame) + len(ts) + len(prefix) + len(line) + 1
+	}
+
+	return size
+}
+
+// writeRaw writes line as it is without time and level prefix
+func (blog *BLog) writeRaw(level LevelType, line string) int {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	w := blog.begin()
+	defer blog.end(level)
+	blog.mark = -1
+
+	frame := blog.frame(level)
+	w.Write(frame)
+	w.WriteString(line)
+	w.WriteByte(blog.eol)
+	return len(frame) + len(line) + 1
+}
+
+// writeJSON writes fields as a single json line with specific level
+func (blog *BLog) writeJSON(level LevelType, fields map[string]interface{}) (size int) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if nil != blog.formatter {
+		return blog.writeFormatted(level, jsonFields(fields))
+	}
+
+	var line []byte
+	if blog.dev {
+		line = blog.devFormat(level, fields)
+	} else {
+		line = blog.endLine(blog.json.encode(timeCache.Now(), level, fields))
+	}
+
+	w := blog.begin()
+	defer func() {
+		if blog.end(level) {
+			size = 0
+		}
+	}()
+
+	frame := blog.frame(level)
+	w.Write(frame)
+	w.Write(line)
+	return len(frame) + len(line)
+}
+
 // Flush flush buffer to disk
 func (blog *BLog) flush() {
 	blog.lock.Lock()
@@ -355,9 +930,29 @@ func (blog *BLog) flush() {
 		return
 	}
 
+	blog.releaseAll()
 	blog.writer.Flush()
 }
 
+// Ping flushes buffered logs and does a zero-byte write to the input
+// io.Writer, so that an unwritable destination is reported without
+// writing any visible content
+func (blog *BLog) Ping() error {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if blog.closed {
+		return ErrWriterClosed
+	}
+
+	if err := blog.writer.Flush(); nil != err {
+		return err
+	}
+
+	_, err := blog.in.Write(nil)
+	return err
+}
+
 // Close close file writer
 func (blog *BLog) Close() {
 	blog.lock.Lock()
@@ -368,8 +963,191 @@ func (blog *BLog) Close() {
 	}
 
 	blog.closed = true
+	blog.releaseAll()
 	blog.writer.Flush()
 	blog.writer = nil
+	timeCache.release()
+}
+
+// verb converts a placeholder with its verb into the fmt format
+func (blog *BLog) verb(placeholder string) string {
+	if PLACEHOLDER == blog.placeholder {
+		return placeholder
+	}
+	return string(PLACEHOLDER) + placeholder[1:]
+}
+
+// StrictFormat get whether BLog is in strict format mode
+func (blog *BLog) StrictFormat() bool {
+	return blog.strict
+}
+
+// SetStrictFormat toggle strict format mode, default false.
+// In strict mode, unknown verbs, missing or extra arguments and placeholders
+// without verb are written as explicit error markers like %!y(BADVERB) and
+// reported to the error handler, which helps catching logging bugs in
+// development. In lenient mode format string is passed through as it is.
+func (blog *BLog) SetStrictFormat(strict bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.strict = strict
+	return blog
+}
+
+// SetErrorHandler set handler called when BLog meets an error.
+// handler is called while BLog is locked, so it must not log with the same BLog
+func (blog *BLog) SetErrorHandler(handler ErrorHandler) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.errorHandler = handler
+	return blog
+}
+
+// Placeholder get placeholder character used in writef
+func (blog *BLog) Placeholder() byte {
+	return blog.placeholder
+}
+
+// SetPlaceholder set placeholder character used in writef, default '%'.
+// e.g. with '@' as placeholder, Infof("name LIKE '%foo%' AND id = @d", 1)
+// writes percent signs as they are. It must be set before BLog is used.
+// escape character and EOL can not be used as placeholder
+func (blog *BLog) SetPlaceholder(placeholder byte) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if blog.escape == placeholder || blog.eol == placeholder {
+		return blog
+	}
+	blog.placeholder = placeholder
+	return blog
+}
+
+// EOL get character ending every line
+func (blog *BLog) EOL() byte {
+	return blog.eol
+}
+
+// SetEOL set character ending every line, default EOL. e.g. with 0 lines
+// are framed as null terminated records. placeholder and escape character
+// can not be used as EOL
+func (blog *BLog) SetEOL(eol byte) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if blog.placeholder == eol || blog.escape == eol {
+		return blog
+	}
+	blog.eol = eol
+	return blog
+}
+
+// setCharacters set placeholder, escape character and EOL together, they are
+// checked to differ by caller
+func (blog *BLog) setCharacters(placeholder, escape, eol byte) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.placeholder, blog.escape, blog.eol = placeholder, escape, eol
+}
+
+// Escape get escape character used in writef
+func (blog *BLog) Escape() byte {
+	return blog.escape
+}
+
+// SetEscape set escape character used in writef, default ESCAPE.
+// placeholder and EOL can not be used as escape character
+func (blog *BLog) SetEscape(escape byte) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if blog.placeholder == escape || blog.eol == escape {
+		return blog
+	}
+	blog.escape = escape
+	return blog
+}
+
+// SetNilString set the token nil args are written as in writef, such as
+// "<nil>" or "". nil interfaces and nil pointers, maps, slices, channels and
+// functions are replaced regardless of verbs. By default nil args are
+// formatted by fmt, e.g. %!s(<nil>)
+func (blog *BLog) SetNilString(s string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.nilString = s
+	blog.replaceNil = true
+	return blog
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately
+func (blog *BLog) FlushLevel() LevelType {
+	return blog.flushLevel
+}
+
+// SetFlushLevel set the level at or above which messages are flushed to
+// input io right after written, so that critical messages are durable even
+// if the program crashes before the next flush. Messages below it keep
+// buffered. An invalid level disables it, which is the default
+func (blog *BLog) SetFlushLevel(level LevelType) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if !level.valid() {
+		level = noFlushLevel
+	}
+	blog.flushLevel = level
+	return blog
+}
+
+// ThreadSafe get whether BLog is thread safe
+func (blog *BLog) ThreadSafe() bool {
+	return blog.threadSafe
+}
+
+// SetThreadSafe toggle thread safe mode, default true.
+// When threadSafe is false, the mutex used in write, writef and flush is
+// replaced with a no-op lock, which saves the mutex cost for callers that
+// log from a single goroutine only.
+// It must be set before BLog is used, and BLog must never be used by
+// multiple goroutines concurrently when not in thread safe mode.
+func (blog *BLog) SetThreadSafe(threadSafe bool) *BLog {
+	if threadSafe == blog.threadSafe {
+		return blog
+	}
+
+	blog.threadSafe = threadSafe
+	if threadSafe {
+		blog.lock = new(sync.Mutex)
+	} else {
+		blog.lock = nopLocker{}
+	}
+	return blog
+}
+
+// AtomicWrite get whether every line is written with a single Write call
+func (blog *BLog) AtomicWrite() bool {
+	return blog.atomic
+}
+
+// SetAtomicWrite toggle atomic mode. In atomic mode every line is assembled
+// in memory first and then written to input io with a single Write call
+// rather than through the bufio.Writer, which may split a line into two
+// Write calls when the line straddles the buffer boundary.
+// Together with a file opened with O_APPEND, lines written by different
+// processes to the same file will not interleave as long as every line is
+// no longer than PIPE_BUF (4096 bytes on linux).
+func (blog *BLog) SetAtomicWrite(atomic bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	// flush lines already buffered to keep them in order
+	if atomic && !blog.atomic && nil != blog.writer {
+		blog.writer.Flush()
+	}
+	blog.atomic = atomic
+	return blog
 }
 
 // In return the input io.Writer
@@ -382,6 +1160,19 @@ func (blog *BLog) Level() LevelType {
 	return blog.level
 }
 
+// Name get name of BLog
+func (blog *BLog) Name() string {
+	return blog.name
+}
+
+// SetName set name of BLog, used to tell writers apart
+func (blog *BLog) SetName(name string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.name = name
+	return blog
+}
+
 // SetLevel set logging level threshold
 func (blog *BLog) SetLevel(level LevelType) *BLog {
 	blog.level = level
@@ -393,163 +1184,495 @@ func (blog *BLog) resetFile(in io.Writer) (err error) {
 	blog.lock.Lock()
 	defer blog.lock.Unlock()
 
+	// 轮转前写出去重汇总
+	if nil != blog.debounces {
+		blog.summarizeAll()
+	}
+	blog.releaseAll()
 	blog.writer.Flush()
 
 	blog.in = in
-	blog.writer.Reset(in)
+	blog.relay.w = blog.bufferedIn()
+	blog.writer.Reset(blog.relay)
+	blog.err = nil
 
 	return
 }
 
 // Level get log level
 func Level() LevelType {
-	return blog.Level()
+	return blog().Level()
+}
+
+// Name get name of the singleton writer
+func Name() string {
+	return blog().Name()
+}
+
+// SetName set name of the singleton writer
+func SetName(name string) {
+	blog().SetName(name)
 }
 
 // SetLevel set level for logging action
 func SetLevel(level LevelType) {
-	blog.SetLevel(level)
+	blog().SetLevel(level)
+}
+
+// PushLevel sets logging level of the singleton writer and return a function
+// restoring the previous level, e.g. defer blog4go.PushLevel(blog4go.ERROR)()
+// the level is process wide rather than goroutine scoped, messages logged by
+// other goroutines are filtered by it as well. restore functions should be
+// called in reverse order when levels are pushed more than once
+func PushLevel(level LevelType) func() {
+	return pushLevel(blog(), level)
+}
+
+// pushLevel sets logging level of writer and return a function restoring it
+func pushLevel(writer Writer, level LevelType) func() {
+	previous := writer.Level()
+	writer.SetLevel(level)
+	return func() {
+		writer.SetLevel(previous)
+	}
 }
 
 // SetHook set hook for logging action
 func SetHook(hook Hook) {
-	blog.SetHook(hook)
+	blog().SetHook(hook)
 }
 
 // SetHookLevel set when hook will be called
 func SetHookLevel(level LevelType) {
-	blog.SetHookLevel(level)
+	blog().SetHookLevel(level)
 }
 
 // SetHookAsync set whether hook is called async
 func SetHookAsync(async bool) {
-	blog.SetHookAsync(async)
+	blog().SetHookAsync(async)
 }
 
 // Colored get whether it is log with colored
 func Colored() bool {
-	return blog.Colored()
+	return blog().Colored()
 }
 
 // SetColored set logging color
 func SetColored(colored bool) {
-	blog.SetColored(colored)
+	blog().SetColored(colored)
+}
+
+// StrictFormat get whether it is in strict format mode
+func StrictFormat() bool {
+	return blog().StrictFormat()
+}
+
+// SetStrictFormat toggle strict format mode
+func SetStrictFormat(strict bool) {
+	blog().SetStrictFormat(strict)
+}
+
+// SetErrorHandler set handler called when writer meets an error
+func SetErrorHandler(handler ErrorHandler) {
+	blog().SetErrorHandler(handler)
+}
+
+// SetWriteTimeout bound every write of the singleton writer to its
+// destination, it works for socket writers
+func SetWriteTimeout(d time.Duration) {
+	blog().SetWriteTimeout(d)
+}
+
+// Placeholder get placeholder character used in formatting
+func Placeholder() byte {
+	return blog().Placeholder()
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func SetPlaceholder(placeholder byte) {
+	blog().SetPlaceholder(placeholder)
+}
+
+// SetEOL set character ending every line of the singleton writer
+func SetEOL(eol byte) {
+	blog().SetEOL(eol)
+}
+
+// SetEscape set escape character used in formatting
+func SetEscape(escape byte) {
+	blog().SetEscape(escape)
+}
+
+// SetTimeFormat set layout of time prefix
+func SetTimeFormat(layout string) {
+	blog().SetTimeFormat(layout)
+}
+
+// SetTimeFormatPreset set time prefix as a preset format
+func SetTimeFormatPreset(preset TimePreset) {
+	blog().SetTimeFormatPreset(preset)
+}
+
+// SetNilString set the token nil args are written as in formatting
+func SetNilString(s string) {
+	blog().SetNilString(s)
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately
+func FlushLevel() LevelType {
+	return blog().FlushLevel()
+}
+
+// SetFlushLevel set the level at or above which messages are flushed immediately
+func SetFlushLevel(level LevelType) {
+	blog().SetFlushLevel(level)
+}
+
+// AtomicWrite get whether every line is written with a single Write call
+func AtomicWrite() bool {
+	return blog().AtomicWrite()
+}
+
+// SetAtomicWrite toggle writing every line with a single Write call
+func SetAtomicWrite(atomic bool) {
+	blog().SetAtomicWrite(atomic)
+}
+
+// MultilinePrefix get whether every line of a multi-line message is prefixed
+func MultilinePrefix() bool {
+	return blog().MultilinePrefix()
+}
+
+// SetMultilinePrefix toggle prefixing every line of a multi-line message
+func SetMultilinePrefix(multiline bool) {
+	blog().SetMultilinePrefix(multiline)
 }
 
 // TimeRotated get timeRotated
 func TimeRotated() bool {
-	return blog.TimeRotated()
+	return blog().TimeRotated()
 }
 
 // SetTimeRotated toggle time base logrotate on the fly
 func SetTimeRotated(timeRotated bool) {
-	blog.SetTimeRotated(timeRotated)
+	blog().SetTimeRotated(timeRotated)
 }
 
 // Retentions get retentions
 func Retentions() int64 {
-	return blog.Retentions()
+	return blog().Retentions()
 }
 
 // SetRetentions set how many logs will keep after logrotate
 func SetRetentions(retentions int64) {
-	blog.SetRetentions(retentions)
+	blog().SetRetentions(retentions)
+}
+
+// Rotate force a logrotate on demand
+func Rotate() error {
+	return blog().Rotate()
+}
+
+// MaxTotalSize get max total size of all archives
+func MaxTotalSize() int64 {
+	return blog().MaxTotalSize()
+}
+
+// SetMaxTotalSize set max total size of all archives after logrotate
+func SetMaxTotalSize(maxTotalSize int64) {
+	blog().SetMaxTotalSize(maxTotalSize)
+}
+
+// SetRotateHook set a user defined logrotate policy
+func SetRotateHook(hook RotateHook) {
+	blog().SetRotateHook(hook)
+}
+
+// FileMode get permission of log files
+func FileMode() os.FileMode {
+	return blog().FileMode()
+}
+
+// SetFileMode set permission of log files
+func SetFileMode(mode os.FileMode) error {
+	return blog().SetFileMode(mode)
+}
+
+// SetFileOwner set owner of log files
+func SetFileOwner(uid, gid int) error {
+	return blog().SetFileOwner(uid, gid)
+}
+
+// SetOpenBanner toggle writing a banner line whenever a log file is opened
+func SetOpenBanner(banner bool) {
+	blog().SetOpenBanner(banner)
 }
 
 // RotateSize get rotateSize
 func RotateSize() int64 {
-	return blog.RotateSize()
+	return blog().RotateSize()
 }
 
 // SetRotateSize set size when logroatate
 func SetRotateSize(rotateSize int64) {
-	blog.SetRotateSize(rotateSize)
+	blog().SetRotateSize(rotateSize)
 }
 
 // RotateLines get rotateLines
 func RotateLines() int {
-	return blog.RotateLines()
+	return blog().RotateLines()
 }
 
 // SetRotateLines set line number when logrotate
 func SetRotateLines(rotateLines int) {
-	blog.SetRotateLines(rotateLines)
+	blog().SetRotateLines(rotateLines)
+}
+
+// Drain blocks until logs written before are flushed to destination
+func Drain() error {
+	return blog().Drain()
+}
+
+// Ping checks whether the singleton writer can still write to its
+// destination, designed for readiness probes
+func Ping() error {
+	return blog().Ping()
 }
 
 // Flush flush logs to disk
 func Flush() {
-	blog.flush()
+	blog().flush()
 }
 
 // Trace static function for Trace
 func Trace(args ...interface{}) {
-	blog.Trace(args...)
+	if TRACE < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Trace(args...)
 }
 
 // Tracef static function for Tracef
 func Tracef(format string, args ...interface{}) {
-	blog.Tracef(format, args...)
+	if TRACE < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Tracef(format, args...)
 }
 
 // Debug static function for Debug
 func Debug(args ...interface{}) {
-	blog.Debug(args...)
+	if DEBUG < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Debug(args...)
 }
 
 // Debugf static function for Debugf
 func Debugf(format string, args ...interface{}) {
-	blog.Debugf(format, args...)
+	if DEBUG < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Debugf(format, args...)
 }
 
 // Info static function for Info
 func Info(args ...interface{}) {
-	blog.Info(args...)
+	if INFO < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Info(args...)
 }
 
 // Infof static function for Infof
 func Infof(format string, args ...interface{}) {
-	blog.Infof(format, args...)
+	if INFO < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Infof(format, args...)
+}
+
+// InfoSync static function for InfoSync
+func InfoSync(message string) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.InfoSync(message)
+}
+
+// InfofSync static function for InfofSync
+func InfofSync(format string, args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.InfofSync(format, args...)
 }
 
 // Warn static function for Warn
 func Warn(args ...interface{}) {
-	blog.Warn(args...)
+	if WARNING < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Warn(args...)
 }
 
 // Warnf static function for Warnf
 func Warnf(format string, args ...interface{}) {
-	blog.Warnf(format, args...)
+	if WARNING < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Warnf(format, args...)
 }
 
 // Error static function for Error
 func Error(args ...interface{}) {
-	blog.Error(args...)
+	if ERROR < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Error(args...)
 }
 
 // Errorf static function for Errorf
 func Errorf(format string, args ...interface{}) {
-	blog.Errorf(format, args...)
+	if ERROR < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Errorf(format, args...)
+}
+
+// DebugPretty static function for DebugPretty
+func DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.DebugPretty(v)
 }
 
 // Critical static function for Critical
 func Critical(args ...interface{}) {
-	blog.Critical(args...)
+	if CRITICAL < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Critical(args...)
 }
 
 // Criticalf static function for Criticalf
 func Criticalf(format string, args ...interface{}) {
-	blog.Criticalf(format, args...)
+	if CRITICAL < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Criticalf(format, args...)
 }
 
-// Close close the logger
+// logs calls the logging function of writer associate with level
+func logs(writer Writer, level LevelType, args ...interface{}) {
+	switch level {
+	case TRACE:
+		writer.Trace(args...)
+	case DEBUG:
+		writer.Debug(args...)
+	case INFO:
+		writer.Info(args...)
+	case WARNING:
+		writer.Warn(args...)
+	case ERROR:
+		writer.Error(args...)
+	case CRITICAL:
+		writer.Critical(args...)
+	}
+}
+
+// logf calls the formatting function of writer associate with level
+func logf(writer Writer, level LevelType, format string, args ...interface{}) {
+	switch level {
+	case TRACE:
+		writer.Tracef(format, args...)
+	case DEBUG:
+		writer.Debugf(format, args...)
+	case INFO:
+		writer.Infof(format, args...)
+	case WARNING:
+		writer.Warnf(format, args...)
+	case ERROR:
+		writer.Errorf(format, args...)
+	case CRITICAL:
+		writer.Criticalf(format, args...)
+	}
+}
+
+// InfoJSON writes fields as a json line with info level
+func InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.InfoJSON(fields)
+}
+
+// NewEntry starts building a structured message with the singleton writer
+func NewEntry(level LevelType) *Entry {
+	return blog().Entry(level)
+}
+
+// Close close the logger, the singleton is removed first and closed once
+// logging calls of package functions in flight on it have returned
 func Close() {
 	singltonLock.Lock()
-	defer singltonLock.Unlock()
-
-	if nil == blog {
+	old, _ := singleton.Load().(*singletonHolder)
+	if nil == old || nil == old.writer {
+		singltonLock.Unlock()
 		return
 	}
+	setBlog(nil)
+	singltonLock.Unlock()
 
-	blog.Close()
-	blog = nil
+	old.wait()
+	old.writer.Close()
 }
@@ -3,7 +3,12 @@
 package blog4go
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -105,3 +110,289 @@ func TestGlobalOperation(t *testing.T) {
 	Critical("Critical", 6)
 	Criticalf("%s", "Critical")
 }
+
+func TestBLogThreadSafe(t *testing.T) {
+	fileName := "/tmp/threadsafe.log"
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.FileMode(0644))
+	if nil != err {
+		t.Fatal(err.Error())
+	}
+	defer func() {
+		file.Close()
+		os.Remove(fileName)
+	}()
+
+	initPrefix(false)
+
+	blog := NewBLog(file)
+	if !blog.ThreadSafe() {
+		t.Error("BLog should be thread safe by default.")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				blog.writef(INFO, "haha %s %d", "eddie", j)
+				blog.write(INFO, "test for not formated")
+			}
+		}()
+	}
+	wg.Wait()
+	blog.flush()
+
+	content, err := ioutil.ReadFile(fileName)
+	if nil != err {
+		t.Fatal(err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), string(EOL)), string(EOL))
+	if 10*100*2 != len(lines) {
+		t.Errorf("it loses %d lines.", 10*100*2-len(lines))
+	}
+
+	for i, line := range lines {
+		arrs := strings.Split(line, "[INFO] ")
+		if 2 != len(arrs) || (!strings.HasPrefix(arrs[1], "haha eddie ") && "test for not formated" != arrs[1]) {
+			t.Errorf("line %d detect inconsistent line. lineStr: %s", i, line)
+		}
+	}
+
+	blog.SetThreadSafe(false)
+	if blog.ThreadSafe() {
+		t.Error("BLog should not be thread safe.")
+	}
+	blog.SetThreadSafe(true)
+	if !blog.ThreadSafe() {
+		t.Error("BLog should be thread safe.")
+	}
+}
+
+func BenchmarkBLogThreadSafe(b *testing.B) {
+	blog := NewBLog(ioutil.Discard)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blog.writef(DEBUG, "haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+	}
+}
+
+func BenchmarkBLogNotThreadSafe(b *testing.B) {
+	blog := NewBLog(ioutil.Discard).SetThreadSafe(false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blog.writef(DEBUG, "haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+	}
+}
+
+func TestBLogPlaceholder(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	if PLACEHOLDER != blog.Placeholder() {
+		t.Errorf("default placeholder wrong. placeholder: %c", blog.Placeholder())
+	}
+
+	blog.SetPlaceholder('@')
+	blog.writef(INFO, "SELECT * FROM users WHERE name LIKE '%foo%' AND id = @d AND nick = @s", 18, "eddie")
+	blog.flush()
+
+	expected := "SELECT * FROM users WHERE name LIKE '%foo%' AND id = 18 AND nick = eddie"
+	if arrs := strings.Split(buf.String(), "[INFO] "); 2 != len(arrs) || expected+string(EOL) != arrs[1] {
+		t.Errorf("format with custom placeholder wrong. line: %s", buf.String())
+	}
+
+	// escape and eol can not be placeholder
+	blog.SetPlaceholder(ESCAPE)
+	blog.SetPlaceholder(EOL)
+	if '@' != blog.Placeholder() {
+		t.Errorf("placeholder should not be changed. placeholder: %c", blog.Placeholder())
+	}
+}
+
+func TestBLogFramingPerWriter(t *testing.T) {
+	initPrefix(false)
+
+	sqlBuf := new(bytes.Buffer)
+	sql := NewBLog(sqlBuf)
+	sql.SetPlaceholder('@')
+	sql.SetEscape('^')
+	sql.SetEOL(0)
+
+	plainBuf := new(bytes.Buffer)
+	plain := NewBLog(plainBuf)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			sql.writef(INFO, "LIKE '%foo%' AND id = @d", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			plain.writef(INFO, "@ %d%s", i, "!")
+		}
+	}()
+	wg.Wait()
+	sql.flush()
+	plain.flush()
+
+	records := strings.Split(strings.TrimSuffix(sqlBuf.String(), "\x00"), "\x00")
+	if 100 != len(records) || strings.Contains(sqlBuf.String(), "\n") || !strings.HasSuffix(records[99], " [INFO] LIKE '%foo%' AND id = 99") {
+		t.Errorf("custom framing wrong. records: %d, last: %q", len(records), records[len(records)-1])
+	}
+
+	lines := strings.Split(strings.TrimSuffix(plainBuf.String(), "\n"), "\n")
+	if 100 != len(lines) || !strings.HasSuffix(lines[99], " [INFO] @ 99!") {
+		t.Errorf("default framing wrong. lines: %d, last: %q", len(lines), lines[len(lines)-1])
+	}
+
+	// characters in use can not be shared
+	sql.SetEOL('@')
+	sql.SetEscape(0)
+	if 0 != sql.EOL() || '^' != sql.Escape() {
+		t.Errorf("framing characters should not be changed. eol: %q, escape: %q", sql.EOL(), sql.Escape())
+	}
+}
+
+func TestBLogSnapshot(t *testing.T) {
+	initPrefix(false)
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	if nil != blog.Snapshot() {
+		t.Error("snapshot should be nil when disabled.")
+	}
+
+	blog.SetSnapshot(true)
+	blog.writef(INFO, "user %s", "alice")
+	blog.write(DEBUG, "buffered")
+
+	snapshot := string(blog.Snapshot())
+	if 0 != buf.Len() {
+		t.Errorf("snapshot should not flush. flushed: %s", buf.String())
+	}
+	if !strings.Contains(snapshot, " [INFO] user alice\n") || !strings.HasSuffix(snapshot, " [DEBUG] buffered\n") || 2 != strings.Count(snapshot, "\n") {
+		t.Errorf("snapshot wrong. snapshot: %s", snapshot)
+	}
+
+	blog.flush()
+	if snapshot != buf.String() || 0 != len(blog.Snapshot()) {
+		t.Errorf("snapshot should be drained after flush. flushed: %s, snapshot: %s", buf.String(), string(blog.Snapshot()))
+	}
+
+	// lines straddling the buffer boundary are partially flushed by bufio
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		blog.write(INFO, strings.Repeat("x", DefaultBufferSize/2))
+	}
+	line := " [INFO] " + strings.Repeat("x", DefaultBufferSize/2) + "\n"
+	if all := buf.String() + string(blog.Snapshot()); 0 == buf.Len() || 3 != strings.Count(all, line) || 3 != strings.Count(all, "\n") {
+		t.Error("flushed bytes and snapshot should make up all lines.")
+	}
+
+	blog.SetSnapshot(false)
+	if nil != blog.Snapshot() {
+		t.Error("snapshot should be nil after disabled.")
+	}
+}
+
+func TestBLogMultilinePrefix(t *testing.T) {
+	initPrefix(false)
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	// only the first line is prefixed by default
+	blog.write(ERROR, "first\nsecond\nthird")
+	blog.flush()
+	if 1 != strings.Count(buf.String(), " [ERROR] ") {
+		t.Errorf("only the first line should be prefixed. content: %s", buf.String())
+	}
+
+	blog.SetMultilinePrefix(true)
+	if !blog.MultilinePrefix() {
+		t.Fatal("multiline prefix mode should be on.")
+	}
+
+	cases := []func() int{
+		func() int { return blog.write(ERROR, "first\nsecond\nthird") },
+		func() int { return blog.writef(ERROR, "first\n%s\n%s", "second", "third") },
+		func() int { return blog.writef(ERROR, "first%s", "\nsecond\nthird") },
+	}
+
+	for i, c := range cases {
+		buf.Reset()
+		size := c()
+		blog.flush()
+
+		if buf.Len() != size {
+			t.Errorf("size wrong. case: %d, size: %d, written: %d", i, size, buf.Len())
+		}
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if 3 != len(lines) {
+			t.Fatalf("line count wrong. case: %d, content: %s", i, buf.String())
+		}
+
+		for j, expected := range []string{"first", "second", "third"} {
+			if !strings.HasSuffix(lines[j], " [ERROR] "+expected) || !strings.HasPrefix(lines[j], "[") {
+				t.Errorf("line should be prefixed. case: %d, line: %s", i, lines[j])
+			}
+		}
+	}
+
+	// lines are split on eol of BLog
+	blog.SetEOL(0)
+	buf.Reset()
+	blog.write(ERROR, "first\x00second\nthird")
+	blog.flush()
+	records := strings.Split(strings.TrimSuffix(buf.String(), "\x00"), "\x00")
+	if 2 != len(records) || !strings.HasSuffix(records[1], " [ERROR] second\nthird") {
+		t.Errorf("null terminated records should be prefixed. content: %q", buf.String())
+	}
+}
+
+func TestWriterName(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	fileWriter, err := newBaseFileWriter("/tmp/name.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	consoleWriter, _ := newConsoleWriter()
+	defer func() {
+		fileWriter.Close()
+		consoleWriter.Close()
+		os.Remove("/tmp/name.log")
+	}()
+
+	writers := map[string]Writer{
+		"/tmp/name.log": fileWriter,
+		"console":       consoleWriter,
+		"sink":          NewSinkWriter(newMySink()),
+		"router":        NewRouterWriter(),
+	}
+	for name, writer := range writers {
+		if name != writer.Name() {
+			t.Errorf("default name wrong. expected: %s, got: %s", name, writer.Name())
+		}
+
+		writer.SetName("audit")
+		if "audit" != writer.Name() {
+			t.Errorf("name should be set. expected: audit, got: %s", writer.Name())
+		}
+	}
+
+	setBlog(NewSinkWriter(newMySink()))
+	SetName("singleton")
+	if "singleton" != Name() {
+		t.Errorf("name of singleton wrong. got: %s", Name())
+	}
+}
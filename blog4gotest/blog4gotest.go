@@ -0,0 +1,191 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package blog4gotest provides a blog4go writer logging to a test through
+// tb.Logf, so that library output is kept with the test it belongs to. It
+// lives in its own package so that importing blog4go does not link testing
+// into production binaries.
+package blog4gotest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// testSink logs messages to a test, lines are shown only when the test fails
+// or runs with -v
+type testSink struct {
+	tb testing.TB
+
+	// lines are kept instead of logged while a logging function of
+	// TestWriter is in flight, it logs them itself
+	capture bool
+	lines   []string
+
+	lock *sync.Mutex
+}
+
+// Emit logs message with level to the test
+func (sink *testSink) Emit(t time.Time, level blog4go.LevelType, message string) error {
+	sink.tb.Helper()
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	line := "[" + level.String() + "] " + message
+	if sink.capture {
+		sink.lines = append(sink.lines, line)
+		return nil
+	}
+
+	sink.tb.Logf("%s", line)
+	return nil
+}
+
+// Flush do nothing
+func (sink *testSink) Flush() error {
+	return nil
+}
+
+// Close do nothing
+func (sink *testSink) Close() error {
+	return nil
+}
+
+// TestWriter is a writer logging to a test through tb.Logf. Lines of its
+// level functions are attributed to their caller, lines written otherwise,
+// such as by Entry or package functions of blog4go, are attributed to
+// blog4go
+type TestWriter struct {
+	blog4go.Writer
+
+	tb   testing.TB
+	sink *testSink
+
+	// serializes logging functions capturing lines of the sink
+	lock *sync.Mutex
+}
+
+// NewTestWriter creates a writer logging every message to tb, not singlton.
+// The writer is closed when the test completes, messages afterwards are
+// dropped instead of panicking in tb.Logf. default level TRACE
+func NewTestWriter(tb testing.TB) *TestWriter {
+	sink := &testSink{tb: tb, lock: new(sync.Mutex)}
+	sinkWriter := blog4go.NewSinkWriter(sink)
+	sinkWriter.SetName("test")
+	sinkWriter.SetLevel(blog4go.TRACE)
+
+	testWriter := &TestWriter{Writer: sinkWriter, tb: tb, sink: sink, lock: new(sync.Mutex)}
+	tb.Cleanup(testWriter.Close)
+	return testWriter
+}
+
+// log calls f writing to the sink and logs lines it writes to the test, so
+// that tb.Logf is called by a helper of the caller
+func (writer *TestWriter) log(f func()) {
+	writer.tb.Helper()
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.sink.lock.Lock()
+	writer.sink.capture = true
+	writer.sink.lock.Unlock()
+
+	f()
+
+	writer.sink.lock.Lock()
+	lines := writer.sink.lines
+	writer.sink.lines = nil
+	writer.sink.capture = false
+	writer.sink.lock.Unlock()
+
+	for _, line := range lines {
+		writer.tb.Logf("%s", line)
+	}
+}
+
+// SetLevel set logging level threshold and return the test writer for
+// chaining
+func (writer *TestWriter) SetLevel(level blog4go.LevelType) blog4go.Writer {
+	writer.Writer.SetLevel(level)
+	return writer
+}
+
+// Trace trace
+func (writer *TestWriter) Trace(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Trace(args...) })
+}
+
+// Tracef tracef
+func (writer *TestWriter) Tracef(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Tracef(format, args...) })
+}
+
+// Debug debug
+func (writer *TestWriter) Debug(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Debug(args...) })
+}
+
+// Debugf debugf
+func (writer *TestWriter) Debugf(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Debugf(format, args...) })
+}
+
+// Info info
+func (writer *TestWriter) Info(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Info(args...) })
+}
+
+// Infof infof
+func (writer *TestWriter) Infof(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Infof(format, args...) })
+}
+
+// Warn warn
+func (writer *TestWriter) Warn(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Warn(args...) })
+}
+
+// Warnf warnf
+func (writer *TestWriter) Warnf(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Warnf(format, args...) })
+}
+
+// Error error
+func (writer *TestWriter) Error(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Error(args...) })
+}
+
+// Errorf errorf
+func (writer *TestWriter) Errorf(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Errorf(format, args...) })
+}
+
+// Critical critical
+func (writer *TestWriter) Critical(args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Critical(args...) })
+}
+
+// Criticalf criticalf
+func (writer *TestWriter) Criticalf(format string, args ...interface{}) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Criticalf(format, args...) })
+}
+
+// Raw writes message verbatim
+func (writer *TestWriter) Raw(message string) {
+	writer.tb.Helper()
+	writer.log(func() { writer.Writer.Raw(message) })
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4gotest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// fakeTB records lines logged and cleanups registered
+type fakeTB struct {
+	testing.TB
+
+	lines    []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Logf(format string, args ...interface{}) {
+	tb.lines = append(tb.lines, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestTestWriter(t *testing.T) {
+	tb := new(fakeTB)
+	writer := NewTestWriter(tb)
+	writer.SetLevel(blog4go.INFO)
+	writer.SetPlaceholder('@')
+
+	writer.Debug("filtered")
+	writer.Info("started")
+	writer.Errorf("failed @d", 1)
+	writer.Raw("raw")
+	writer.Entry(blog4go.WARNING).Str("key", "user:1").Msg("cache miss")
+
+	expected := []string{"[INFO] started", "[ERROR] failed 1", "[CRITICAL] raw", "[WARN] cache miss key=user:1"}
+	if fmt.Sprint(expected) != fmt.Sprint(tb.lines) {
+		t.Fatalf("lines logged wrong. got: %q", tb.lines)
+	}
+
+	// test completed
+	if 1 != len(tb.cleanups) {
+		t.Fatalf("writer should be closed by cleanup. got: %d", len(tb.cleanups))
+	}
+	tb.cleanups[0]()
+
+	writer.Info("after test")
+	writer.Raw("after test")
+	if len(expected) != len(tb.lines) {
+		t.Errorf("lines after test should be dropped. got: %q", tb.lines)
+	}
+}
+
+func TestTestWriterExample(t *testing.T) {
+	// library under test logs to t, shown only on failure or with -v
+	writer := NewTestWriter(t)
+	writer.Infof("connecting to %s", "127.0.0.1:6379")
+	writer.Entry(blog4go.WARNING).Str("key", "user:1").Msg("cache miss")
+}
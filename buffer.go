@@ -0,0 +1,158 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"io"
+)
+
+// relayWriter is the input io of bufio.Writer, it forwards bytes flushed to
+// w. bufio.Writer does not expose buffered bytes, pointing w to another
+// bufio.Writer moves them there with a Flush
+type relayWriter struct {
+	w io.Writer
+}
+
+func (r *relayWriter) Write(p []byte) (int, error) {
+	return r.w.Write(p)
+}
+
+// tailWriter keeps the last bytes fitting in writer, and writes the rest
+// to in directly
+type tailWriter struct {
+	in     io.Writer
+	writer *bufio.Writer
+}
+
+func (w *tailWriter) Write(p []byte) (n int, err error) {
+	if over := len(p) - w.writer.Available(); over > 0 {
+		if n, err = w.in.Write(p[:over]); nil != err {
+			return n, err
+		}
+		p = p[over:]
+	}
+
+	m, err := w.writer.Write(p)
+	return n + m, err
+}
+
+// resize replaces bufio.Writer with one of size, bytes buffered are moved
+// into it rather than flushed. when they are more than size, only the
+// overflow is flushed. it is called under lock of BLog
+func (blog *BLog) resize(size int) {
+	relay := &relayWriter{w: blog.bufferedIn()}
+	writer := bufio.NewWriterSize(relay, size)
+
+	blog.relay.w = &tailWriter{in: relay, writer: writer}
+	blog.writer.Flush()
+
+	blog.relay = relay
+	blog.writer = writer
+	if nil != blog.tee {
+		blog.tee.writer = writer
+	}
+}
+
+// BufferSize get size of buffer
+func (blog *BLog) BufferSize() int {
+	if nil == blog.writer {
+		return 0
+	}
+	return blog.writer.Size()
+}
+
+// SetBufferSize resize buffer while writing, such as growing it during a
+// burst. bytes buffered are kept in the new buffer instead of flushed, when
+// shrinking below them only the overflow is flushed
+func (blog *BLog) SetBufferSize(size int) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if size <= 0 || nil == blog.writer || size == blog.writer.Size() {
+		return blog
+	}
+
+	blog.resize(size)
+	return blog
+}
+
+// SetBufferSize resize buffer of the singleton writer keeping bytes buffered
+func SetBufferSize(size int) {
+	blog().SetBufferSize(size)
+}
+
+// shortWriter counts bytes accepted by w, and stops writing once a write of
+// w returns less bytes than given, keeping the error. bufio.Writer returns a
+// short count when flushing to input io fails. it is used under lock of BLog
+type shortWriter struct {
+	w   lineWriter
+	n   int
+	err error
+}
+
+// reset points shortWriter to w, clearing bytes counted and error
+func (sw *shortWriter) reset(w lineWriter) {
+	sw.w = w
+	sw.n = 0
+	sw.err = nil
+}
+
+func (sw *shortWriter) short(n, want int, err error) (int, error) {
+	sw.n += n
+	if n < want {
+		if nil == err {
+			err = io.ErrShortWrite
+		}
+		sw.err = err
+	}
+	return n, err
+}
+
+func (sw *shortWriter) Write(p []byte) (int, error) {
+	if nil != sw.err {
+		return 0, sw.err
+	}
+	n, err := sw.w.Write(p)
+	return sw.short(n, len(p), err)
+}
+
+func (sw *shortWriter) WriteString(s string) (int, error) {
+	if nil != sw.err {
+		return 0, sw.err
+	}
+	n, err := sw.w.WriteString(s)
+	return sw.short(n, len(s), err)
+}
+
+func (sw *shortWriter) WriteByte(c byte) error {
+	if nil != sw.err {
+		return sw.err
+	}
+	if err := sw.w.WriteByte(c); nil != err {
+		_, err = sw.short(0, 1, err)
+		return err
+	}
+	sw.n++
+	return nil
+}
+
+// writeError keeps the first error writing to input io and reports it to
+// error handler. it is called under lock of BLog
+func (blog *BLog) writeError(err error) {
+	if nil == blog.err {
+		blog.err = err
+	}
+
+	if nil != blog.errorHandler {
+		blog.errorHandler(err)
+	}
+}
+
+// Err return the first error writing to input io, nil if none. bufio.Writer
+// keeps failing after an error, until the file is reset by logrotate
+func (blog *BLog) Err() error {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	return blog.err
+}
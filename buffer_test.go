@@ -0,0 +1,100 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetBufferSize(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := newBLogSize(buf, 64)
+	blog.SetSnapshot(true)
+
+	blog.write(INFO, "pending")
+	pending := string(blog.Snapshot())
+
+	// growing keeps bytes buffered without flushing
+	blog.SetBufferSize(1024)
+	if 1024 != blog.BufferSize() {
+		t.Errorf("buffer size not set. size: %d", blog.BufferSize())
+	}
+	if 0 != buf.Len() {
+		t.Errorf("growing should not flush. content: %s", buf.String())
+	}
+	if pending != string(blog.Snapshot()) {
+		t.Errorf("bytes buffered should be kept. snapshot: %s", blog.Snapshot())
+	}
+
+	for i := 0; i < 10; i++ {
+		blog.writef(INFO, "line %d", i)
+	}
+	if 0 != buf.Len() {
+		t.Errorf("grown buffer should keep buffering. content: %s", buf.String())
+	}
+	expected := string(blog.Snapshot())
+
+	// shrinking flushes the overflow only
+	blog.SetBufferSize(32)
+	if 0 == buf.Len() || len(expected) == buf.Len() {
+		t.Errorf("only the overflow should be flushed. flushed: %d, buffered: %d", buf.Len(), len(expected))
+	}
+	if buf.String()+string(blog.Snapshot()) != expected {
+		t.Errorf("bytes should be kept in order. flushed: %s, snapshot: %s", buf.String(), blog.Snapshot())
+	}
+
+	blog.flush()
+	if expected != buf.String() || 11 != strings.Count(buf.String(), "\n") {
+		t.Errorf("data lost while resizing. content: %s", buf.String())
+	}
+}
+
+// limitedWriter accepts the first limit bytes then fails
+type limitedWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+var errLimited = errors.New("no space left")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if left := w.limit - w.buf.Len(); len(p) > left {
+		w.buf.Write(p[:left])
+		return left, errLimited
+	}
+	return w.buf.Write(p)
+}
+
+func TestWritefShortWrite(t *testing.T) {
+	initPrefix(false)
+
+	in := &limitedWriter{limit: 10}
+	blog := newBLogSize(in, 16)
+
+	var reported []error
+	blog.SetErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+
+	size := blog.writef(INFO, "%s and %d", strings.Repeat("x", 64), 1)
+	if 10 != size || in.buf.Len() != size {
+		t.Errorf("size should be bytes accepted. size: %d, accepted: %d", size, in.buf.Len())
+	}
+
+	if errLimited != blog.Err() {
+		t.Errorf("error state not set. err: %v", blog.Err())
+	}
+	if 1 != len(reported) || errLimited != reported[0] {
+		t.Errorf("short write not reported. got: %v", reported)
+	}
+
+	// input io keeps failing
+	if size := blog.writef(INFO, "%s", "more"); 0 != size {
+		t.Errorf("nothing should be counted after failure. size: %d", size)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxCallerDepth is the maximum number of frames searched for the caller
+	maxCallerDepth = 16
+)
+
+// sourceDir is the directory of blog4go sources, frames inside are skipped
+// when looking for the caller
+var sourceDir string
+
+func init() {
+	_, file, _, _ := runtime.Caller(0)
+	sourceDir = filepath.Dir(file)
+}
+
+// caller return the first frame calling into blog4go
+func caller() (frame runtime.Frame, ok bool) {
+	var pcs [maxCallerDepth]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != sourceDir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame, "" != frame.Function
+		}
+		if !more {
+			return frame, false
+		}
+	}
+}
+
+// shortFuncName return function name qualified by package name only,
+// e.g. blog4go.(*BLog).write of github.com/YoungPioneers/blog4go.(*BLog).write
+func shortFuncName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// writeCaller writes the calling function as "func=blog4go.main " and
+// file:line as "caller=main.go:12 " in caller modes, and return number of
+// bytes written
+func (blog *BLog) writeCaller(w lineWriter) int {
+	if !blog.callerFunc && !blog.callerLine {
+		return 0
+	}
+
+	frame, ok := caller()
+	if !ok {
+		return 0
+	}
+
+	buf := blog.callerBuf[:0]
+	if blog.callerFunc {
+		buf = append(buf, "func="...)
+		buf = append(buf, shortFuncName(frame.Function)...)
+		buf = append(buf, ' ')
+	}
+	if blog.callerLine {
+		buf = append(buf, "caller="...)
+		buf = append(buf, filepath.Base(frame.File)...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+		buf = append(buf, ' ')
+	}
+
+	blog.callerBuf = buf
+	w.Write(buf)
+	return len(buf)
+}
+
+// SetPrintCallerFunc toggle writing the function calling the logger ahead
+// every message, such as "func=main.(*Server).serve message". It costs
+// about the same as runtime.Caller, so it is off by default
+func (blog *BLog) SetPrintCallerFunc(print bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.callerFunc = print
+	return blog
+}
+
+// SetPrintCallerLine toggle writing file:line calling the logger ahead every
+// message, such as "caller=server.go:42 message", off by default
+func (blog *BLog) SetPrintCallerLine(print bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.callerLine = print
+	return blog
+}
+
+// SetPrintCallerFunc toggle writing the calling function ahead every message
+// of the singleton writer
+func SetPrintCallerFunc(print bool) {
+	blog().SetPrintCallerFunc(print)
+}
+
+// SetPrintCallerLine toggle writing file:line of the caller ahead every
+// message of the singleton writer
+func SetPrintCallerLine(print bool) {
+	blog().SetPrintCallerLine(print)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintCallerFunc(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetPrintCallerFunc(true)
+
+	size := blog.writef(INFO, "id %d", 1)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] func=blog4go.TestPrintCallerFunc id 1\n") {
+		t.Errorf("calling function should be written. content: %s", buf.String())
+	}
+	if len(buf.String()) != size {
+		t.Errorf("size should count caller. size: %d, content: %s", size, buf.String())
+	}
+
+	// combined with file:line
+	blog.SetPrintCallerLine(true)
+	buf.Reset()
+	func() {
+		blog.write(INFO, "closure")
+	}()
+	blog.flush()
+	if !strings.Contains(buf.String(), " [INFO] func=blog4go.TestPrintCallerFunc.func1 caller=caller_test.go:") ||
+		!strings.HasSuffix(buf.String(), " closure\n") {
+		t.Errorf("calling function and file:line should be written. content: %s", buf.String())
+	}
+
+	blog.SetPrintCallerFunc(false)
+	blog.SetPrintCallerLine(false)
+	buf.Reset()
+	blog.writef(INFO, "id %d", 2)
+	blog.flush()
+	if strings.Contains(buf.String(), "func=") || strings.Contains(buf.String(), "caller=") {
+		t.Errorf("caller should not be written by default. content: %s", buf.String())
+	}
+}
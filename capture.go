@@ -0,0 +1,63 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Capture redirects the singleton to memory while fn runs and return lines
+// written, it is designed for asserting logs in tests. The original writer
+// and its level are restored after fn even if it panics. Lines are captured
+// at level of the original writer, or all of them without one
+func Capture(fn func()) (lines []string) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+
+	singltonLock.Lock()
+	original := blog()
+	var level LevelType
+	if nil != original {
+		level = original.Level()
+		writer.SetLevel(level)
+	}
+	setBlog(writer)
+	captured := singleton.Load().(*singletonHolder)
+	singltonLock.Unlock()
+
+	defer func() {
+		singltonLock.Lock()
+		if nil != original {
+			original.SetLevel(level)
+		}
+		setBlog(original)
+		singltonLock.Unlock()
+
+		// logging calls in flight on the capture writer return before it
+		// is closed, as ReplaceSingleton does
+		captured.wait()
+		writer.Close()
+		if 0 < buf.Len() {
+			lines = strings.Split(strings.TrimSuffix(buf.String(), string(EOL)), string(EOL))
+		}
+	}()
+
+	fn()
+	return
+}
+
+// newCaptureWriter create a writer writing to buf, not singlton
+func newCaptureWriter(buf *bytes.Buffer) (captureWriter *ConsoleWriter) {
+	captureWriter = new(ConsoleWriter)
+	captureWriter.blog = NewBLog(buf)
+	captureWriter.blog.name = "capture"
+	captureWriter.closed = false
+	captureWriter.colored = false
+
+	// log hook
+	captureWriter.hook = nil
+	captureWriter.hookLevel = DEBUG
+	captureWriter.hookAsync = true
+	return captureWriter
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCapture(t *testing.T) {
+	singltonLock.Lock()
+	saved := blog()
+	sink := newMySink()
+	original := NewSinkWriter(sink).SetLevel(INFO)
+	setBlog(original)
+	singltonLock.Unlock()
+
+	defer func() {
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}()
+
+	lines := Capture(func() {
+		Debug("filtered by level of the original writer")
+		Info("info")
+		Warnf("warn %d", 1)
+		Error("error")
+	})
+
+	expected := []string{"[INFO] info", "[WARN] warn 1", "[ERROR] error"}
+	if len(expected) != len(lines) {
+		t.Fatalf("lines captured wrong. got: %q", lines)
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("line captured wrong. expected: %s, got: %s", expected[i], line)
+		}
+	}
+
+	if original != blog() {
+		t.Error("original writer not restored")
+	}
+	if 0 != len(sink.messages) {
+		t.Errorf("captured lines should not reach the original writer. got: %q", sink.messages)
+	}
+
+	// restored even if fn panics, level changed inside is restored too, and
+	// the capture writer is closed
+	timeCache.lock.RLock()
+	refs := timeCache.refs
+	timeCache.lock.RUnlock()
+	func() {
+		defer func() {
+			if nil == recover() {
+				t.Error("panic should be propagated")
+			}
+		}()
+		Capture(func() {
+			SetLevel(TRACE)
+			panic("boom")
+		})
+	}()
+
+	if original != blog() || INFO != original.Level() {
+		t.Errorf("original writer not restored after panic. level: %s", original.Level().String())
+	}
+	timeCache.lock.RLock()
+	if refs != timeCache.refs {
+		t.Errorf("capture writer should be closed after panic. before: %d, after: %d", refs, timeCache.refs)
+	}
+	timeCache.lock.RUnlock()
+}
+
+func TestCaptureWaitsInFlight(t *testing.T) {
+	singltonLock.Lock()
+	saved := blog()
+	setBlog(NewSinkWriter(newMySink()))
+	singltonLock.Unlock()
+
+	defer func() {
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}()
+
+	// logging in flight when fn returns still writes to the open writer
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	lines := Capture(func() {
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					Info("line")
+				}
+			}
+		}()
+		time.Sleep(10 * time.Millisecond)
+	})
+	close(stop)
+	<-done
+
+	if 0 == len(lines) {
+		t.Error("lines written before restored should be captured")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock interface tells the current time. The time cache only refreshes
+// every second, helpers measuring durations read time from a Clock instead,
+// which can be replaced with a fake one in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads time from the system
+type realClock struct{}
+
+// Now return the current system time
+func (c realClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	// clock is the Clock used by helpers measuring durations
+	clock Clock = realClock{}
+
+	// clockLock protects clock
+	clockLock = new(sync.RWMutex)
+)
+
+// SetClock replaces the clock used for measuring durations.
+// nil restores the system clock
+func SetClock(c Clock) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+
+	if nil == c {
+		c = realClock{}
+	}
+	clock = c
+}
+
+// now return the current time of clock
+func now() time.Time {
+	clockLock.RLock()
+	defer clockLock.RUnlock()
+	return clock.Now()
+}
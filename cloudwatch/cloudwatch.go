@@ -0,0 +1,312 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package cloudwatch provides a blog4go writer delivering messages to an AWS
+// CloudWatch Logs stream. It lives in its own package so that only users who
+// need it depend on AWS, and it depends on CloudWatchAPI only, a client of
+// the AWS SDK can be adapted to it with a few lines.
+package cloudwatch
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+const (
+	// MaxBatchCount is the maximum number of events in a PutLogEvents call
+	MaxBatchCount = 10000
+
+	// MaxBatchSize is the maximum size of events in a PutLogEvents call,
+	// size of an event is its message length plus EventOverhead
+	MaxBatchSize = 1048576
+
+	// MaxBatchSpan is the maximum time span between events in a batch
+	MaxBatchSpan = 24 * time.Hour
+
+	// EventOverhead is the size CloudWatch adds up for every event
+	EventOverhead = 26
+
+	// MaxEventSize is the maximum size of an event, longer messages are
+	// truncated
+	MaxEventSize = 262144
+
+	// DefaultMaxDelay is the default maximum time an event waits in a batch
+	DefaultMaxDelay = 5 * time.Second
+
+	// pendingBatches is the number of full batches waiting to be put,
+	// logging blocks when it is reached
+	pendingBatches = 4
+)
+
+// Option configures the cloudwatch writer
+type Option func(s *sink)
+
+// WithMaxDelay sets maximum time an event waits in a batch
+func WithMaxDelay(delay time.Duration) Option {
+	return func(s *sink) {
+		if 0 < delay {
+			s.maxDelay = delay
+		}
+	}
+}
+
+// InputLogEvent is a log event sent to CloudWatch, Timestamp is in
+// milliseconds since epoch
+type InputLogEvent struct {
+	Timestamp int64
+	Message   string
+}
+
+// PutLogEventsInput is the input of a PutLogEvents call,
+// SequenceToken is empty for the first call to a new stream
+type PutLogEventsInput struct {
+	LogGroupName  string
+	LogStreamName string
+	SequenceToken string
+	LogEvents     []InputLogEvent
+}
+
+// PutLogEventsOutput is the output of a PutLogEvents call
+type PutLogEventsOutput struct {
+	NextSequenceToken string
+}
+
+// InvalidSequenceTokenError should be returned by CloudWatchAPI when the
+// sequence token is rejected, the batch is sent again with
+// ExpectedSequenceToken
+type InvalidSequenceTokenError struct {
+	ExpectedSequenceToken string
+}
+
+func (err *InvalidSequenceTokenError) Error() string {
+	return fmt.Sprintf("cloudwatch: invalid sequence token, expected: %s", err.ExpectedSequenceToken)
+}
+
+// CloudWatchAPI is the part of CloudWatch Logs client used by the writer
+type CloudWatchAPI interface {
+	PutLogEvents(input *PutLogEventsInput) (*PutLogEventsOutput, error)
+}
+
+// batch is a batch of events swapped out of sink, done receives the error
+// of the put if it is not nil
+type batch struct {
+	events []InputLogEvent
+	done   chan error
+}
+
+// byTimestamp sorts events of a batch in chronological order, as required
+// by PutLogEvents
+type byTimestamp []InputLogEvent
+
+func (events byTimestamp) Len() int           { return len(events) }
+func (events byTimestamp) Less(i, j int) bool { return events[i].Timestamp < events[j].Timestamp }
+func (events byTimestamp) Swap(i, j int)      { events[i], events[j] = events[j], events[i] }
+
+// sink batches messages and puts them to a log stream in background, so that
+// logging never waits for CloudWatch under the lock
+type sink struct {
+	group  string
+	stream string
+	client CloudWatchAPI
+
+	// sequence token is owned by the background goroutine
+	token string
+
+	events []InputLogEvent
+	size   int
+	// oldest and newest timestamp of events, events may go backwards
+	oldest int64
+	newest int64
+
+	// batches swapped out wait to be put by the background goroutine
+	batches chan *batch
+
+	// batch is put every maxDelay until stop is closed, error of the put
+	// is returned by the following Emit or Flush
+	maxDelay time.Duration
+	stop     chan struct{}
+	exited   chan struct{}
+	err      error
+
+	lock *sync.Mutex
+}
+
+// Emit appends message to current batch, the batch is put to CloudWatch
+// before it exceeds count, size or time span limit
+func (s *sink) Emit(t time.Time, level blog4go.LevelType, message string) error {
+	// time is carried by the event, only level is kept in the message
+	message = "[" + level.String() + "] " + message
+	if len(message)+EventOverhead > MaxEventSize {
+		// cut at a rune boundary, CloudWatch rejects invalid UTF-8
+		n := MaxEventSize - EventOverhead
+		for 0 < n && !utf8.RuneStart(message[n]) {
+			n--
+		}
+		message = message[:n]
+	}
+
+	event := InputLogEvent{Timestamp: t.UnixNano() / int64(time.Millisecond), Message: message}
+
+	s.lock.Lock()
+	var full []InputLogEvent
+	if 0 < len(s.events) && (len(s.events)+1 > MaxBatchCount ||
+		s.size+len(message)+EventOverhead > MaxBatchSize ||
+		event.Timestamp-s.oldest >= int64(MaxBatchSpan/time.Millisecond) ||
+		s.newest-event.Timestamp >= int64(MaxBatchSpan/time.Millisecond)) {
+		full = s.swap()
+	}
+
+	if 0 == len(s.events) || event.Timestamp < s.oldest {
+		s.oldest = event.Timestamp
+	}
+	if 0 == len(s.events) || event.Timestamp > s.newest {
+		s.newest = event.Timestamp
+	}
+	s.events = append(s.events, event)
+	s.size += len(message) + EventOverhead
+
+	err := s.err
+	s.err = nil
+	s.lock.Unlock()
+
+	if nil != full {
+		s.batches <- &batch{events: full}
+	}
+	return err
+}
+
+// swap takes current batch out, it is called under lock
+func (s *sink) swap() []InputLogEvent {
+	events := s.events
+	s.events = nil
+	s.size = 0
+	return events
+}
+
+// loop puts batches swapped out, and current batch every maxDelay, until
+// stop is closed. batches are put one by one so that sequence token is
+// always the latest
+func (s *sink) loop(stop chan struct{}) {
+	defer close(s.exited)
+
+	t := time.NewTicker(s.maxDelay)
+	defer t.Stop()
+
+	for {
+		select {
+		case b := <-s.batches:
+			err := s.put(b.events)
+			if nil != b.done {
+				b.done <- err
+			} else if nil != err {
+				s.fail(err)
+			}
+		case <-t.C:
+			s.lock.Lock()
+			events := s.swap()
+			s.lock.Unlock()
+
+			if err := s.put(events); nil != err {
+				s.fail(err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// fail keeps err of a put in background for the following Emit or Flush
+func (s *sink) fail(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+}
+
+// put sends events sorted by timestamp, events are dropped even if it fails
+// so that a broken stream would not hold messages in memory
+func (s *sink) put(events []InputLogEvent) error {
+	if 0 == len(events) {
+		return nil
+	}
+	sort.Stable(byTimestamp(events))
+
+	input := &PutLogEventsInput{
+		LogGroupName:  s.group,
+		LogStreamName: s.stream,
+		SequenceToken: s.token,
+		LogEvents:     events,
+	}
+
+	output, err := s.client.PutLogEvents(input)
+	if invalid, ok := err.(*InvalidSequenceTokenError); ok {
+		// token is out of date, e.g. another process writes to the stream
+		input.SequenceToken = invalid.ExpectedSequenceToken
+		output, err = s.client.PutLogEvents(input)
+	}
+	if nil != err {
+		return err
+	}
+
+	s.token = output.NextSequenceToken
+	return nil
+}
+
+// Flush puts current batch to CloudWatch, it waits for batches swapped out
+// before it as well
+func (s *sink) Flush() error {
+	s.lock.Lock()
+	b := &batch{events: s.swap(), done: make(chan error, 1)}
+	s.lock.Unlock()
+
+	s.batches <- b
+	err := <-b.done
+	if nil == err {
+		s.lock.Lock()
+		err, s.err = s.err, nil
+		s.lock.Unlock()
+	}
+	return err
+}
+
+// Close stops the background goroutine, the client is owned by the caller.
+// the final batch is put by Flush when the writer is closed, the sink is not
+// used afterwards
+func (s *sink) Close() error {
+	s.lock.Lock()
+	stop := s.stop
+	s.stop = nil
+	s.lock.Unlock()
+
+	if nil != stop {
+		close(stop)
+		<-s.exited
+	}
+	return nil
+}
+
+// NewCloudWatchWriter creates a writer putting messages to log stream of
+// group with client. messages are batched and put every max delay, when the
+// writer is flushed, drained or closed, or when the batch reaches limits of
+// PutLogEvents
+func NewCloudWatchWriter(group, stream string, client CloudWatchAPI, opts ...Option) blog4go.Writer {
+	s := &sink{
+		group:    group,
+		stream:   stream,
+		client:   client,
+		batches:  make(chan *batch, pendingBatches),
+		maxDelay: DefaultMaxDelay,
+		stop:     make(chan struct{}),
+		exited:   make(chan struct{}),
+		lock:     new(sync.Mutex),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.loop(s.stop)
+	return blog4go.NewSinkWriter(s)
+}
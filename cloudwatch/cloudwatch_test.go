@@ -0,0 +1,200 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package cloudwatch
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// fakeClient keeps every batch put and issues sequence tokens as
+// CloudWatch does
+type fakeClient struct {
+	inputs []PutLogEventsInput
+	token  int
+
+	// reject the token of next call
+	reject bool
+
+	l *sync.Mutex
+}
+
+func (c *fakeClient) PutLogEvents(input *PutLogEventsInput) (*PutLogEventsOutput, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	expected := ""
+	if 0 < c.token {
+		expected = fmt.Sprintf("token-%d", c.token)
+	}
+	if c.reject || expected != input.SequenceToken {
+		c.reject = false
+		return nil, &InvalidSequenceTokenError{ExpectedSequenceToken: expected}
+	}
+
+	c.inputs = append(c.inputs, *input)
+	c.token++
+	return &PutLogEventsOutput{NextSequenceToken: fmt.Sprintf("token-%d", c.token)}, nil
+}
+
+func TestCloudWatchWriter(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client)
+
+	writer.SetLevel(blog4go.INFO)
+	writer.Debug("dropped")
+	writer.Infof("hello %s", "eddie")
+	writer.Error("boom")
+
+	if 0 != len(client.inputs) {
+		t.Fatal("messages should be batched before flush.")
+	}
+
+	if err := writer.Drain(); nil != err {
+		t.Fatalf("drain failed. err: %s", err.Error())
+	}
+	if 1 != len(client.inputs) {
+		t.Fatalf("batches count wrong. count: %d", len(client.inputs))
+	}
+
+	input := client.inputs[0]
+	if "group" != input.LogGroupName || "stream" != input.LogStreamName || "" != input.SequenceToken {
+		t.Errorf("first batch input wrong. input: %+v", input)
+	}
+	if 2 != len(input.LogEvents) || "[INFO] hello eddie" != input.LogEvents[0].Message || "[ERROR] boom" != input.LogEvents[1].Message {
+		t.Errorf("batch events wrong. events: %+v", input.LogEvents)
+	}
+	if 0 == input.LogEvents[0].Timestamp {
+		t.Error("event timestamp not set.")
+	}
+
+	// the final batch is put when closing, with token returned by last call
+	writer.Info("last")
+	writer.Close()
+	if 2 != len(client.inputs) || "token-1" != client.inputs[1].SequenceToken {
+		t.Fatalf("final batch should be put with next token. inputs: %+v", client.inputs)
+	}
+}
+
+func TestCloudWatchWriterLimits(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client)
+
+	for i := 0; i < MaxBatchCount+1; i++ {
+		writer.Info(i)
+	}
+	writer.Drain()
+	if 2 != len(client.inputs) || MaxBatchCount != len(client.inputs[0].LogEvents) {
+		t.Fatalf("batch should be put when reaching count limit. batches: %d", len(client.inputs))
+	}
+
+	// only 5 messages fit in a batch
+	message := strings.Repeat("x", 200000)
+	for i := 0; i < 6; i++ {
+		writer.Info(message)
+	}
+	writer.Drain()
+	if 4 != len(client.inputs) || 5 != len(client.inputs[2].LogEvents) || 1 != len(client.inputs[3].LogEvents) {
+		t.Fatalf("batch should be put before exceeding size limit. batches: %d", len(client.inputs))
+	}
+
+	// too long message is truncated
+	writer.Info(strings.Repeat("x", MaxEventSize))
+	writer.Drain()
+	if MaxEventSize-EventOverhead != len(client.inputs[4].LogEvents[0].Message) {
+		t.Errorf("long message should be truncated. length: %d", len(client.inputs[4].LogEvents[0].Message))
+	}
+
+	writer.Close()
+}
+
+func TestCloudWatchWriterSequenceToken(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client)
+	defer writer.Close()
+
+	writer.Info("first")
+	writer.Drain()
+
+	// token rejected, e.g. another process writes to the stream
+	client.token = 5
+	writer.Info("second")
+	if err := writer.Drain(); nil != err {
+		t.Fatalf("batch should be sent again with expected token. err: %s", err.Error())
+	}
+	if 2 != len(client.inputs) || "token-5" != client.inputs[1].SequenceToken {
+		t.Errorf("batch should be sent with expected token. inputs: %+v", client.inputs)
+	}
+}
+
+func TestCloudWatchWriterMaxDelay(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client, WithMaxDelay(10*time.Millisecond))
+
+	writer.Info("waiting")
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.l.Lock()
+		n := len(client.inputs)
+		client.l.Unlock()
+		if 1 == n {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batch should be put after max delay")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// no batch is put after the writer is closed
+	writer.Close()
+	writer.Info("dropped")
+	time.Sleep(30 * time.Millisecond)
+	client.l.Lock()
+	if 1 != len(client.inputs) {
+		t.Errorf("batch should not be put after close. batches: %d", len(client.inputs))
+	}
+	client.l.Unlock()
+}
+
+func TestCloudWatchWriterTimestampOrder(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client)
+
+	now := time.Now()
+	writer.LogAt(now, blog4go.INFO, "second")
+	writer.LogAt(now.Add(-time.Second), blog4go.INFO, "first")
+	writer.LogAt(now.Add(time.Second), blog4go.INFO, "third")
+
+	// a day back from the newest event starts a new batch
+	writer.LogAt(now.Add(-MaxBatchSpan), blog4go.INFO, "yesterday")
+	writer.Close()
+
+	if 2 != len(client.inputs) || 3 != len(client.inputs[0].LogEvents) || 1 != len(client.inputs[1].LogEvents) {
+		t.Fatalf("batch should be split by time span. inputs: %+v", client.inputs)
+	}
+	events := client.inputs[0].LogEvents
+	if "[INFO] first" != events[0].Message || "[INFO] second" != events[1].Message || "[INFO] third" != events[2].Message {
+		t.Errorf("events should be put in chronological order. events: %+v", events)
+	}
+}
+
+func TestCloudWatchWriterTruncateRune(t *testing.T) {
+	client := &fakeClient{l: new(sync.Mutex)}
+	writer := NewCloudWatchWriter("group", "stream", client)
+
+	// "[INFO] " is 7 bytes, a 2 bytes rune crosses the limit
+	writer.Info(strings.Repeat("é", MaxEventSize/2))
+	writer.Close()
+
+	message := client.inputs[0].LogEvents[0].Message
+	if !utf8.ValidString(message) || len(message) > MaxEventSize-EventOverhead {
+		t.Errorf("message should be truncated at a rune boundary. length: %d", len(message))
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/xml"
 	"errors"
 	"io/ioutil"
+	"net"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 const (
@@ -14,6 +17,9 @@ const (
 	TypeTimeBaseRotate = "time"
 	// TypeSizeBaseRotate is size base logrotate tag
 	TypeSizeBaseRotate = "size"
+
+	// ValidateDialTimeout is timeout of dialing sockets in ValidateConfig
+	ValidateDialTimeout = 3 * time.Second
 )
 
 var (
@@ -33,12 +39,18 @@ var (
 	ErrConfigSocketNetworkNotFound = errors.New("Please define a socket network type")
 	// ErrConfigMissingFilterType miss a filter configuration
 	ErrConfigMissingFilterType = errors.New("Missing filter configuration")
+	// ErrConfigBadTimeFormat time format is not a valid time layout
+	ErrConfigBadTimeFormat = errors.New("Bad time format")
 )
 
 // Config struct define the config struct used for file wirter
 type Config struct {
 	Filters  []filter `xml:"filter"`
 	MinLevel string   `xml:"minlevel,attr"`
+	// MaxOpenFiles bounds log files kept open, see SetMaxOpenFiles
+	MaxOpenFiles int `xml:"maxOpenFiles,attr"`
+	// TimeFormat is layout of time in message prefix, see SetTimeFormat
+	TimeFormat string `xml:"timeFormat,attr"`
 }
 
 // log filter
@@ -56,11 +68,12 @@ type file struct {
 }
 
 type rotateFile struct {
-	Path        string `xml:"path,attr"`
-	Type        string `xml:"type,attr"`
-	RotateLines int    `xml:"rotateLines,attr"`
-	RotateSize  int64  `xml:"rotateSize,attr"`
-	Retentions  int64  `xml:"retentions,attr"`
+	Path         string `xml:"path,attr"`
+	Type         string `xml:"type,attr"`
+	RotateLines  int    `xml:"rotateLines,attr"`
+	RotateSize   int64  `xml:"rotateSize,attr"`
+	Retentions   int64  `xml:"retentions,attr"`
+	MaxTotalSize int64  `xml:"maxTotalSize,attr"`
 }
 
 //type console struct {
@@ -79,6 +92,10 @@ func (config *Config) valid() error {
 		return ErrConfigBadAttributes
 	}
 
+	if "" != config.TimeFormat && !validTimeFormat(config.TimeFormat) {
+		return ErrConfigBadTimeFormat
+	}
+
 	// check filters len
 	if len(config.Filters) < 1 {
 		return ErrConfigFiltersNotFound
@@ -119,6 +136,68 @@ func (config *Config) valid() error {
 	return nil
 }
 
+// validTimeFormat checks layout contains time elements, and time formatted
+// with it can be parsed back
+func validTimeFormat(layout string) bool {
+	formatted := time.Date(2016, 7, 17, 8, 5, 2, 123456789, time.Local).Format(layout)
+	if formatted == layout {
+		return false
+	}
+
+	_, err := time.Parse(layout, formatted)
+	return nil == err
+}
+
+// ValidateConfig checks config without applying it, as a dry run before
+// switching to it. Besides attributes, directories of log files must be
+// writable, which is checked by creating and removing a temp file in them,
+// and sockets must be reachable in ValidateDialTimeout. The first problem
+// found is returned, nothing is left behind
+func ValidateConfig(config Config) error {
+	if err := config.valid(); nil != err {
+		return err
+	}
+
+	for _, filter := range config.Filters {
+		var err error
+
+		if (file{}) != filter.File {
+			err = checkWritable(filter.File.Path)
+		} else if (rotateFile{}) != filter.RotateFile {
+			err = checkWritable(filter.RotateFile.Path)
+		} else {
+			err = checkReachable(filter.Socket.Network, filter.Socket.Address)
+		}
+
+		if nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkWritable creates and removes a temp file in directory of fileName
+func checkWritable(fileName string) error {
+	f, err := ioutil.TempFile(filepath.Dir(fileName), ".blog4go-validate")
+	if nil != err {
+		return err
+	}
+
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkReachable dials address and closes the connection
+func checkReachable(network, address string) error {
+	conn, err := net.DialTimeout(network, address, ValidateDialTimeout)
+	if nil != err {
+		return err
+	}
+
+	return conn.Close()
+}
+
 // read config from a xml file
 func readConfig(fileName string) (*Config, error) {
 	file, err := os.Open(fileName)
@@ -3,6 +3,9 @@
 package blog4go
 
 import (
+	"io/ioutil"
+	"net"
+	"os"
 	"testing"
 )
 
@@ -148,3 +151,65 @@ func TestConfigValidation(t *testing.T) {
 		t.Error("config missing filter check failed.")
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("/tmp", "validate")
+	if nil != err {
+		t.Fatalf("create dir failed. err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatalf("listen failed. err: %s", err.Error())
+	}
+	defer listener.Close()
+
+	config := Config{
+		MinLevel:   "debug",
+		TimeFormat: "2006-01-02T15:04:05.000",
+		Filters: []filter{
+			{Levels: "debug", File: file{Path: dir + "/debug.log"}},
+			{Levels: "info", RotateFile: rotateFile{Path: dir + "/info.log", Type: TypeSizeBaseRotate}},
+			{Levels: "error", Socket: socket{Network: "tcp", Address: listener.Addr().String()}},
+		},
+	}
+
+	if err := ValidateConfig(config); nil != err {
+		t.Errorf("valid config rejected. err: %s", err.Error())
+	}
+
+	if files, _ := ioutil.ReadDir(dir); 0 != len(files) {
+		t.Errorf("validation left files. got: %d", len(files))
+	}
+
+	// bad time format
+	bad := config
+	bad.TimeFormat = "yyyy-MM-dd"
+	if err := ValidateConfig(bad); ErrConfigBadTimeFormat != err {
+		t.Errorf("bad time format not found. err: %v", err)
+	}
+
+	// non-writable directory, root writes anywhere but /proc
+	readonly := dir + "/readonly"
+	os.Mkdir(readonly, 0555)
+	if 0 == os.Geteuid() {
+		readonly = "/proc"
+	}
+	bad = config
+	bad.Filters = []filter{{Levels: "debug", File: file{Path: readonly + "/debug.log"}}}
+	if err := ValidateConfig(bad); nil == err {
+		t.Error("non-writable directory not found")
+	}
+
+	// unreachable socket
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := closed.Addr().String()
+	closed.Close()
+
+	bad = config
+	bad.Filters = []filter{{Levels: "error", Socket: socket{Network: "tcp", Address: address}}}
+	if err := ValidateConfig(bad); nil == err {
+		t.Error("unreachable socket not found")
+	}
+}
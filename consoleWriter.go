@@ -5,6 +5,7 @@ package blog4go
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"time"
 )
 
@@ -16,6 +17,14 @@ type ConsoleWriter struct {
 
 	colored bool
 
+	// file of fd writers, nil for stdout && stderr
+	file *os.File
+	// fd writers close file when closed, default false
+	closeFD bool
+
+	// stack traces written by ErrorStack
+	stacks stackDedup
+
 	// log hook
 	hook      Hook
 	hookLevel LevelType
@@ -26,7 +35,7 @@ type ConsoleWriter struct {
 func NewConsoleWriter() (err error) {
 	singltonLock.Lock()
 	defer singltonLock.Unlock()
-	if nil != blog {
+	if nil != blog() {
 		return ErrAlreadyInit
 	}
 
@@ -35,7 +44,7 @@ func NewConsoleWriter() (err error) {
 		return err
 	}
 
-	blog = consoleWriter
+	setBlog(consoleWriter)
 	go consoleWriter.daemon()
 	return nil
 }
@@ -44,6 +53,7 @@ func NewConsoleWriter() (err error) {
 func newConsoleWriter() (consoleWriter *ConsoleWriter, err error) {
 	consoleWriter = new(ConsoleWriter)
 	consoleWriter.blog = NewBLog(os.Stdout)
+	consoleWriter.blog.name = "console"
 
 	consoleWriter.closed = false
 
@@ -56,10 +66,44 @@ func newConsoleWriter() (consoleWriter *ConsoleWriter, err error) {
 
 	go consoleWriter.daemon()
 
-	blog = consoleWriter
+	setBlog(consoleWriter)
 	return consoleWriter, nil
 }
 
+// NewConsoleWriterStderr initialize a console writer writing to stderr,
+// singlton. Every line is flushed at once as stderr is expected to, and
+// colored when stderr is a terminal
+func NewConsoleWriterStderr() (err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+	if nil != blog() {
+		return ErrAlreadyInit
+	}
+
+	setBlog(newStderrWriter())
+	return nil
+}
+
+// newStderrWriter initialize a console writer writing to stderr, not singlton
+func newStderrWriter() (stderrWriter *ConsoleWriter) {
+	stderrWriter = new(ConsoleWriter)
+	stderrWriter.blog = NewBLog(os.Stderr)
+	stderrWriter.blog.name = "stderr"
+	stderrWriter.blog.SetFlushLevel(TRACE)
+
+	stderrWriter.closed = false
+
+	stderrWriter.SetColored(isTerminal(os.Stderr))
+
+	// log hook
+	stderrWriter.hook = nil
+	stderrWriter.hookLevel = DEBUG
+	stderrWriter.hookAsync = true
+
+	go stderrWriter.daemon()
+	return stderrWriter
+}
+
 func (writer *ConsoleWriter) daemon() {
 	f := time.Tick(10 * time.Second)
 
@@ -77,47 +121,105 @@ DaemonLoop:
 }
 
 func (writer *ConsoleWriter) write(level LevelType, args ...interface{}) {
+	var size = 0
+
 	if writer.closed {
 		return
 	}
 
 	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, args ...interface{}) {
-					writer.hook.Fire(level, args...)
-				}(level, args...)
-
-			} else {
-				writer.hook.Fire(level, args...)
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, fmt.Sprint(args...))
 		}
 	}()
 
-	writer.blog.write(level, args...)
+	size = writer.blog.write(level, args...)
 }
 
 func (writer *ConsoleWriter) writef(level LevelType, format string, args ...interface{}) {
+	var size = 0
+	// message body written, only kept for log hook
+	var message string
+
 	if writer.closed {
 		return
 	}
 
 	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
 
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, format string, args ...interface{}) {
-					writer.hook.Fire(level, fmt.Sprintf(format, args...))
-				}(level, format, args...)
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+		}
+	}()
 
-			} else {
-				writer.hook.Fire(level, fmt.Sprintf(format, args...))
+	if nil != writer.hook && !(level < writer.hookLevel) {
+		size, message = writer.blog.writefBody(level, format, args...)
+		return
+	}
+	size = writer.blog.writef(level, format, args...)
+}
 
-			}
+func (writer *ConsoleWriter) writeLines(level LevelType, message string) {
+	var size = 0
+
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
 		}
 	}()
 
-	writer.blog.writef(level, format, args...)
+	size = writer.blog.writeLines(level, message)
+}
+
+func (writer *ConsoleWriter) writeJSON(level LevelType, fields map[string]interface{}) {
+	var size = 0
+
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		// message dropped by drop rules
+		if 0 == size {
+			return
+		}
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, jsonFields(fields))
+		}
+	}()
+
+	size = writer.blog.writeJSON(level, fields)
+}
+
+// writeRaw writes message verbatim with RawLevel
+func (writer *ConsoleWriter) writeRaw(message string) {
+	if writer.closed {
+		return
+	}
+
+	defer func() {
+		if nil != writer.hook && !(RawLevel < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), RawLevel, message)
+		}
+	}()
+
+	writer.blog.writeRaw(RawLevel, message)
 }
 
 // Level get level
@@ -125,9 +227,20 @@ func (writer *ConsoleWriter) Level() LevelType {
 	return writer.blog.Level()
 }
 
+// Name get name of the writer, "console" by default
+func (writer *ConsoleWriter) Name() string {
+	return writer.blog.Name()
+}
+
+// SetName set name of the writer
+func (writer *ConsoleWriter) SetName(name string) {
+	writer.blog.SetName(name)
+}
+
 // SetLevel set logger level
-func (writer *ConsoleWriter) SetLevel(level LevelType) {
+func (writer *ConsoleWriter) SetLevel(level LevelType) Writer {
 	writer.blog.SetLevel(level)
+	return writer
 }
 
 // Colored get Colored
@@ -167,9 +280,18 @@ func (writer *ConsoleWriter) Close() {
 		return
 	}
 
-	writer.blog.flush()
+	// closing BLog flushes it and releases the time cache
+	writer.blog.Close()
 	writer.blog = nil
 	writer.closed = true
+
+	if nil != writer.file {
+		if writer.closeFD {
+			writer.file.Close()
+		} else {
+			keepFD(writer.file)
+		}
+	}
 }
 
 // TimeRotated do nothing
@@ -192,6 +314,46 @@ func (writer *ConsoleWriter) SetRetentions(retentions int64) {
 	return
 }
 
+// Rotate do nothing
+func (writer *ConsoleWriter) Rotate() error {
+	return nil
+}
+
+// MaxTotalSize do nothing
+func (writer *ConsoleWriter) MaxTotalSize() int64 {
+	return 0
+}
+
+// SetMaxTotalSize do nothing
+func (writer *ConsoleWriter) SetMaxTotalSize(maxTotalSize int64) {
+	return
+}
+
+// SetRotateHook do nothing
+func (writer *ConsoleWriter) SetRotateHook(hook RotateHook) {
+	return
+}
+
+// FileMode do nothing
+func (writer *ConsoleWriter) FileMode() os.FileMode {
+	return 0
+}
+
+// SetFileMode do nothing
+func (writer *ConsoleWriter) SetFileMode(mode os.FileMode) error {
+	return nil
+}
+
+// SetFileOwner do nothing
+func (writer *ConsoleWriter) SetFileOwner(uid, gid int) error {
+	return nil
+}
+
+// SetOpenBanner do nothing
+func (writer *ConsoleWriter) SetOpenBanner(banner bool) {
+	return
+}
+
 // RotateSize do nothing
 func (writer *ConsoleWriter) RotateSize() int64 {
 	return 0
@@ -212,6 +374,244 @@ func (writer *ConsoleWriter) SetRotateLines(rotateLines int) {
 	return
 }
 
+// StrictFormat get whether it is in strict format mode
+func (writer *ConsoleWriter) StrictFormat() bool {
+	return writer.blog.StrictFormat()
+}
+
+// SetStrictFormat toggle strict format mode
+func (writer *ConsoleWriter) SetStrictFormat(strict bool) {
+	writer.blog.SetStrictFormat(strict)
+}
+
+// SetErrorHandler set handler called when writer meets an error
+func (writer *ConsoleWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.blog.SetErrorHandler(handler)
+}
+
+// SetWriteTimeout do nothing
+func (writer *ConsoleWriter) SetWriteTimeout(d time.Duration) {
+	return
+}
+
+// Placeholder get placeholder character used in formatting
+func (writer *ConsoleWriter) Placeholder() byte {
+	return writer.blog.Placeholder()
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func (writer *ConsoleWriter) SetPlaceholder(placeholder byte) {
+	writer.blog.SetPlaceholder(placeholder)
+}
+
+// SetEOL set character ending every line
+func (writer *ConsoleWriter) SetEOL(eol byte) {
+	writer.blog.SetEOL(eol)
+}
+
+// SetEscape set escape character used in formatting
+func (writer *ConsoleWriter) SetEscape(escape byte) {
+	writer.blog.SetEscape(escape)
+}
+
+// SetTimeFormat set layout of time prefix
+func (writer *ConsoleWriter) SetTimeFormat(layout string) {
+	writer.blog.SetTimeFormat(layout)
+}
+
+// SetTimeFormatPreset set time prefix as a preset format
+func (writer *ConsoleWriter) SetTimeFormatPreset(preset TimePreset) {
+	writer.blog.SetTimeFormatPreset(preset)
+}
+
+// SetFraming set how every line is framed
+func (writer *ConsoleWriter) SetFraming(framing Framing) {
+	writer.blog.SetFraming(framing)
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly
+func (writer *ConsoleWriter) SetSmartTimeVerb(smart bool) {
+	writer.blog.SetSmartTimeVerb(smart)
+}
+
+// SetFormatter set formatter producing the whole line written for every message
+func (writer *ConsoleWriter) SetFormatter(formatter Formatter) {
+	writer.blog.SetFormatter(formatter)
+}
+
+// SetNilString set the token nil args are written as in formatting
+func (writer *ConsoleWriter) SetNilString(s string) {
+	writer.blog.SetNilString(s)
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with recover
+func (writer *ConsoleWriter) SetSafeStringer(safe bool) {
+	writer.blog.SetSafeStringer(safe)
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args
+func (writer *ConsoleWriter) SetRawStringer(raw bool) {
+	writer.blog.SetRawStringer(raw)
+}
+
+// SetDebounce logs messages with format at most once per window
+func (writer *ConsoleWriter) SetDebounce(format string, window time.Duration) {
+	writer.blog.SetDebounce(format, window)
+}
+
+// SetSamplerPolicy logs the first messages of every format in every tick, then only every thereafter-th of them
+func (writer *ConsoleWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	writer.blog.SetSamplerPolicy(first, thereafter, tick)
+}
+
+// SetPrintSequence toggle writing an increasing sequence number ahead every
+// message
+func (writer *ConsoleWriter) SetPrintSequence(sequence bool) {
+	writer.blog.SetPrintSequence(sequence)
+}
+
+// SetPrintElapsed toggle writing milliseconds since start ahead every message
+func (writer *ConsoleWriter) SetPrintElapsed(elapsed bool) {
+	writer.blog.SetPrintElapsed(elapsed)
+}
+
+// SetSequenceWidth set width sequence numbers are zero padded to
+func (writer *ConsoleWriter) SetSequenceWidth(width int) {
+	writer.blog.SetSequenceWidth(width)
+}
+
+// SetDefaultFields sets static fields prepended to every message
+func (writer *ConsoleWriter) SetDefaultFields(fields map[string]string) {
+	writer.blog.SetDefaultFields(fields)
+}
+
+// AddDefaultField appends a static field prepended to every message
+func (writer *ConsoleWriter) AddDefaultField(key, value string) {
+	writer.blog.AddDefaultField(key, value)
+}
+
+// separators return separators in use
+func (writer *ConsoleWriter) separators() *fieldSeparator {
+	return writer.blog.separators()
+}
+
+// SetFieldSeparator set what is written between structured fields
+func (writer *ConsoleWriter) SetFieldSeparator(sep string) error {
+	return writer.blog.SetFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields
+func (writer *ConsoleWriter) SetKVSeparator(sep string) error {
+	return writer.blog.SetKVSeparator(sep)
+}
+
+// SetPrintHostname toggle writing hostname ahead every message
+func (writer *ConsoleWriter) SetPrintHostname(print bool) {
+	writer.blog.SetPrintHostname(print)
+}
+
+// SetHostname overrides hostname written ahead messages
+func (writer *ConsoleWriter) SetHostname(name string) {
+	writer.blog.SetHostname(name)
+}
+
+// SetPrintPID toggle writing process id ahead every message
+func (writer *ConsoleWriter) SetPrintPID(print bool) {
+	writer.blog.SetPrintPID(print)
+}
+
+// SetPrintProgram toggle writing program name ahead every message
+func (writer *ConsoleWriter) SetPrintProgram(print bool) {
+	writer.blog.SetPrintProgram(print)
+}
+
+// SetPrintCallerFunc toggle writing the function calling the logger ahead every message
+func (writer *ConsoleWriter) SetPrintCallerFunc(print bool) {
+	writer.blog.SetPrintCallerFunc(print)
+}
+
+// SetPrintCallerLine toggle writing file:line calling the logger ahead every message
+func (writer *ConsoleWriter) SetPrintCallerLine(print bool) {
+	writer.blog.SetPrintCallerLine(print)
+}
+
+// AddDropSubstring drops messages containing s
+func (writer *ConsoleWriter) AddDropSubstring(s string) {
+	writer.blog.AddDropSubstring(s)
+}
+
+// AddDropRegexp drops messages matching re
+func (writer *ConsoleWriter) AddDropRegexp(re *regexp.Regexp) {
+	writer.blog.AddDropRegexp(re)
+}
+
+// Dropped return number of messages dropped by drop rules
+func (writer *ConsoleWriter) Dropped() int64 {
+	return writer.blog.Dropped()
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately
+func (writer *ConsoleWriter) FlushLevel() LevelType {
+	return writer.blog.FlushLevel()
+}
+
+// SetFlushLevel set the level at or above which messages are flushed immediately
+func (writer *ConsoleWriter) SetFlushLevel(level LevelType) {
+	writer.blog.SetFlushLevel(level)
+}
+
+// SetBufferSize resize buffer keeping bytes buffered
+func (writer *ConsoleWriter) SetBufferSize(size int) {
+	writer.blog.SetBufferSize(size)
+}
+
+// MultilinePrefix get whether every line of a multi-line message is prefixed
+func (writer *ConsoleWriter) MultilinePrefix() bool {
+	return writer.blog.MultilinePrefix()
+}
+
+// SetMultilinePrefix toggle prefixing every line of a multi-line message
+func (writer *ConsoleWriter) SetMultilinePrefix(multiline bool) {
+	writer.blog.SetMultilinePrefix(multiline)
+}
+
+// AtomicWrite get whether every line is written with a single Write call
+func (writer *ConsoleWriter) AtomicWrite() bool {
+	return writer.blog.AtomicWrite()
+}
+
+// SetAtomicWrite toggle atomic mode, it keeps lines whole when stdout is
+// shared with other processes, such as supervisors collecting their output
+func (writer *ConsoleWriter) SetAtomicWrite(atomic bool) {
+	writer.blog.SetAtomicWrite(atomic)
+}
+
+// SetReorderWindow hold lines for d and write them sorted by time
+func (writer *ConsoleWriter) SetReorderWindow(d time.Duration) {
+	writer.blog.SetReorderWindow(d)
+}
+
+// SetDevMode toggle rendering json lines in human readable format on terminal
+func (writer *ConsoleWriter) SetDevMode(dev bool) {
+	writer.blog.SetDevMode(dev)
+}
+
+// Drain flushes buffered logs to console
+func (writer *ConsoleWriter) Drain() error {
+	writer.blog.flush()
+	return nil
+}
+
+// Ping checks whether console is still writable
+func (writer *ConsoleWriter) Ping() error {
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	return writer.blog.Ping()
+}
+
 // flush buffer to disk
 func (writer *ConsoleWriter) flush() {
 	writer.blog.flush()
@@ -219,7 +619,7 @@ func (writer *ConsoleWriter) flush() {
 
 // Trace trace
 func (writer *ConsoleWriter) Trace(args ...interface{}) {
-	if nil == writer.blog || TRACE < writer.blog.Level() {
+	if TRACE < CompileLevel || nil == writer.blog || TRACE < writer.blog.Level() {
 		return
 	}
 
@@ -228,7 +628,7 @@ func (writer *ConsoleWriter) Trace(args ...interface{}) {
 
 // Tracef tracef
 func (writer *ConsoleWriter) Tracef(format string, args ...interface{}) {
-	if nil == writer.blog || TRACE < writer.blog.Level() {
+	if TRACE < CompileLevel || nil == writer.blog || TRACE < writer.blog.Level() {
 		return
 	}
 
@@ -237,7 +637,7 @@ func (writer *ConsoleWriter) Tracef(format string, args ...interface{}) {
 
 // Debug debug
 func (writer *ConsoleWriter) Debug(args ...interface{}) {
-	if nil == writer.blog || DEBUG < writer.blog.Level() {
+	if DEBUG < CompileLevel || nil == writer.blog || DEBUG < writer.blog.Level() {
 		return
 	}
 
@@ -246,34 +646,141 @@ func (writer *ConsoleWriter) Debug(args ...interface{}) {
 
 // Debugf debugf
 func (writer *ConsoleWriter) Debugf(format string, args ...interface{}) {
-	if nil == writer.blog || DEBUG < writer.blog.Level() {
+	if DEBUG < CompileLevel || nil == writer.blog || DEBUG < writer.blog.Level() {
 		return
 	}
 
 	writer.writef(DEBUG, format, args...)
 }
 
+// DebugPretty debug pretty
+func (writer *ConsoleWriter) DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel || nil == writer.blog || DEBUG < writer.blog.Level() {
+		return
+	}
+
+	writer.writeLines(DEBUG, prettyFormat(v))
+}
+
+// ErrorStack writes message with stack trace of the caller, a stack
+// written before is written as its fingerprint
+func (writer *ConsoleWriter) ErrorStack(message string) {
+	if ERROR < CompileLevel || nil == writer.blog || ERROR < writer.blog.Level() {
+		return
+	}
+
+	writer.writeLines(ERROR, writer.stacks.message(message))
+}
+
+// Entry starts building a structured message with specific level
+func (writer *ConsoleWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
 // Info info
 func (writer *ConsoleWriter) Info(args ...interface{}) {
-	if nil == writer.blog || INFO < writer.blog.Level() {
+	if INFO < CompileLevel || nil == writer.blog || INFO < writer.blog.Level() {
 		return
 	}
 
 	writer.write(INFO, args...)
 }
 
+// InfoJSON writes fields as a json line with info level
+func (writer *ConsoleWriter) InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel || nil == writer.blog || INFO < writer.blog.Level() {
+		return
+	}
+
+	writer.writeJSON(INFO, fields)
+}
+
+// WriteBatch writes messages with specific level contiguously, taking the
+// lock once. It return total size written
+func (writer *ConsoleWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	if level < CompileLevel || !level.valid() || nil == writer.blog || level < writer.blog.Level() || writer.closed {
+		return 0
+	}
+
+	for i, s := range writer.blog.writeBatch(level, messages) {
+		// message dropped by drop rules
+		if 0 == s {
+			continue
+		}
+		size += s
+
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, messages[i])
+		}
+	}
+	return
+}
+
+// LogAt writes message with specific level prefixed with time t instead of
+// now
+func (writer *ConsoleWriter) LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel || !level.valid() || nil == writer.blog || level < writer.blog.Level() || writer.closed {
+		return
+	}
+
+	if 0 < writer.blog.writeAt(t, level, message) && nil != writer.hook && !(level < writer.hookLevel) {
+		fireHook(writer.hook, writer.hookAsync, t, level, message)
+	}
+}
+
+// LogfAt formats message with specific level prefixed with time t instead of
+// now
+func (writer *ConsoleWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	if level < CompileLevel || !level.valid() || nil == writer.blog || level < writer.blog.Level() || writer.closed {
+		return
+	}
+
+	if size, message := writer.blog.writefAt(t, level, format, args...); 0 < size && nil != writer.hook && !(level < writer.hookLevel) {
+		fireHook(writer.hook, writer.hookAsync, t, level, message)
+	}
+}
+
+// Raw writes message verbatim without time && level prefix
+func (writer *ConsoleWriter) Raw(message string) {
+	if RawLevel < CompileLevel || nil == writer.blog || RawLevel < writer.blog.Level() {
+		return
+	}
+
+	writer.writeRaw(message)
+}
+
 // Infof infof
 func (writer *ConsoleWriter) Infof(format string, args ...interface{}) {
-	if nil == writer.blog || INFO < writer.blog.Level() {
+	if INFO < CompileLevel || nil == writer.blog || INFO < writer.blog.Level() {
 		return
 	}
 
 	writer.writef(INFO, format, args...)
 }
 
+// InfoSync writes message with info level and flushes it immediately
+func (writer *ConsoleWriter) InfoSync(message string) {
+	if INFO < CompileLevel || nil == writer.blog || INFO < writer.blog.Level() {
+		return
+	}
+
+	writer.write(INFO, message)
+	writer.blog.flush()
+}
+
+// InfofSync formats message with info level and flushes it immediately
+func (writer *ConsoleWriter) InfofSync(format string, args ...interface{}) {
+	if INFO < CompileLevel || nil == writer.blog || INFO < writer.blog.Level() {
+		return
+	}
+
+	writer.writef(INFO, format, args...)
+	writer.blog.flush()
+}
+
 // Warn warn
 func (writer *ConsoleWriter) Warn(args ...interface{}) {
-	if nil == writer.blog || WARNING < writer.blog.Level() {
+	if WARNING < CompileLevel || nil == writer.blog || WARNING < writer.blog.Level() {
 		return
 	}
 
@@ -282,7 +789,7 @@ func (writer *ConsoleWriter) Warn(args ...interface{}) {
 
 // Warnf warnf
 func (writer *ConsoleWriter) Warnf(format string, args ...interface{}) {
-	if nil == writer.blog || WARNING < writer.blog.Level() {
+	if WARNING < CompileLevel || nil == writer.blog || WARNING < writer.blog.Level() {
 		return
 	}
 
@@ -291,7 +798,7 @@ func (writer *ConsoleWriter) Warnf(format string, args ...interface{}) {
 
 // Error error
 func (writer *ConsoleWriter) Error(args ...interface{}) {
-	if nil == writer.blog || ERROR < writer.blog.Level() {
+	if ERROR < CompileLevel || nil == writer.blog || ERROR < writer.blog.Level() {
 		return
 	}
 
@@ -300,7 +807,7 @@ func (writer *ConsoleWriter) Error(args ...interface{}) {
 
 // Errorf errorf
 func (writer *ConsoleWriter) Errorf(format string, args ...interface{}) {
-	if nil == writer.blog || ERROR < writer.blog.Level() {
+	if ERROR < CompileLevel || nil == writer.blog || ERROR < writer.blog.Level() {
 		return
 	}
 
@@ -309,7 +816,7 @@ func (writer *ConsoleWriter) Errorf(format string, args ...interface{}) {
 
 // Critical critical
 func (writer *ConsoleWriter) Critical(args ...interface{}) {
-	if nil == writer.blog || CRITICAL < writer.blog.Level() {
+	if CRITICAL < CompileLevel || nil == writer.blog || CRITICAL < writer.blog.Level() {
 		return
 	}
 
@@ -318,7 +825,7 @@ func (writer *ConsoleWriter) Critical(args ...interface{}) {
 
 // Criticalf criticalf
 func (writer *ConsoleWriter) Criticalf(format string, args ...interface{}) {
-	if nil == writer.blog || CRITICAL < writer.blog.Level() {
+	if CRITICAL < CompileLevel || nil == writer.blog || CRITICAL < writer.blog.Level() {
 		return
 	}
 
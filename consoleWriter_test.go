@@ -3,6 +3,10 @@
 package blog4go
 
 import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -17,11 +21,11 @@ func TestConsoleWriterBasicOperation(t *testing.T) {
 	// test console writer hook
 	hook := NewMyHook()
 
-	blog.SetHook(hook)
-	blog.SetHookLevel(INFO)
+	blog().SetHook(hook)
+	blog().SetHookLevel(INFO)
 
-	blog.Debug("something")
-	blog.Debugf("%s", "something")
+	blog().Debug("something")
+	blog().Debugf("%s", "something")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 0 != hook.Cnt() {
@@ -33,7 +37,7 @@ func TestConsoleWriterBasicOperation(t *testing.T) {
 	}
 
 	// async
-	blog.Info("yes")
+	blog().Info("yes")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 1 != hook.Cnt() {
@@ -45,8 +49,8 @@ func TestConsoleWriterBasicOperation(t *testing.T) {
 	}
 
 	// sync
-	blog.SetHookAsync(false)
-	blog.Warn("warn")
+	blog().SetHookAsync(false)
+	blog().Warn("warn")
 	// wait for hook called
 	if 2 != hook.Cnt() {
 		t.Error("hook not called")
@@ -57,49 +61,49 @@ func TestConsoleWriterBasicOperation(t *testing.T) {
 	}
 
 	// test basic operations
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-	blog.flush()
-
-	blog.Colored()
-	blog.SetColored(true)
-	blog.TimeRotated()
-	blog.SetTimeRotated(true)
-	blog.Level()
-	blog.SetLevel(CRITICAL)
-	blog.Retentions()
-	blog.SetRetentions(7)
-	blog.RotateLines()
-	blog.SetRotateLines(100000)
-	blog.RotateSize()
-	blog.SetRotateSize(1024 * 1024 * 500)
-
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s\\", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-
-	blog.Close()
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+	blog().flush()
+
+	blog().Colored()
+	blog().SetColored(true)
+	blog().TimeRotated()
+	blog().SetTimeRotated(true)
+	blog().Level()
+	blog().SetLevel(CRITICAL)
+	blog().Retentions()
+	blog().SetRetentions(7)
+	blog().RotateLines()
+	blog().SetRotateLines(100000)
+	blog().RotateSize()
+	blog().SetRotateSize(1024 * 1024 * 500)
+
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s\\", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+
+	blog().Close()
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
 }
 
 func TestSingleConsoleWriter(t *testing.T) {
@@ -126,6 +130,116 @@ func BenchmarkConsoleWriter(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		blog.Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+		blog().Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+	}
+}
+
+func TestConsoleWriterAtomicWrite(t *testing.T) {
+	// newConsoleWriter replaces the singleton, restore it after test
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	r, w, err := os.Pipe()
+	if nil != err {
+		t.Fatalf("create pipe failed. err: %s", err.Error())
+	}
+	defer r.Close()
+
+	// two console writers share the same output like two processes sharing
+	// stdout, lines straddling the bufio buffer boundary would interleave
+	// unless every line is written with a single Write call
+	lineLength := DefaultBufferSize * 3 / 4
+	writers := make([]*ConsoleWriter, 2)
+	for i := range writers {
+		writer, err := newConsoleWriter()
+		if nil != err {
+			t.Fatalf("new console writer failed. err: %s", err.Error())
+		}
+		writer.blog = NewBLog(w)
+		writer.SetAtomicWrite(true)
+		if !writer.AtomicWrite() {
+			t.Fatal("atomic mode should be on.")
+		}
+		writers[i] = writer
+	}
+
+	var wg sync.WaitGroup
+	for i, writer := range writers {
+		wg.Add(2)
+		for j := 0; j < 2; j++ {
+			go func(writer *ConsoleWriter, message string) {
+				defer wg.Done()
+				for k := 0; k < 100; k++ {
+					writer.Info(message)
+				}
+			}(writer, strings.Repeat(string('a'+byte(2*i+j)), lineLength))
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		for _, writer := range writers {
+			writer.flush()
+		}
+		w.Close()
+	}()
+
+	lines := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 2*DefaultBufferSize), 2*DefaultBufferSize)
+	for scanner.Scan() {
+		lines++
+		line := scanner.Text()
+		message := line[strings.LastIndex(line, " ")+1:]
+		if lineLength != len(message) || strings.Count(message, message[:1]) != lineLength {
+			t.Fatalf("lines interleaved. line length: %d", len(line))
+		}
+	}
+
+	if 400 != lines {
+		t.Errorf("line count wrong. lines: %d", lines)
+	}
+
+	for _, writer := range writers {
+		writer.Close()
+	}
+}
+
+func TestConsoleWriterStdoutStderr(t *testing.T) {
+	// newConsoleWriter replaces the singleton, restore it after test
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+	defer func(stdout, stderr *os.File) { os.Stdout, os.Stderr = stdout, stderr }(os.Stdout, os.Stderr)
+	initPrefix(false)
+
+	stdoutR, stdoutW, err := os.Pipe()
+	if nil != err {
+		t.Fatalf("create pipe failed. err: %s", err.Error())
+	}
+	defer stdoutR.Close()
+	stderrR, stderrW, err := os.Pipe()
+	if nil != err {
+		t.Fatalf("create pipe failed. err: %s", err.Error())
+	}
+	defer stderrR.Close()
+	os.Stdout, os.Stderr = stdoutW, stderrW
+
+	stdoutWriter, err := newConsoleWriter()
+	if nil != err {
+		t.Fatalf("new console writer failed. err: %s", err.Error())
+	}
+	stdoutWriter.Info("hi")
+	stdoutWriter.Close()
+	stdoutW.Close()
+
+	// stderr writer flushes every line at once
+	stderrWriter := newStderrWriter()
+	stderrWriter.Info("oops")
+	stderrW.Close()
+	defer stderrWriter.Close()
+
+	if line, _ := bufio.NewReader(stdoutR).ReadString(EOL); !strings.HasSuffix(line, "[INFO] hi\n") {
+		t.Errorf("console writer should write to stdout. got: %q", line)
+	}
+	if line, _ := bufio.NewReader(stderrR).ReadString(EOL); !strings.HasSuffix(line, "[INFO] oops\n") {
+		t.Errorf("stderr writer should write to stderr at once. got: %q", line)
 	}
 }
@@ -0,0 +1,131 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// DebounceFormat is the format of summary written at the end of a
+	// debounce window, with count of messages suppressed, the window and the
+	// format string debounced
+	DebounceFormat = "last message repeated %d times in %s: %s"
+)
+
+// debounceRule logs messages of a format at most once per window
+type debounceRule struct {
+	window time.Duration
+
+	// messages before until are suppressed
+	until time.Time
+	// suppressed messages and level of the last one in current window
+	suppressed int
+	level      LevelType
+	// timer writing summary at the end of current window
+	timer *time.Timer
+}
+
+// debounced determines whether a message with format should be suppressed,
+// it is called under lock of BLog
+func (blog *BLog) debounced(level LevelType, format string) bool {
+	rule, ok := blog.debounces[format]
+	if !ok {
+		return false
+	}
+
+	t := now()
+	if !t.Before(rule.until) {
+		// a new window starts with the message written
+		rule.until = t.Add(rule.window)
+		return false
+	}
+
+	rule.suppressed++
+	rule.level = level
+	if nil == rule.timer {
+		rule.timer = time.AfterFunc(rule.until.Sub(t), func() {
+			blog.summarize(format, rule)
+		})
+	}
+	return true
+}
+
+// summarize writes count of messages suppressed in the window just ended
+func (blog *BLog) summarize(format string, rule *debounceRule) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	rule.timer = nil
+	if blog.closed {
+		return
+	}
+	blog.writeSummary(format, rule)
+}
+
+// summarizeAll writes summaries of all rules with messages suppressed, so
+// that they are written into the file before rotation rather than the next
+// one. it is called under lock of BLog
+func (blog *BLog) summarizeAll() {
+	formats := make([]string, 0, len(blog.debounces))
+	for format, rule := range blog.debounces {
+		if 0 != rule.suppressed {
+			formats = append(formats, format)
+		}
+	}
+	sort.Strings(formats)
+
+	for _, format := range formats {
+		rule := blog.debounces[format]
+		if nil != rule.timer {
+			rule.timer.Stop()
+			rule.timer = nil
+		}
+		blog.writeSummary(format, rule)
+	}
+}
+
+// writeSummary writes count of messages suppressed by rule and resets it,
+// it is called under lock of BLog
+func (blog *BLog) writeSummary(format string, rule *debounceRule) {
+	if 0 == rule.suppressed {
+		return
+	}
+
+	suppressed := rule.suppressed
+	rule.suppressed = 0
+	blog.writeMessage(rule.level, fmt.Sprintf(DebounceFormat, suppressed, rule.window, format))
+}
+
+// SetDebounce logs messages with format at most once per window, messages
+// coming within the window are suppressed and counted, and a summary in
+// DebounceFormat is written at the end of the window. It is designed for
+// errors repeating while a dependency flaps, messages are matched by format
+// string of writef functions such as Errorf. window <= 0 removes the rule
+func (blog *BLog) SetDebounce(format string, window time.Duration) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if rule, ok := blog.debounces[format]; ok && nil != rule.timer {
+		rule.timer.Stop()
+	}
+
+	if window <= 0 {
+		delete(blog.debounces, format)
+		return blog
+	}
+
+	if nil == blog.debounces {
+		blog.debounces = make(map[string]*debounceRule)
+	}
+	blog.debounces[format] = &debounceRule{window: window}
+	return blog
+}
+
+// SetDebounce logs messages with format of the singleton writer at most
+// once per window
+func SetDebounce(format string, window time.Duration) {
+	blog().SetDebounce(format, window)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBLogDebounce(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	window := 100 * time.Millisecond
+	blog.SetDebounce("dial %s failed", window)
+
+	for i := 0; i < 5; i++ {
+		blog.writef(ERROR, "dial %s failed", "db")
+	}
+	blog.writef(INFO, "not debounced %d", 1)
+	blog.writef(INFO, "not debounced %d", 2)
+
+	blog.flush()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if 3 != len(lines) || !strings.HasSuffix(lines[0], " [ERROR] dial db failed") {
+		t.Fatalf("only one line should be written within the window. content: %s", buf.String())
+	}
+
+	// summary is written at the end of window
+	time.Sleep(2 * window)
+	blog.writef(ERROR, "dial %s failed", "cache")
+	blog.flush()
+
+	lines = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	summary := fmt.Sprintf(DebounceFormat, 4, window, "dial %s failed")
+	if 5 != len(lines) || !strings.HasSuffix(lines[3], " [ERROR] "+summary) || !strings.HasSuffix(lines[4], " [ERROR] dial cache failed") {
+		t.Errorf("summary should be written at the end of window and a new window starts. content: %s", buf.String())
+	}
+
+	// rule removed
+	blog.SetDebounce("dial %s failed", 0)
+	blog.writef(ERROR, "dial %s failed", "cache")
+	blog.flush()
+	if 6 != strings.Count(buf.String(), "\n") {
+		t.Errorf("messages should not be debounced after rule removed. content: %s", buf.String())
+	}
+}
+
+func TestDebounceAcrossRotation(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/debounce.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.SetRetentions(2)
+	writer.SetDebounce("dial %s failed", time.Hour)
+	for i := 0; i < 4; i++ {
+		writer.Errorf("dial %s failed", "db")
+	}
+
+	if err = writer.Rotate(); nil != err {
+		t.Fatalf("rotate failed. err: %s", err.Error())
+	}
+	writer.Errorf("dial %s failed", "db")
+	writer.Info("after rotation")
+	writer.flush()
+
+	summary := fmt.Sprintf(DebounceFormat, 3, time.Hour, "dial %s failed")
+	content, _ := ioutil.ReadFile("/tmp/debounce.log.1")
+	if 2 != strings.Count(string(content), "\n") || !strings.Contains(string(content), " [ERROR] "+summary+"\n") {
+		t.Errorf("summary should be written to the rotated file. content: %s", string(content))
+	}
+
+	content, _ = ioutil.ReadFile("/tmp/debounce.log")
+	if 1 != strings.Count(string(content), "\n") || !strings.Contains(string(content), "after rotation") {
+		t.Errorf("pending count should not be carried over. content: %s", string(content))
+	}
+}
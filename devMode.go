@@ -0,0 +1,98 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// DevMessageWidth is the width messages are padded to in dev mode, so
+	// that fields following them are aligned
+	DevMessageWidth = 40
+)
+
+var (
+	// levelColors are colors of levels in dev mode
+	levelColors = map[LevelType]int{
+		TRACE:    GRAY,
+		DEBUG:    GREEN,
+		INFO:     BLUE,
+		WARNING:  YELLOW,
+		ERROR:    RED,
+		CRITICAL: RED,
+	}
+
+	// isTerminal determines whether w is a terminal
+	isTerminal = func(w io.Writer) bool {
+		f, ok := w.(*os.File)
+		if !ok {
+			return false
+		}
+
+		info, err := f.Stat()
+		return nil == err && 0 != info.Mode()&os.ModeCharDevice
+	}
+)
+
+// devFormat renders fields as a human readable line ending with EOL, with
+// time, colored level, message and then the other fields ordered by key
+func (blog *BLog) devFormat(level LevelType, fields map[string]interface{}) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(blog.timestamp())
+	fmt.Fprintf(buf, " \x1b[%dm%-8s\x1b[0m ", levelColors[level], level.String())
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if JSONMessageKey != key {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	message := ""
+	if v, ok := fields[JSONMessageKey]; ok {
+		message = fmt.Sprint(v)
+	}
+	buf.WriteString(message)
+
+	if 0 < len(keys) && len(message) < DevMessageWidth {
+		buf.WriteString(strings.Repeat(" ", DevMessageWidth-len(message)))
+	}
+	for _, key := range keys {
+		buf.WriteByte(' ')
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(fmt.Sprint(fields[key])))
+	}
+
+	buf.WriteByte(blog.eol)
+	return buf.Bytes()
+}
+
+// DevMode get whether json lines are rendered in human readable format
+func (blog *BLog) DevMode() bool {
+	return blog.dev
+}
+
+// SetDevMode toggle dev mode, default false. In dev mode json lines, such as
+// InfoJSON writes, are rendered as colored and aligned human readable lines
+// for local development, e.g. "[time] INFO     started    port=80".
+// It takes effect only when output is a terminal, so that files and pipes
+// keep receiving json lines without call sites changed
+func (blog *BLog) SetDevMode(dev bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.dev = dev && isTerminal(blog.in)
+	return blog
+}
+
+// SetDevMode toggle dev mode of the singleton writer
+func SetDevMode(dev bool) {
+	blog().SetDevMode(dev)
+}
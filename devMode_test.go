@@ -0,0 +1,40 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDevMode(t *testing.T) {
+	defer func(f func(w io.Writer) bool) { isTerminal = f }(isTerminal)
+
+	fields := map[string]interface{}{"msg": "started", "port": 80, "host": "local host"}
+
+	// non terminal keeps json
+	buf := new(bytes.Buffer)
+	writer := NewBLog(buf)
+	writer.SetDevMode(true)
+	writer.writeJSON(INFO, fields)
+	writer.flush()
+	if writer.DevMode() || !strings.HasPrefix(buf.String(), "{") || strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("dev mode should fall back to json without terminal. line: %q", buf.String())
+	}
+
+	// terminal renders colored human readable lines
+	isTerminal = func(w io.Writer) bool { return true }
+	buf.Reset()
+	writer = NewBLog(buf)
+	writer.SetDevMode(true)
+	writer.writeJSON(ERROR, fields)
+	writer.flush()
+
+	line := buf.String()
+	expected := " \x1b[31mERROR   \x1b[0m started" + strings.Repeat(" ", DevMessageWidth-len("started")) + " host=\"local host\" port=80\n"
+	if !writer.DevMode() || !strings.HasSuffix(line, expected) {
+		t.Errorf("dev mode line wrong. line: %q, expected suffix: %q", line, expected)
+	}
+}
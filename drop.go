@@ -0,0 +1,108 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"regexp"
+	"sync/atomic"
+)
+
+// dropRules suppresses messages containing any of substrings or matching any
+// of regexps, such as access logs of health checks. rules are added under
+// lock of the writer, and matched while formatting under the same lock
+type dropRules struct {
+	// messages dropped, first field to keep 64-bit alignment for atomic
+	dropped int64
+
+	substrings [][]byte
+	regexps    []*regexp.Regexp
+}
+
+// addSubstring adds a substring rule
+func (rules *dropRules) addSubstring(s string) {
+	rules.substrings = append(rules.substrings, []byte(s))
+}
+
+// addRegexp adds a regexp rule
+func (rules *dropRules) addRegexp(re *regexp.Regexp) {
+	rules.regexps = append(rules.regexps, re)
+}
+
+// drop determines whether message should be dropped and counts it.
+// a nil dropRules drops nothing
+func (rules *dropRules) drop(message []byte) bool {
+	if nil == rules {
+		return false
+	}
+
+	for _, s := range rules.substrings {
+		if bytes.Contains(message, s) {
+			atomic.AddInt64(&rules.dropped, 1)
+			CountDropped(DropFiltered)
+			return true
+		}
+	}
+	for _, re := range rules.regexps {
+		if re.Match(message) {
+			atomic.AddInt64(&rules.dropped, 1)
+			CountDropped(DropFiltered)
+			return true
+		}
+	}
+	return false
+}
+
+// count return number of messages dropped
+func (rules *dropRules) count() int64 {
+	if nil == rules {
+		return 0
+	}
+	return atomic.LoadInt64(&rules.dropped)
+}
+
+// AddDropSubstring drops messages containing s before they are written,
+// rules combine with OR
+func (blog *BLog) AddDropSubstring(s string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if nil == blog.drops {
+		blog.drops = new(dropRules)
+	}
+	blog.drops.addSubstring(s)
+	return blog
+}
+
+// AddDropRegexp drops messages matching re before they are written,
+// rules combine with OR
+func (blog *BLog) AddDropRegexp(re *regexp.Regexp) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if nil == blog.drops {
+		blog.drops = new(dropRules)
+	}
+	blog.drops.addRegexp(re)
+	return blog
+}
+
+// Dropped return number of messages dropped by drop rules
+func (blog *BLog) Dropped() int64 {
+	return blog.drops.count()
+}
+
+// AddDropSubstring drops messages containing s of the singleton writer
+func AddDropSubstring(s string) {
+	blog().AddDropSubstring(s)
+}
+
+// AddDropRegexp drops messages matching re of the singleton writer
+func AddDropRegexp(re *regexp.Regexp) {
+	blog().AddDropRegexp(re)
+}
+
+// Dropped return number of messages dropped by the singleton writer
+func Dropped() int64 {
+	return blog().Dropped()
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync/atomic"
+)
+
+// DropReason tells why a message is discarded
+type DropReason int
+
+const (
+	// DropQueueFull message discarded by an async writer whose queue is full
+	DropQueueFull DropReason = iota
+	// DropSampled message discarded by sampler policy
+	DropSampled
+	// DropDeduped message suppressed by debounce
+	DropDeduped
+	// DropHook message not delivered to a hook, such as hook panics
+	DropHook
+	// DropFiltered message discarded by drop rules
+	DropFiltered
+	// DropThrottled message discarded by throttle writer
+	DropThrottled
+
+	// dropReasons is number of drop reasons
+	dropReasons
+)
+
+// dropped counts messages discarded by every reason, accessed atomically
+var dropped [dropReasons]int64
+
+// DropStats is numbers of messages discarded by every reason of all writers
+type DropStats struct {
+	QueueFull int64
+	Sampled   int64
+	Deduped   int64
+	Hook      int64
+	Filtered  int64
+	Throttled int64
+}
+
+// CountDropped counts a message discarded for reason, writers outside this
+// package such as async sinks report their drops with it
+func CountDropped(reason DropReason) {
+	if 0 <= reason && reason < dropReasons {
+		atomic.AddInt64(&dropped[reason], 1)
+	}
+}
+
+// DroppedStats return numbers of messages discarded by every reason, so that
+// operators see in one place what is lost, e.g. alert on unexpected drops
+func DroppedStats() DropStats {
+	return DropStats{
+		QueueFull: atomic.LoadInt64(&dropped[DropQueueFull]),
+		Sampled:   atomic.LoadInt64(&dropped[DropSampled]),
+		Deduped:   atomic.LoadInt64(&dropped[DropDeduped]),
+		Hook:      atomic.LoadInt64(&dropped[DropHook]),
+		Filtered:  atomic.LoadInt64(&dropped[DropFiltered]),
+		Throttled: atomic.LoadInt64(&dropped[DropThrottled]),
+	}
+}
+
+// ResetDroppedStats sets numbers of messages discarded to zero
+func ResetDroppedStats() {
+	for i := range dropped {
+		atomic.StoreInt64(&dropped[i], 0)
+	}
+}
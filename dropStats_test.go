@@ -0,0 +1,54 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDroppedStats(t *testing.T) {
+	initPrefix(false)
+	ResetDroppedStats()
+	defer ResetDroppedStats()
+
+	SetInternalErrorWriter(nil)
+	defer SetInternalErrorWriter(os.Stderr)
+
+	// sampled
+	sampled := NewBLog(new(bytes.Buffer))
+	sampled.SetSamplerPolicy(1, 0, time.Second)
+	sampled.writef(INFO, "sampled %d", 1)
+	sampled.writef(INFO, "sampled %d", 2)
+
+	// deduped
+	deduped := NewBLog(new(bytes.Buffer))
+	deduped.SetDebounce("dial %s failed", time.Minute)
+	for i := 0; i < 3; i++ {
+		deduped.writef(ERROR, "dial %s failed", "db")
+	}
+
+	// filtered by drop rules
+	filtered := NewBLog(new(bytes.Buffer))
+	filtered.AddDropSubstring("healthz")
+	filtered.write(INFO, "GET /healthz")
+	filtered.write(INFO, "GET /index")
+
+	// hook
+	fireHook(new(panicHook), false, time.Now(), INFO, "lost")
+
+	// queue full reported by writers outside
+	CountDropped(DropQueueFull)
+
+	expected := DropStats{QueueFull: 1, Sampled: 1, Deduped: 2, Hook: 1, Filtered: 1}
+	if stats := DroppedStats(); expected != stats {
+		t.Errorf("dropped stats wrong. expected: %+v, got: %+v", expected, stats)
+	}
+
+	ResetDroppedStats()
+	if stats := DroppedStats(); (DropStats{}) != stats {
+		t.Errorf("dropped stats not reset. got: %+v", stats)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestBaseFileWriterDrop(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/drop.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	hook := NewMyHook()
+	writer.SetHook(hook)
+	writer.SetHookAsync(false)
+
+	writer.AddDropSubstring("/healthz")
+	// anchored to the beginning of message rather than time prefix
+	writer.AddDropRegexp(regexp.MustCompile(`^GET /metrics`))
+
+	writer.Info("GET /healthz 200")
+	writer.Infof("GET %s 200", "/metrics")
+	writer.Infof("GET %s 200", "/users")
+	writer.Info("POST /orders 201 from /metrics page")
+	writer.InfoJSON(map[string]interface{}{"path": "/healthz"})
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/drop.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 2 != len(lines) || !strings.HasSuffix(lines[0], "GET /users 200") || !strings.HasSuffix(lines[1], "POST /orders 201 from /metrics page") {
+		t.Errorf("only messages not matching drop rules should be written. content: %s", string(content))
+	}
+
+	if 3 != writer.Dropped() {
+		t.Errorf("dropped count wrong. dropped: %d", writer.Dropped())
+	}
+	if 2 != hook.Cnt() {
+		t.Errorf("hook should not be called for dropped messages. cnt: %d", hook.Cnt())
+	}
+
+	writer.Close()
+}
+
+func TestSinkWriterDrop(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	defer writer.Close()
+
+	writer.AddDropSubstring("/healthz")
+	writer.Info("GET /healthz 200")
+	writer.Errorf("GET %s 500", "/users")
+
+	if 1 != len(sink.messages) || "GET /users 500" != sink.messages[0] || 1 != writer.Dropped() {
+		t.Errorf("messages matching drop rules should not reach sink. messages: %v", sink.messages)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"strconv"
+	"time"
+)
+
+var (
+	// started is the baseline of elapsed time, captured at package init with
+	// monotonic clock reading
+	started = time.Now()
+)
+
+// writeElapsed writes milliseconds since started as "+123ms " in elapsed
+// mode, and return number of bytes written
+func (blog *BLog) writeElapsed(w lineWriter) int {
+	if !blog.elapsed {
+		return 0
+	}
+
+	buf := append(blog.elapsedBuf[:0], '+')
+	buf = strconv.AppendInt(buf, int64(time.Since(started)/time.Millisecond), 10)
+	buf = append(buf, "ms "...)
+
+	blog.elapsedBuf = buf
+	w.Write(buf)
+	return len(buf)
+}
+
+// PrintElapsed get whether elapsed time since start is written
+func (blog *BLog) PrintElapsed() bool {
+	return blog.elapsed
+}
+
+// SetPrintElapsed toggle writing milliseconds since the process started ahead
+// every message, such as "+123ms message", e.g. reading startup sequences.
+// It is measured with monotonic clock and written ahead sequence number
+func (blog *BLog) SetPrintElapsed(elapsed bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.elapsed = elapsed
+	return blog
+}
+
+// SetPrintElapsed toggle writing elapsed time since start ahead every message
+// of the singleton writer
+func SetPrintElapsed(elapsed bool) {
+	blog().SetPrintElapsed(elapsed)
+}
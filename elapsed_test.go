@@ -0,0 +1,48 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintElapsed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetPrintElapsed(true)
+	writer.SetPrintSequence(true)
+	defer writer.SetPrintSequence(false)
+
+	for i := 0; i < 5; i++ {
+		writer.Infof("step %d", i)
+		time.Sleep(2 * time.Millisecond)
+	}
+	writer.blog.flush()
+
+	last := int64(-1)
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		i := strings.Index(line, "] +")
+		j := strings.Index(line, "ms seq=")
+		if i < 0 || j < i {
+			t.Fatalf("line should have elapsed ahead sequence number. got: %s", line)
+		}
+
+		elapsed, err := strconv.ParseInt(line[i+3:j], 10, 64)
+		if nil != err || elapsed <= last {
+			t.Errorf("elapsed should increase monotonically. last: %d, got: %s", last, line)
+		}
+		last = elapsed
+	}
+
+	buf.Reset()
+	writer.SetPrintElapsed(false)
+	writer.Info("plain")
+	writer.blog.flush()
+	if strings.Contains(buf.String(), "ms ") {
+		t.Errorf("elapsed should not be written. got: %s", buf.String())
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// entryPool reuses entries and their buffers
+var entryPool = sync.Pool{
+	New: func() interface{} {
+		return &Entry{buf: new(bytes.Buffer)}
+	},
+}
+
+// Entry builds a structured message with typed fields in logfmt, such as
+// writer.Entry(INFO).Str("user", u).Int("code", c).Msg("login")
+// writes "login user=u code=c". Fields are encoded without reflection.
+// An Entry of a disabled level is nil, all its functions do nothing.
+// An Entry must not be used after Msg is called
+type Entry struct {
+	writer Writer
+	level  LevelType
+	sep    *fieldSeparator
+	buf    *bytes.Buffer
+}
+
+// newEntry gets an entry from pool, it returns nil if level is disabled
+func newEntry(writer Writer, level LevelType) *Entry {
+	if level < CompileLevel || !level.valid() || level < writer.Level() {
+		return nil
+	}
+
+	entry := entryPool.Get().(*Entry)
+	entry.writer = writer
+	entry.level = level
+	entry.sep = separatorsOf(writer)
+	entry.buf.Reset()
+	return entry
+}
+
+// key writes separator and key of a field
+func (entry *Entry) key(key string) {
+	entry.buf.WriteString(entry.sep.field)
+	entry.buf.WriteString(key)
+	entry.buf.WriteString(entry.sep.kv)
+}
+
+// Str adds a string field, value is quoted if needed
+func (entry *Entry) Str(key string, value string) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	entry.key(key)
+	entry.buf.WriteString(entry.sep.value(value))
+	return entry
+}
+
+// logfmtValue quotes value if needed in logfmt. values that are empty or
+// contain spaces, control characters, '=', '"' or invalid utf-8 are wrapped in
+// double quotes, with '"', backslash and control characters backslash escaped.
+// other values, such as numbers and simple tokens, are kept as they are
+func logfmtValue(value string) string {
+	return defaultSeparator.value(value)
+}
+
+// value quotes value if needed in logfmt, as logfmtValue does, values
+// containing custom separators are quoted as well
+func (sep *fieldSeparator) value(value string) string {
+	if "" != value && -1 == strings.IndexFunc(value, logfmtNeedsQuote) && !sep.custom(value) {
+		return value
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(value)+2))
+	buf.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(byte(r))
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case utf8.RuneError:
+			buf.WriteString(`\ufffd`)
+		default:
+			if r < ' ' {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[r>>4])
+				buf.WriteByte(hexDigits[r&0xf])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// hexDigits used in escaping control characters and hex dumps
+const hexDigits = "0123456789abcdef"
+
+// logfmtNeedsQuote return whether r makes a logfmt value quoted
+func logfmtNeedsQuote(r rune) bool {
+	return r <= ' ' || '=' == r || '"' == r || utf8.RuneError == r
+}
+
+// Int adds an int field
+func (entry *Entry) Int(key string, value int) *Entry {
+	return entry.Int64(key, int64(value))
+}
+
+// Int64 adds an int64 field
+func (entry *Entry) Int64(key string, value int64) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	entry.key(key)
+	entry.buf.WriteString(strconv.FormatInt(value, 10))
+	return entry
+}
+
+// Uint64 adds an uint64 field
+func (entry *Entry) Uint64(key string, value uint64) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	entry.key(key)
+	entry.buf.WriteString(strconv.FormatUint(value, 10))
+	return entry
+}
+
+// Float64 adds a float64 field
+func (entry *Entry) Float64(key string, value float64) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	entry.key(key)
+	entry.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	return entry
+}
+
+// Bool adds a bool field
+func (entry *Entry) Bool(key string, value bool) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	entry.key(key)
+	entry.buf.WriteString(strconv.FormatBool(value))
+	return entry
+}
+
+// Dur adds a duration field
+func (entry *Entry) Dur(key string, value time.Duration) *Entry {
+	return entry.Str(key, value.String())
+}
+
+// Err adds an "error" field, nil error is skipped
+func (entry *Entry) Err(err error) *Entry {
+	if nil == entry || nil == err {
+		return entry
+	}
+
+	return entry.Str("error", err.Error())
+}
+
+// Msg writes message followed by fields and puts entry back to pool
+func (entry *Entry) Msg(message string) {
+	if nil == entry {
+		return
+	}
+
+	logs(entry.writer, entry.level, message+entry.buf.String())
+
+	entry.writer = nil
+	entry.sep = nil
+	entryPool.Put(entry)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEntry(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+
+	writer.Entry(WARNING).
+		Str("user", "alice smith").
+		Int("code", 401).
+		Int64("id", -7).
+		Uint64("bytes", 1024).
+		Float64("ratio", 0.5).
+		Bool("ok", false).
+		Dur("took", 1500*time.Millisecond).
+		Err(errors.New("bad password")).
+		Err(nil).
+		Msg("login")
+
+	if 1 != len(sink.messages) {
+		t.Fatalf("entry message count wrong. count: %d", len(sink.messages))
+	}
+
+	expected := `login user="alice smith" code=401 id=-7 bytes=1024 ratio=0.5 ok=false took=1.5s error="bad password"`
+	if WARNING != sink.levels[0] || expected != sink.messages[0] {
+		t.Errorf("entry message wrong. level: %s, message: %s", sink.levels[0].String(), sink.messages[0])
+	}
+
+	// entries are reused from pool
+	writer.Entry(INFO).Str("user", "bob").Msg("logout")
+	if "logout user=bob" != sink.messages[1] {
+		t.Errorf("reused entry message wrong. message: %s", sink.messages[1])
+	}
+}
+
+func TestEntryDisabledLevel(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetLevel(ERROR)
+
+	entry := writer.Entry(INFO)
+	if nil != entry {
+		t.Error("entry of disabled level should be nil.")
+	}
+
+	entry.Str("user", "bob").Int("code", 200).Err(errors.New("ignored")).Msg("login")
+	if 0 != len(sink.messages) {
+		t.Errorf("entry of disabled level should not write. messages: %v", sink.messages)
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	cases := map[string]string{
+		"":               `""`,
+		"42":             `42`,
+		"-3.5e10":        `-3.5e10`,
+		"alice":          `alice`,
+		"/var/log":       `/var/log`,
+		`C:\tmp`:         `C:\tmp`,
+		"中文":             `中文`,
+		"cn north":       `"cn north"`,
+		"a=b":            `"a=b"`,
+		`say "hi"`:       `"say \"hi\""`,
+		`quote" \ slash`: `"quote\" \\ slash"`,
+		"line1\nline2":   `"line1\nline2"`,
+		"tab\there\r":    `"tab\there\r"`,
+		"bell\x07":       `"bell\u0007"`,
+		"bad\xffutf8":    `"bad\ufffdutf8"`,
+	}
+
+	for value, expected := range cases {
+		if got := logfmtValue(value); expected != got {
+			t.Errorf("logfmt value wrong. value: %q, expected: %s, got: %s", value, expected, got)
+		}
+	}
+}
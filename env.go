@@ -0,0 +1,161 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvOutput is the environment variable decides where messages go, one of
+	// console, stderr, file:<path>, fd:<fd> or socket:<network>:<address>,
+	// default console. fd:<fd> writes to an open fd of conventions such as
+	// LOGGING_FD
+	EnvOutput = "BLOG_OUTPUT"
+	// EnvLevel is the environment variable decides logging level, default info
+	EnvLevel = "BLOG_LEVEL"
+	// EnvFormat is the environment variable decides message format, text or
+	// json, default text. json writes lines of JSONFormatter
+	EnvFormat = "BLOG_FORMAT"
+	// EnvRotateSize is the environment variable decides size base logrotate
+	// threshold of file output, such as 100MB, 1GB. size base logrotate is
+	// disabled when not set
+	EnvRotateSize = "BLOG_ROTATE_SIZE"
+)
+
+// NewWriterFromEnv initialize a writer according to environment variables,
+// it complements NewWriterFromConfigAsFile for applications configured by
+// environment, singlton
+func NewWriterFromEnv() (err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+	if nil != blog() {
+		return ErrAlreadyInit
+	}
+
+	writer, err := newWriterFromEnv()
+	if nil != err {
+		return err
+	}
+
+	setBlog(writer)
+	return nil
+}
+
+// applyEnvLevel applies EnvLevel to the singleton writer if it is valid
+func applyEnvLevel() {
+	value := os.Getenv(EnvLevel)
+	if "" == value {
+		return
+	}
+
+	level := LevelFromString(value)
+	if !level.valid() {
+		internalError("invalid %s %q ignored", EnvLevel, value)
+		return
+	}
+
+	if singlton := blog(); nil != singlton {
+		singlton.SetLevel(level)
+	}
+}
+
+// newWriterFromEnv initialize a writer according to environment variables,
+// not singlton
+func newWriterFromEnv() (writer Writer, err error) {
+	level := INFO
+	if value := os.Getenv(EnvLevel); "" != value {
+		if level = LevelFromString(value); !level.valid() {
+			return nil, fmt.Errorf("blog4go: invalid %s %q, must be one of trace, debug, info, warn, error, critical", EnvLevel, value)
+		}
+	}
+
+	var formatter Formatter
+	switch value := os.Getenv(EnvFormat); strings.ToLower(value) {
+	case "", "text":
+	case "json":
+		formatter = JSONFormatter
+	default:
+		return nil, fmt.Errorf("blog4go: unsupported %s %q, must be text or json", EnvFormat, value)
+	}
+
+	var rotateSize int64
+	if value := os.Getenv(EnvRotateSize); "" != value {
+		if rotateSize, err = ParseSize(value); nil != err {
+			return nil, fmt.Errorf("blog4go: invalid %s %q, %s", EnvRotateSize, value, err.Error())
+		}
+	}
+
+	output := os.Getenv(EnvOutput)
+	switch {
+	case "" == output || "console" == output:
+		writer, err = newConsoleWriter()
+	case "stderr" == output:
+		writer = newStderrWriter()
+	case strings.HasPrefix(output, "file:"):
+		fileName := strings.TrimPrefix(output, "file:")
+		if "" == fileName {
+			return nil, fmt.Errorf("blog4go: invalid %s %q, missing file path", EnvOutput, output)
+		}
+
+		var fileWriter *baseFileWriter
+		if fileWriter, err = newBaseFileWriter(fileName, false); nil == err && 0 < rotateSize {
+			fileWriter.SetRotateSize(rotateSize)
+		}
+		writer = fileWriter
+	case strings.HasPrefix(output, "fd:"):
+		fd, e := strconv.ParseUint(strings.TrimPrefix(output, "fd:"), 10, 64)
+		if nil != e {
+			return nil, fmt.Errorf("blog4go: invalid %s %q, must be fd:<fd>", EnvOutput, output)
+		}
+		writer, err = newFDWriter(uintptr(fd))
+	case strings.HasPrefix(output, "socket:"):
+		parts := strings.SplitN(strings.TrimPrefix(output, "socket:"), ":", 2)
+		if 2 != len(parts) || "" == parts[0] || "" == parts[1] {
+			return nil, fmt.Errorf("blog4go: invalid %s %q, must be socket:<network>:<address>", EnvOutput, output)
+		}
+		writer, err = newSocketWriter(parts[0], parts[1])
+	default:
+		return nil, fmt.Errorf("blog4go: invalid %s %q, must be console, stderr, file:<path>, fd:<fd> or socket:<network>:<address>", EnvOutput, output)
+	}
+
+	if nil != err {
+		return nil, err
+	}
+	if _, ok := writer.(*SocketWriter); ok && nil != formatter {
+		writer.Close()
+		return nil, fmt.Errorf("blog4go: %s %q is not supported by socket output", EnvFormat, os.Getenv(EnvFormat))
+	}
+
+	writer.SetLevel(level)
+	if nil != formatter {
+		writer.SetFormatter(formatter)
+	}
+	return writer, nil
+}
+
+// ParseSize parses size strings such as 512KB, 100MB, 1GB or 4096 into bytes.
+// units are case insensitive, B/K/M/G are accepted as well
+func ParseSize(str string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(str))
+
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(s, "KB"), strings.HasSuffix(s, "K"):
+		unit = KB
+	case strings.HasSuffix(s, "MB"), strings.HasSuffix(s, "M"):
+		unit = MB
+	case strings.HasSuffix(s, "GB"), strings.HasSuffix(s, "G"):
+		unit = GB
+	}
+	s = strings.TrimSpace(strings.TrimRight(s, "KMGB"))
+
+	size, err := strconv.ParseInt(s, 10, 64)
+	if nil != err || 0 > size {
+		return 0, fmt.Errorf("bad size %q", str)
+	}
+	return size * unit, nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+//go:build !windows
+// +build !windows
+
+package blog4go
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// WatchEnvLevel reads EnvLevel again and applies it to the singleton writer
+// every time the process receives SIGUSR1, so that operators change
+// verbosity without restart, e.g. after os.Setenv by an admin endpoint.
+// Unset or invalid values are ignored, the latter with an internal warning.
+// The returned function stops watching
+func WatchEnvLevel() (unregister func()) {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				applyEnvLevel()
+			case <-done:
+				signal.Stop(sigs)
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
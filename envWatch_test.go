@@ -0,0 +1,58 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+//go:build !windows
+// +build !windows
+
+package blog4go
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchEnvLevel(t *testing.T) {
+	defer setEnv(nil)
+
+	warnings := make(lineChan, 1)
+	SetInternalErrorWriter(warnings)
+	defer SetInternalErrorWriter(os.Stderr)
+
+	writer := &levelWriter{SinkWriter: NewSinkWriter(newMySink()), levels: make(chan LevelType, 1)}
+	singltonLock.Lock()
+	saved := blog()
+	setBlog(writer)
+	singltonLock.Unlock()
+	defer func() {
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}()
+
+	defer WatchEnvLevel()()
+
+	// invalid value is ignored with a warning
+	setEnv(map[string]string{EnvLevel: "verbose"})
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	select {
+	case warning := <-warnings:
+		if !strings.Contains(warning, `invalid BLOG_LEVEL "verbose" ignored`) {
+			t.Errorf("warning of invalid level wrong. got: %s", warning)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("invalid level should be warned")
+	}
+
+	setEnv(map[string]string{EnvLevel: "error"})
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	select {
+	case level := <-writer.levels:
+		if ERROR != level {
+			t.Errorf("level of singleton should be updated. got: %s", level.String())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("level should be applied on SIGUSR1")
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+//go:build windows
+// +build windows
+
+package blog4go
+
+// WatchEnvLevel is not supported on windows, which has no SIGUSR1. It reports
+// an internal error and the returned function does nothing
+func WatchEnvLevel() (unregister func()) {
+	internalError("WatchEnvLevel is not supported on windows")
+	return func() {}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func setEnv(env map[string]string) {
+	for _, key := range []string{EnvOutput, EnvLevel, EnvFormat, EnvRotateSize} {
+		os.Unsetenv(key)
+	}
+
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+}
+
+func TestNewWriterFromEnv(t *testing.T) {
+	defer setEnv(nil)
+
+	// newConsoleWriter replaces the singleton, restore it after test
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	// defaults
+	setEnv(nil)
+	writer, err := newWriterFromEnv()
+	if nil != err {
+		t.Fatalf("new writer from env failed. err: %s", err.Error())
+	}
+	if _, ok := writer.(*ConsoleWriter); !ok || INFO != writer.Level() {
+		t.Errorf("default writer wrong. writer: %T, level: %s", writer, writer.Level().String())
+	}
+	writer.Close()
+
+	setEnv(map[string]string{
+		EnvOutput:     "file:/tmp/env.log",
+		EnvLevel:      "warn",
+		EnvFormat:     "text",
+		EnvRotateSize: "100MB",
+	})
+	writer, err = newWriterFromEnv()
+	if nil != err {
+		t.Fatalf("new writer from env failed. err: %s", err.Error())
+	}
+	if _, ok := writer.(*baseFileWriter); !ok || WARNING != writer.Level() || 100*MB != writer.RotateSize() {
+		t.Errorf("file writer wrong. writer: %T, level: %s, rotate size: %d", writer, writer.Level().String(), writer.RotateSize())
+	}
+	writer.Close()
+
+	// json lines
+	setEnv(map[string]string{
+		EnvOutput: "file:/tmp/env.log",
+		EnvFormat: "JSON",
+	})
+	writer, err = newWriterFromEnv()
+	if nil != err {
+		t.Fatalf("new writer from env failed. err: %s", err.Error())
+	}
+	writer.Info("json line")
+	writer.Close()
+
+	content, err := ioutil.ReadFile("/tmp/env.log")
+	if nil != err {
+		t.Fatalf("read log file failed. err: %s", err.Error())
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), string(EOL)), string(EOL))
+	var line map[string]interface{}
+	if err = json.Unmarshal([]byte(lines[len(lines)-1]), &line); nil != err || "json line" != line["msg"] {
+		t.Errorf("line should be valid json. line: %s", lines[len(lines)-1])
+	}
+	os.Remove("/tmp/env.log")
+
+	bad := []map[string]string{
+		{EnvLevel: "verbose"},
+		{EnvFormat: "xml"},
+		{EnvRotateSize: "100XB"},
+		{EnvOutput: "file:"},
+		{EnvOutput: "socket:udp"},
+		{EnvOutput: "syslog"},
+	}
+	for _, env := range bad {
+		setEnv(env)
+		if _, err = newWriterFromEnv(); nil == err {
+			t.Errorf("invalid env should return error. env: %v", env)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"4096":   4096,
+		"512KB":  512 * KB,
+		"100MB":  100 * MB,
+		"100mb":  100 * MB,
+		"1GB":    GB,
+		"2g":     2 * GB,
+		" 10 M ": 10 * MB,
+	}
+
+	for str, expected := range cases {
+		size, err := ParseSize(str)
+		if nil != err || expected != size {
+			t.Errorf("parse size wrong. str: %q, expected: %d, got: %d", str, expected, size)
+		}
+	}
+
+	for _, str := range []string{"", "MB", "-1MB", "1.5GB", "100XB"} {
+		if _, err := ParseSize(str); nil == err {
+			t.Errorf("parse size should fail. str: %q", str)
+		}
+	}
+}
+
+// levelWriter reports levels set
+type levelWriter struct {
+	*SinkWriter
+	levels chan LevelType
+}
+
+func (writer *levelWriter) SetLevel(level LevelType) Writer {
+	writer.levels <- level
+	return writer.SinkWriter.SetLevel(level)
+}
+
+// lineChan receives lines written
+type lineChan chan string
+
+func (c lineChan) Write(p []byte) (int, error) {
+	c <- string(p)
+	return len(p), nil
+}
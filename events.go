@@ -0,0 +1,62 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+)
+
+const (
+	// EventKey is the key of event type in events
+	EventKey = "event"
+)
+
+var (
+	// eventsWriter receives events written by InfoEvent, nil means the
+	// singleton writer
+	eventsWriter Writer
+
+	// eventsLock protects eventsWriter
+	eventsLock = new(sync.RWMutex)
+)
+
+// SetEventsWriter set writer events of InfoEvent are written to, such as a
+// json file or a sink of a structured store, so that business events are
+// kept apart from ordinary logs without another logger. nil writes events
+// to the singleton writer. The writer is owned by the caller
+func SetEventsWriter(w Writer) {
+	eventsLock.Lock()
+	defer eventsLock.Unlock()
+	eventsWriter = w
+}
+
+// event return fields of an event with its type, fields are copied
+func event(eventType string, fields map[string]interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		record[key] = value
+	}
+	record[EventKey] = eventType
+	return record
+}
+
+// InfoEvent writes a structured event of eventType with fields as a json
+// line with info level to the events writer, or the singleton writer if no
+// events writer is set
+func InfoEvent(eventType string, fields map[string]interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	eventsLock.RLock()
+	defer eventsLock.RUnlock()
+
+	if nil != eventsWriter {
+		eventsWriter.InfoJSON(event(eventType, fields))
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.InfoJSON(event(eventType, fields))
+}
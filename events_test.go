@@ -0,0 +1,45 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInfoEvent(t *testing.T) {
+	main := newMySink()
+	events := newMySink()
+
+	singltonLock.Lock()
+	saved := blog()
+	setBlog(NewSinkWriter(main))
+	singltonLock.Unlock()
+	defer func() {
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}()
+
+	SetEventsWriter(NewSinkWriter(events))
+	fields := map[string]interface{}{"order": 42}
+	InfoEvent("order_paid", fields)
+	Info("ordinary")
+
+	if 1 != len(events.messages) || !strings.Contains(events.messages[0], `"event":"order_paid"`) || !strings.Contains(events.messages[0], `"order":42`) {
+		t.Errorf("event should be written to events writer. events: %v", events.messages)
+	}
+	if 1 != len(main.messages) || "ordinary" != main.messages[0] {
+		t.Errorf("ordinary logs should be written to the main writer. messages: %v", main.messages)
+	}
+	if 1 != len(fields) {
+		t.Errorf("fields of caller should not be modified. fields: %v", fields)
+	}
+
+	// without events writer
+	SetEventsWriter(nil)
+	InfoEvent("order_shipped", nil)
+	if 2 != len(main.messages) || !strings.Contains(main.messages[1], `"event":"order_shipped"`) {
+		t.Errorf("event should be written to the singleton writer. messages: %v", main.messages)
+	}
+}
@@ -13,8 +13,8 @@ type MyHook struct {
 }
 
 // Fire .
-func (hook *MyHook) Fire(level log.LevelType, args ...interface{}) {
-	fmt.Println(args...)
+func (hook *MyHook) Fire(t time.Time, level log.LevelType, message string) {
+	fmt.Println(message)
 }
 
 // T .
@@ -0,0 +1,84 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/ioutil"
+)
+
+var (
+	// ErrNoReplay fallback writer is not set, or is not a file writer
+	ErrNoReplay = errors.New("Fallback writer can not be replayed")
+)
+
+// SetFallbackWriter makes lines failed to be sent, after retries if any, be
+// written verbatim to w instead of being dropped, e.g. a local file writer
+// during an outage of the collector. They can be sent again with Replay
+// after the collector recovers. w should be dedicated to the socket writer,
+// nil removes fallback
+func (writer *SocketWriter) SetFallbackWriter(w Writer) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.fallback = w
+}
+
+// FallbackWriter get writer lines failed to be sent are written to
+func (writer *SocketWriter) FallbackWriter() Writer {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	return writer.fallback
+}
+
+// Replay sends lines in fallback file writer to socket again in order, lines
+// sent are removed from the file. It stops at the first line failed and
+// returns its error, the line and lines after it are kept for next Replay.
+// socket dialed by address is reconnected first if the last line failed
+func (writer *SocketWriter) Replay() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	fallback, ok := writer.fallback.(*baseFileWriter)
+	if !ok {
+		return ErrNoReplay
+	}
+
+	// lines are written to fallback only under lock of socket writer
+	fallback.flush()
+	fallback.lock.RLock()
+	fileName, mode := fallback.currentFileName, fallback.fileMode
+	fallback.lock.RUnlock()
+
+	content, err := ioutil.ReadFile(fileName)
+	if nil != err || 0 == len(content) {
+		return err
+	}
+
+	if writer.failing && "" != writer.address {
+		writer.reconnect()
+	}
+
+	var sent int
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content))
+	for scanner.Scan() {
+		if err = writer.deliver(scanner.Bytes()); nil != err {
+			break
+		}
+		sent += len(scanner.Bytes()) + 1
+	}
+
+	// O_APPEND of fallback writes after lines kept
+	if e := ioutil.WriteFile(fileName, content[sent:], mode); nil != e && nil == err {
+		err = e
+	}
+	return err
+}
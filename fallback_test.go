@@ -0,0 +1,102 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// downConn is a connection which fails writes while it is down
+type downConn struct {
+	net.Conn
+	down    bool
+	packets []string
+	l       *sync.Mutex
+}
+
+func (c *downConn) Write(p []byte) (int, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	if c.down {
+		return 0, errors.New("collector down")
+	}
+	c.packets = append(c.packets, string(p))
+	return len(p), nil
+}
+
+func (c *downConn) setDown(down bool) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.down = down
+}
+
+func (c *downConn) RemoteAddr() net.Addr {
+	return nil
+}
+
+func (c *downConn) Close() error {
+	return nil
+}
+
+func TestSocketWriterFallback(t *testing.T) {
+	conn := &downConn{l: new(sync.Mutex)}
+	writer := newConnWriter(conn)
+	defer writer.Close()
+
+	if ErrNoReplay != writer.Replay() {
+		t.Error("replay without fallback file writer should fail")
+	}
+
+	fallback, err := newBaseFileWriter("/tmp/fallback.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		fallback.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+	writer.SetFallbackWriter(fallback)
+
+	writer.Info("sent")
+	conn.setDown(true)
+	writer.Info("spilled 1")
+	writer.Infof("spilled %d", 2)
+	fallback.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/fallback.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 2 != len(lines) || !strings.HasSuffix(lines[0], "[INFO] spilled 1") || !strings.HasSuffix(lines[1], "[INFO] spilled 2") {
+		t.Fatalf("lines failed to be sent should land in fallback. content: %q", string(content))
+	}
+
+	// still down, lines are kept
+	if nil == writer.Replay() {
+		t.Error("replay should fail while primary is down")
+	}
+	if kept, _ := ioutil.ReadFile("/tmp/fallback.log"); string(content) != string(kept) {
+		t.Errorf("lines not replayed should be kept. content: %q", string(kept))
+	}
+
+	// recovered
+	conn.setDown(false)
+	if err = writer.Replay(); nil != err {
+		t.Fatalf("replay should succeed after primary recovers. err: %s", err.Error())
+	}
+	if 3 != len(conn.packets) || lines[0] != conn.packets[1] || lines[1] != conn.packets[2] {
+		t.Errorf("fallback lines should be sent again in order. packets: %q", conn.packets)
+	}
+	if kept, _ := ioutil.ReadFile("/tmp/fallback.log"); 0 != len(kept) {
+		t.Errorf("lines replayed should be removed. content: %q", string(kept))
+	}
+}
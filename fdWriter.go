@@ -0,0 +1,90 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	// ErrInvalidFD file descriptor is not open
+	ErrInvalidFD = errors.New("Invalid file descriptor")
+
+	// keptFDs holds files of fds not closed by writers, os.File closes its fd
+	// when garbage collected, while the fd belongs to the caller
+	keptFDs     []*os.File
+	keptFDsLock = new(sync.Mutex)
+)
+
+// NewFDWriter initialize a writer on an already open file descriptor, such
+// as those passed by systemd socket activation or container sidecars, no
+// path is needed. Close flushes logs but leaves the fd open for the caller
+// unless SetCloseFD(true), singlton
+func NewFDWriter(fd uintptr) (err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+	if nil != blog() {
+		return ErrAlreadyInit
+	}
+
+	fdWriter, err := newFDWriter(fd)
+	if nil != err {
+		return err
+	}
+
+	setBlog(fdWriter)
+	return nil
+}
+
+// newFDWriter initialize a writer on an already open file descriptor,
+// not singlton
+func newFDWriter(fd uintptr) (fdWriter *ConsoleWriter, err error) {
+	file := os.NewFile(fd, fmt.Sprintf("fd:%d", fd))
+	if nil == file {
+		return nil, ErrInvalidFD
+	}
+	if _, err = file.Stat(); nil != err {
+		return nil, ErrInvalidFD
+	}
+
+	fdWriter = new(ConsoleWriter)
+	fdWriter.blog = NewBLog(file)
+	fdWriter.blog.name = file.Name()
+	fdWriter.file = file
+	fdWriter.closeFD = false
+
+	fdWriter.closed = false
+	fdWriter.colored = false
+
+	// log hook
+	fdWriter.hook = nil
+	fdWriter.hookLevel = DEBUG
+	fdWriter.hookAsync = true
+
+	go fdWriter.daemon()
+	return fdWriter, nil
+}
+
+// keepFD keeps file from being garbage collected, so that its fd is not
+// closed
+func keepFD(file *os.File) {
+	keptFDsLock.Lock()
+	defer keptFDsLock.Unlock()
+	keptFDs = append(keptFDs, file)
+}
+
+// SetCloseFD toggle closing fd when the writer is closed, only fd writers
+// are affected, default false
+func (writer *ConsoleWriter) SetCloseFD(closeFD bool) {
+	writer.closeFD = closeFD
+}
+
+// SetCloseFD toggle closing fd when the singleton fd writer is closed
+func SetCloseFD(closeFD bool) {
+	if writer, ok := blog().(*ConsoleWriter); ok {
+		writer.SetCloseFD(closeFD)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestFDWriter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if nil != err {
+		t.Fatalf("create pipe failed. err: %s", err.Error())
+	}
+	defer r.Close()
+
+	writer, err := newFDWriter(w.Fd())
+	if nil != err {
+		t.Fatalf("create fd writer failed. err: %s", err.Error())
+	}
+
+	writer.Info("through fd")
+	writer.Close()
+
+	// fd is left open for the caller
+	if _, err := w.WriteString("by caller\n"); nil != err {
+		t.Errorf("fd should not be closed by writer. err: %s", err.Error())
+	}
+	w.Close()
+
+	data, _ := ioutil.ReadAll(r)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if 2 != len(lines) || !strings.HasSuffix(lines[0], "[INFO] through fd") || "by caller" != lines[1] {
+		t.Errorf("lines read from pipe wrong. got: %q", string(data))
+	}
+}
+
+func TestFDWriterCloseFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if nil != err {
+		t.Fatalf("create pipe failed. err: %s", err.Error())
+	}
+	defer r.Close()
+
+	// writer owns a dup of the write end, w would close it again otherwise
+	fd, err := syscall.Dup(int(w.Fd()))
+	if nil != err {
+		t.Fatalf("dup fd failed. err: %s", err.Error())
+	}
+	w.Close()
+
+	writer, err := newFDWriter(uintptr(fd))
+	if nil != err {
+		t.Fatalf("create fd writer failed. err: %s", err.Error())
+	}
+	writer.SetCloseFD(true)
+
+	writer.Info("closing")
+	writer.Close()
+
+	// read ends only if the write end is closed
+	data, _ := ioutil.ReadAll(r)
+	if !strings.HasSuffix(string(data), "[INFO] closing\n") {
+		t.Errorf("line read from pipe wrong. got: %q", string(data))
+	}
+
+	if _, err := newFDWriter(^uintptr(0)); ErrInvalidFD != err {
+		t.Errorf("invalid fd not found. err: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"sort"
+)
+
+// defaultField is a static key value pair prepended to every message
+type defaultField struct {
+	key   string
+	value string
+}
+
+// defaultFields keeps default fields in order and renders them once into a
+// logfmt prefix, so that every message only costs a string write
+type defaultFields struct {
+	fields []defaultField
+	prefix string
+
+	// hostname is rendered ahead fields when printHost, host overrides
+	// the machine hostname
+	printHost bool
+	host      string
+
+	// program[pid] is rendered ahead hostname syslog style
+	printPID     bool
+	printProgram bool
+
+	// separators rendered with, nil means default ones
+	sep *fieldSeparator
+}
+
+// set replaces fields with given ones, ordered by key
+func (defaults *defaultFields) set(fields map[string]string) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	defaults.fields = defaults.fields[:0]
+	for _, key := range keys {
+		defaults.fields = append(defaults.fields, defaultField{key: key, value: fields[key]})
+	}
+	defaults.render()
+}
+
+// add appends a field, or replaces value of an existing field in place
+func (defaults *defaultFields) add(key, value string) {
+	for i := range defaults.fields {
+		if key == defaults.fields[i].key {
+			defaults.fields[i].value = value
+			defaults.render()
+			return
+		}
+	}
+
+	defaults.fields = append(defaults.fields, defaultField{key: key, value: value})
+	defaults.render()
+}
+
+// setPrintHost toggles rendering hostname ahead fields
+func (defaults *defaultFields) setPrintHost(print bool) {
+	defaults.printHost = print
+	defaults.render()
+}
+
+// setHost overrides hostname rendered, empty means the machine hostname
+func (defaults *defaultFields) setHost(name string) {
+	defaults.host = name
+	defaults.render()
+}
+
+// setPrintPID toggles rendering process id ahead hostname
+func (defaults *defaultFields) setPrintPID(print bool) {
+	defaults.printPID = print
+	defaults.render()
+}
+
+// setPrintProgram toggles rendering program name ahead hostname
+func (defaults *defaultFields) setPrintProgram(print bool) {
+	defaults.printProgram = print
+	defaults.render()
+}
+
+// render renders fields as "prog[pid] host=h k1=v1 k2=v2 ", empty if there
+// is no field
+func (defaults *defaultFields) render() {
+	buf := new(bytes.Buffer)
+	if defaults.printProgram {
+		buf.WriteString(program)
+	}
+	if defaults.printPID {
+		buf.WriteByte('[')
+		buf.WriteString(pid)
+		buf.WriteByte(']')
+	}
+	sep := defaults.separator()
+	if defaults.printProgram || defaults.printPID {
+		buf.WriteString(sep.field)
+	}
+	if defaults.printHost {
+		buf.WriteString("host")
+		buf.WriteString(sep.kv)
+		buf.WriteString(sep.value(hostnameOf(defaults.host)))
+		buf.WriteString(sep.field)
+	}
+	for _, field := range defaults.fields {
+		buf.WriteString(field.key)
+		buf.WriteString(sep.kv)
+		buf.WriteString(sep.value(field.value))
+		buf.WriteString(sep.field)
+	}
+	defaults.prefix = buf.String()
+}
+
+// SetDefaultFields sets static fields prepended to every message as logfmt
+// tokens ordered by key, such as "service=auth env=prod message".
+// nil or empty fields removes them
+func (blog *BLog) SetDefaultFields(fields map[string]string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.set(fields)
+	return blog
+}
+
+// AddDefaultField appends a static field prepended to every message,
+// fields added keep their order
+func (blog *BLog) AddDefaultField(key, value string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.add(key, value)
+	return blog
+}
+
+// SetDefaultFields sets static fields prepended to every message of the
+// singleton writer
+func SetDefaultFields(fields map[string]string) {
+	blog().SetDefaultFields(fields)
+}
+
+// AddDefaultField appends a static field prepended to every message of the
+// singleton writer
+func AddDefaultField(key, value string) {
+	blog().AddDefaultField(key, value)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestBaseFileWriterDefaultFields(t *testing.T) {
+	initPrefix(false)
+	writer, err := newBaseFileWriter("/tmp/fields.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.SetDefaultFields(map[string]string{"service": "auth", "env": "prod"})
+	writer.Info("started")
+	writer.Errorf("failed %d", 3)
+
+	writer.AddDefaultField("zone", "cn north")
+	writer.AddDefaultField("env", "test")
+	writer.Warn("replaced")
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/fields.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 3 != len(lines) {
+		t.Fatalf("lines count wrong. content: %s", string(content))
+	}
+
+	expected := []string{
+		" [INFO] env=prod service=auth started",
+		" [ERROR] env=prod service=auth failed 3",
+		" [WARN] env=test service=auth zone=\"cn north\" replaced",
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("default fields should be ahead every message. line: %s, expected: %s", line, expected[i])
+		}
+	}
+
+	// fields can be removed
+	writer.SetDefaultFields(nil)
+	writer.Info("plain")
+	writer.flush()
+
+	content, _ = ioutil.ReadFile("/tmp/fields.log")
+	if !strings.HasSuffix(string(content), "] plain\n") {
+		t.Errorf("default fields should be removed. content: %s", string(content))
+	}
+
+	writer.Close()
+}
@@ -14,13 +14,19 @@ import (
 func NewFileWriter(baseDir string, rotate bool) (err error) {
 	singltonLock.Lock()
 	defer singltonLock.Unlock()
-	if nil != blog {
+	if nil != blog() {
 		return ErrAlreadyInit
 	}
 
 	fileWriter := new(MultiWriter)
 	fileWriter.level = DEBUG
 	fileWriter.closed = false
+	fileWriter.flushLevel = noFlushLevel
+	fileWriter.placeholder = PLACEHOLDER
+	fileWriter.escape = ESCAPE
+	fileWriter.eol = EOL
+	fileWriter.format = newFormatBLog()
+	fileWriter.fileMode = DefaultFileMode
 
 	fileWriter.writers = make(map[LevelType]Writer)
 	for _, level := range Levels {
@@ -37,6 +43,6 @@ func NewFileWriter(baseDir string, rotate bool) (err error) {
 	fileWriter.hookLevel = DEBUG
 	fileWriter.hookAsync = true
 
-	blog = fileWriter
+	setBlog(fileWriter)
 	return
 }
@@ -34,11 +34,11 @@ func TestFileWriterBasicOperation(t *testing.T) {
 	// test file writer hook
 	hook := NewMyHook()
 
-	blog.SetHook(hook)
-	blog.SetHookLevel(INFO)
+	blog().SetHook(hook)
+	blog().SetHookLevel(INFO)
 
-	blog.Debug("something")
-	blog.Debugf("%s", "something")
+	blog().Debug("something")
+	blog().Debugf("%s", "something")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 0 != hook.Cnt() {
@@ -50,7 +50,7 @@ func TestFileWriterBasicOperation(t *testing.T) {
 	}
 
 	// async
-	blog.Info("yes")
+	blog().Info("yes")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 1 != hook.Cnt() {
@@ -62,8 +62,8 @@ func TestFileWriterBasicOperation(t *testing.T) {
 	}
 
 	// sync
-	blog.SetHookAsync(false)
-	blog.Warn("warn")
+	blog().SetHookAsync(false)
+	blog().Warn("warn")
 	// wait for hook called
 	if 2 != hook.Cnt() {
 		t.Error("hook not called")
@@ -74,52 +74,52 @@ func TestFileWriterBasicOperation(t *testing.T) {
 	}
 
 	// test basic operations
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-	blog.flush()
-
-	blog.Colored()
-	blog.SetColored(true)
-	blog.TimeRotated()
-	blog.SetTimeRotated(true)
-	blog.Level()
-	blog.SetLevel(CRITICAL)
-	blog.Retentions()
-	blog.SetRetentions(0)
-	blog.SetRetentions(7)
-	blog.RotateLines()
-	blog.SetRotateLines(0)
-	blog.SetRotateLines(100000)
-	blog.RotateSize()
-	blog.SetRotateSize(0)
-	blog.SetRotateSize(1024 * 1024 * 500)
-
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s\\", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-
-	blog.Close()
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+	blog().flush()
+
+	blog().Colored()
+	blog().SetColored(true)
+	blog().TimeRotated()
+	blog().SetTimeRotated(true)
+	blog().Level()
+	blog().SetLevel(CRITICAL)
+	blog().Retentions()
+	blog().SetRetentions(0)
+	blog().SetRetentions(7)
+	blog().RotateLines()
+	blog().SetRotateLines(0)
+	blog().SetRotateLines(100000)
+	blog().RotateSize()
+	blog().SetRotateSize(0)
+	blog().SetRotateSize(1024 * 1024 * 500)
+
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s\\", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+
+	blog().Close()
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
 }
 
 func TestSingleFileWriter(t *testing.T) {
@@ -142,7 +142,7 @@ func TestSingleFileWriter(t *testing.T) {
 
 	// should be closed
 	Close()
-	if nil != blog {
+	if nil != blog() {
 		t.Error("blog should be closed.")
 	}
 }
@@ -169,16 +169,47 @@ func TestFileWriterAsConfigFile(t *testing.T) {
 		t.Errorf("Duplicate initialization check failed. err: %s", err.Error())
 	}
 
-	blog.Debug("Debug")
-	blog.Trace("Trace")
-	blog.Info("Info")
-	blog.Warn("Warn")
-	blog.Error("Error")
-	blog.Critical("Critical")
+	blog().Debug("Debug")
+	blog().Trace("Trace")
+	blog().Info("Info")
+	blog().Warn("Warn")
+	blog().Error("Error")
+	blog().Critical("Critical")
 
-	blog.Close()
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
+	blog().Close()
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+}
+
+func TestFileWriterAsConfigFileReleased(t *testing.T) {
+	defer func() {
+		// clean logs
+		_, err := exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	timeCache.lock.RLock()
+	refs := timeCache.refs
+	timeCache.lock.RUnlock()
+
+	err := NewWriterFromConfigAsFile("examples/writer_from_configfile/config.example.xml")
+	if nil != err {
+		t.Fatal(err.Error())
+	}
+	Close()
+
+	// every BLog created is closed, the time cache goroutine stops with the
+	// last one
+	timeCache.lock.RLock()
+	defer timeCache.lock.RUnlock()
+	if refs != timeCache.refs {
+		t.Errorf("time cache should be released by the writer closed. before: %d, after: %d", refs, timeCache.refs)
+	}
+	if 0 == refs && nil != timeCache.stop {
+		t.Error("time cache goroutine should be stopped")
+	}
 }
 
 // test if log lose in multi goroutine mode
@@ -206,8 +237,8 @@ func TestFileWriterMultiGoroutine(t *testing.T) {
 		defer wg.Done()
 		beginWg.Wait()
 		for i := 0; i < 100; i++ {
-			blog.Infof("haha %s. en\\en, always %d and %f, %t, %+v", "eddie", 18, 3.1415, true, temp)
-			blog.Info("test for not formated")
+			blog().Infof("haha %s. en\\en, always %d and %f, %t, %+v", "eddie", 18, 3.1415, true, temp)
+			blog().Info("test for not formated")
 		}
 	}
 
@@ -284,9 +315,9 @@ func TestFileWriterSizeBaseLogrotate(t *testing.T) {
 	if nil != err {
 		t.Errorf("initialize file writer faied. err: %s", err.Error())
 	}
-	blog.SetRotateSize(60)
+	blog().SetRotateSize(60)
 
-	blog.Info("1")
+	blog().Info("1")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
@@ -294,11 +325,11 @@ func TestFileWriterSizeBaseLogrotate(t *testing.T) {
 		t.Error("size base logrotate failed, log should not exist.")
 	}
 
-	blog.Info("2")
+	blog().Info("2")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
-	blog.Info("3")
+	blog().Info("3")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
@@ -359,8 +390,8 @@ func TestFileWriterLinesBaseLogrotate(t *testing.T) {
 		t.Errorf("initialize file writer faied. err: %s", err.Error())
 	}
 
-	blog.SetRotateLines(2)
-	blog.Info("some")
+	blog().SetRotateLines(2)
+	blog().Info("some")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
@@ -368,11 +399,11 @@ func TestFileWriterLinesBaseLogrotate(t *testing.T) {
 		t.Error("line base logrotate failed, log should not exist.")
 	}
 
-	blog.Info("some")
+	blog().Info("some")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
-	blog.Info("some")
+	blog().Info("some")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
@@ -397,10 +428,10 @@ func TestFileWriterLogrorateRetentionCount(t *testing.T) {
 		t.Errorf("initialize file writer faied. err: %s", err.Error())
 	}
 
-	blog.SetRotateLines(2)
-	blog.SetRetentions(1)
+	blog().SetRotateLines(2)
+	blog().SetRetentions(1)
 
-	blog.Info("1")
+	blog().Info("1")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
@@ -408,19 +439,19 @@ func TestFileWriterLogrorateRetentionCount(t *testing.T) {
 		t.Error("logrotate retention failed, log should not exist.")
 	}
 
-	blog.Info("2")
+	blog().Info("2")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
-	blog.Info("3")
+	blog().Info("3")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
-	blog.Info("4")
+	blog().Info("4")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 
-	blog.Info("5")
+	blog().Info("5")
 	Flush()
 	time.Sleep(1 * time.Millisecond)
 	if _, err = os.Stat("/tmp/info.log.2"); nil == err {
@@ -468,7 +499,7 @@ func BenchmarkFileWriters(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		blog.Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+		blog().Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
 	}
 	Flush()
 }
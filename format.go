@@ -0,0 +1,159 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ErrorHandler is called when a writer meets an error, such as a mistake
+// found in format string in strict format mode, or input io failing to
+// accept a line
+type ErrorHandler func(err error)
+
+// FormatError describes a mistake found in format string
+type FormatError struct {
+	// Format is the format string
+	Format string
+	// Pos is the byte offset of the mistake in format string
+	Pos int
+	// Reason describes the mistake
+	Reason string
+}
+
+// Error implements error interface
+func (err *FormatError) Error() string {
+	return fmt.Sprintf("blog4go: %s at %d in format %q", err.Reason, err.Pos, err.Format)
+}
+
+// isNil determines whether arg is nil or a typed nil such as a nil pointer
+func isNil(arg interface{}) bool {
+	if nil == arg {
+		return true
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isFlag determines whether v may appear between placeholder and verb,
+// such as flags, width and precision
+func isFlag(v rune) bool {
+	switch v {
+	case '+', '-', '#', ' ', '.', '*', '[', ']':
+		return true
+	}
+	return '0' <= v && v <= '9'
+}
+
+// HEXVERB is the custom verb dumping an arg as space separated hex bytes,
+// e.g. %h of []byte{0xde, 0xad} writes "de ad". go vet does not know it and
+// reports f functions using it, Hex(b) with %v is the form vet accepts
+const HEXVERB = 'h'
+
+// writeHex writes arg as space separated hex bytes.
+// []byte is encoded directly, other args fall back to fmt's "% x"
+func writeHex(w lineWriter, arg interface{}) (n int) {
+	b, ok := arg.([]byte)
+	if !ok {
+		n, _ = fmt.Fprintf(w, "% x", arg)
+		return n
+	}
+
+	for i, c := range b {
+		if i > 0 {
+			w.WriteByte(' ')
+			n++
+		}
+		w.WriteByte(hexDigits[c>>4])
+		w.WriteByte(hexDigits[c&0xf])
+		n += 2
+	}
+	return n
+}
+
+// hexArg is bytes dumped as space separated hex bytes by any verb
+type hexArg []byte
+
+// Hex wraps b so that it is dumped as space separated hex bytes by any verb,
+// the same as %h, e.g. Debugf("recv %v", blog4go.Hex(payload)). Unlike %h it
+// passes go vet, and works with fmt functions as well
+func Hex(b []byte) fmt.Formatter {
+	return hexArg(b)
+}
+
+// Format implements fmt.Formatter
+func (arg hexArg) Format(f fmt.State, verb rune) {
+	buffer := new(bytes.Buffer)
+	writeHex(buffer, []byte(arg))
+	f.Write(buffer.Bytes())
+}
+
+// JSONVERB is the custom verb writing an arg as compact json inline,
+// e.g. %j of struct{ID int}{1} writes {"ID":1}. go vet does not know it and
+// reports f functions using it, JSON(arg) with %v is the form vet accepts
+const JSONVERB = 'j'
+
+// writeJSONArg writes arg marshaled as compact json, or %!j(err) if it
+// fails to be marshaled
+func writeJSONArg(w lineWriter, arg interface{}) (n int) {
+	b, err := json.Marshal(arg)
+	if nil != err {
+		n, _ = w.WriteString(badVerb(JSONVERB, err.Error()))
+		return n
+	}
+
+	n, _ = w.Write(b)
+	return n
+}
+
+// jsonArg is an arg formatted as compact json by any verb
+type jsonArg struct {
+	v interface{}
+}
+
+// JSON wraps v so that it is written as compact json by any verb, the same
+// as %j, e.g. Infof("payload=%v", blog4go.JSON(payload)). Unlike %j it
+// passes go vet, and works with fmt functions as well
+func JSON(v interface{}) fmt.Formatter {
+	return jsonArg{v: v}
+}
+
+// Format implements fmt.Formatter
+func (arg jsonArg) Format(f fmt.State, verb rune) {
+	b, err := json.Marshal(arg.v)
+	if nil != err {
+		f.Write([]byte(badVerb(verb, err.Error())))
+		return
+	}
+	f.Write(b)
+}
+
+// badVerb formats an error marker in fmt style, e.g. %!y(BADVERB)
+func badVerb(verb rune, reason string) string {
+	if 0 == verb {
+		return fmt.Sprintf("%%!(%s)", reason)
+	}
+	return fmt.Sprintf("%%!%c(%s)", verb, reason)
+}
+
+// extraArgs formats an error marker for extra arguments in fmt style,
+// e.g. %!(EXTRA int=1, string=a)
+func extraArgs(args []interface{}) string {
+	buffer := bytes.NewBufferString("%!(EXTRA ")
+	for i, arg := range args {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		fmt.Fprintf(buffer, "%T=%v", arg, arg)
+	}
+	buffer.WriteByte(')')
+	return buffer.String()
+}
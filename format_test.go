@@ -0,0 +1,329 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestStrictFormat(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	var errs []error
+	blog.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	// lenient by default
+	blog.writef(INFO, "%y is %s", "passed")
+	blog.flush()
+	if 0 != len(errs) {
+		t.Errorf("lenient mode should not report errors. errs: %v", errs)
+	}
+
+	blog.SetStrictFormat(true)
+	if !blog.StrictFormat() {
+		t.Error("strict format mode not set.")
+	}
+
+	cases := []struct {
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{"unknown %y verb", nil, "unknown %!y(BADVERB) verb"},
+		{"missing %s and %d", []interface{}{"a"}, "missing a and %!d(MISSING)"},
+		{"extra %d", []interface{}{1, "a"}, "extra 1%!(EXTRA string=a)"},
+		{"dangling %", nil, "dangling %!(NOVERB)"},
+		{"100%% sure %d", []interface{}{1}, "100% sure 1"},
+	}
+
+	for _, c := range cases {
+		buf.Reset()
+		errs = errs[:0]
+		blog.writef(INFO, c.format, c.args...)
+		blog.flush()
+
+		arrs := strings.Split(buf.String(), "[INFO] ")
+		if 2 != len(arrs) || c.expected+string(EOL) != arrs[1] {
+			t.Errorf("strict format wrong. format: %s, line: %s", c.format, buf.String())
+		}
+
+		if "100%% sure %d" == c.format {
+			if 0 != len(errs) {
+				t.Errorf("valid format should not report errors. errs: %v", errs)
+			}
+			continue
+		}
+
+		if 1 != len(errs) {
+			t.Errorf("error handler not called. format: %s, errs: %v", c.format, errs)
+			continue
+		}
+
+		if e, ok := errs[0].(*FormatError); !ok || c.format != e.Format {
+			t.Errorf("error reported wrong. err: %v", errs[0])
+		}
+	}
+}
+
+func TestMissingArgumentNotPanic(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.writef(INFO, "missing %s %d", "a")
+	blog.flush()
+
+	if !strings.HasSuffix(buf.String(), "missing a %!d(MISSING)\n") {
+		t.Errorf("missing argument format wrong. line: %s", buf.String())
+	}
+
+	// strict mode parses format without args
+	buf.Reset()
+	blog.SetStrictFormat(true)
+	blog.writef(INFO, "missing %d")
+	blog.flush()
+
+	if !strings.HasSuffix(buf.String(), "missing %!d(MISSING)\n") {
+		t.Errorf("missing argument format wrong. line: %s", buf.String())
+	}
+}
+
+type nilStringT struct{}
+
+func TestNilString(t *testing.T) {
+	initPrefix(false)
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	var p *nilStringT
+	var m map[string]int
+
+	// default keeps fmt behavior
+	blog.writef(INFO, "user %s", nil)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] user %!s(<nil>)\n") {
+		t.Errorf("nil should be formatted by fmt by default. content: %s", buf.String())
+	}
+
+	blog.SetNilString("<nil>")
+	buf.Reset()
+	blog.writef(INFO, "user %s pointer %v map %v id %d", nil, p, m, 1)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] user <nil> pointer <nil> map <nil> id 1\n") {
+		t.Errorf("nil args should be replaced. content: %s", buf.String())
+	}
+
+	blog.SetNilString("")
+	buf.Reset()
+	blog.writef(INFO, "pointer [%v]", p)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] pointer []\n") {
+		t.Errorf("nil args should be replaced with empty string. content: %s", buf.String())
+	}
+}
+
+func TestWritefManyArgs(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	blog.writef(INFO, "a=%d b=%s c=%v d=%x e=%q f=%.2f g=%t h=%c i=%05d j=%s", 1, "two", []int{3}, 255, "five", 6.0, true, 'h', 9, "ten")
+	blog.flush()
+
+	expected := " [INFO] a=1 b=two c=[3] d=ff e=\"five\" f=6.00 g=true h=h i=00009 j=ten\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("args should land in right positions. line: %q, expected: %q", buf.String(), expected)
+	}
+}
+
+// raceEnabled is true when testing with race detector, which drops pooled
+// objects of fmt randomly and makes allocations unstable
+var raceEnabled = false
+
+func TestWritefAllocsIndependentOfArgs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are unstable with race detector.")
+	}
+
+	blog := NewBLog(ioutil.Discard)
+
+	one := testing.AllocsPerRun(100, func() {
+		blog.writef(INFO, "%d", 123456)
+	})
+	ten := testing.AllocsPerRun(100, func() {
+		blog.writef(INFO, "%d %d %d %d %d %d %d %d %d %d", 123451, 123452, 123453, 123454, 123455, 123456, 123457, 123458, 123459, 123450)
+	})
+	if one != ten {
+		t.Errorf("allocations should not grow with args. one arg: %v, ten args: %v", one, ten)
+	}
+}
+
+func TestHexVerb(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetStrictFormat(true)
+
+	var errs []error
+	blog.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	payload := []byte{0x00, 0x01, 0x7f, 0x80, 0xde, 0xad, 0xbe, 0xef, 'G', 'E', 'T'}
+	blog.writef(INFO, "recv %h len %d empty [%h] str %h", payload, len(payload), []byte{}, "ok")
+	blog.flush()
+
+	if !strings.HasSuffix(buf.String(), " [INFO] recv 00 01 7f 80 de ad be ef 47 45 54 len 11 empty [] str 6f 6b\n") {
+		t.Errorf("hex verb format wrong. line: %s", buf.String())
+	}
+	if 0 != len(errs) {
+		t.Errorf("hex verb should be known in strict mode. errs: %v", errs)
+	}
+}
+
+func TestJSONVerb(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetStrictFormat(true)
+
+	var errs []error
+	blog.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	payload := struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}{ID: 7, Tags: []string{"a", "b"}}
+	blog.writef(INFO, "payload=%j n=%d bad=%j", payload, 3, make(chan int))
+	blog.flush()
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !strings.HasSuffix(line, ` [INFO] payload={"id":7,"tags":["a","b"]} n=3 bad=%!j(json: unsupported type: chan int)`) {
+		t.Errorf("json verb format wrong. line: %s", line)
+	}
+
+	inline := line[strings.Index(line, "payload=")+len("payload=") : strings.Index(line, " n=")]
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(inline), &decoded); nil != err {
+		t.Errorf("json verb should write valid json. json: %s, err: %s", inline, err.Error())
+	}
+	if 0 != len(errs) {
+		t.Errorf("json verb should be known in strict mode. errs: %v", errs)
+	}
+}
+
+func TestHexArg(t *testing.T) {
+	initPrefix(false)
+
+	payload := []byte{0xde, 0xad, 0x01}
+	expected := "recv de ad 01 len 3 empty []"
+
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.Debugf("recv %v len %d empty [%s]", Hex(payload), len(payload), Hex(nil))
+	writer.blog.flush()
+	if !strings.HasSuffix(strings.TrimSuffix(buf.String(), "\n"), " [DEBUG] "+expected) {
+		t.Errorf("hex arg format wrong. line: %s", buf.String())
+	}
+
+	sink := newMySink()
+	sinkWriter := NewSinkWriter(sink)
+	sinkWriter.SetLevel(DEBUG)
+	sinkWriter.Debugf("recv %v len %d empty [%s]", Hex(payload), len(payload), Hex(nil))
+	if 1 != len(sink.messages) || expected != sink.messages[0] {
+		t.Errorf("hex arg format wrong. messages: %q", sink.messages)
+	}
+
+	if "de ad 01" != fmt.Sprint(Hex(payload)) {
+		t.Errorf("hex arg should work with fmt. got: %s", fmt.Sprint(Hex(payload)))
+	}
+}
+
+func TestJSONArg(t *testing.T) {
+	initPrefix(false)
+
+	payload := map[string]int{"id": 7}
+	expected := `payload={"id":7} bad=%!s(json: unsupported type: chan int)`
+
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.Infof("payload=%v bad=%s", JSON(payload), JSON(make(chan int)))
+	writer.blog.flush()
+	if !strings.HasSuffix(strings.TrimSuffix(buf.String(), "\n"), " [INFO] "+expected) {
+		t.Errorf("json arg format wrong. line: %s", buf.String())
+	}
+
+	// writers not writing through BLog, and Tx
+	sink := newMySink()
+	sinkWriter := NewSinkWriter(sink)
+	sinkWriter.Infof("payload=%v bad=%s", JSON(payload), JSON(make(chan int)))
+	tx := NewTx(sinkWriter)
+	tx.Infof("payload=%v bad=%s", JSON(payload), JSON(make(chan int)))
+	tx.Commit()
+	if 2 != len(sink.messages) || expected != sink.messages[0] || expected != sink.messages[1] {
+		t.Errorf("json arg format wrong. messages: %q", sink.messages)
+	}
+
+	if `{"id":7}` != fmt.Sprint(JSON(payload)) {
+		t.Errorf("json arg should work with fmt. got: %s", fmt.Sprint(JSON(payload)))
+	}
+}
+
+func TestWritefNoArgs(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.writef(INFO, "100% done, %d left \\%s")
+	blog.flush()
+
+	if !strings.HasSuffix(buf.String(), " [INFO] 100% done, %d left \\%s\n") {
+		t.Errorf("format without args should be written verbatim. line: %s", buf.String())
+	}
+}
+
+func BenchmarkWritefNoArgs(b *testing.B) {
+	blog := NewBLog(ioutil.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blog.writef(INFO, "a format string with no args at all, written as is")
+	}
+}
+
+func BenchmarkWritefNoArgsParsed(b *testing.B) {
+	blog := NewBLog(ioutil.Discard)
+	blog.SetStrictFormat(true)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blog.writef(INFO, "a format string with no args at all, written as is")
+	}
+}
+
+func BenchmarkWritefTenArgs(b *testing.B) {
+	blog := NewBLog(ioutil.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blog.writef(INFO, "%d %d %d %d %d %d %d %d %d %d", 123451, 123452, 123453, 123454, 123455, 123456, 123457, 123458, 123459, 123450)
+	}
+}
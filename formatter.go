@@ -0,0 +1,131 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"time"
+)
+
+const (
+	// CSVTimeColumn is the column of logging time in csv lines, RFC3339
+	CSVTimeColumn = "time"
+	// CSVLevelColumn is the column of logging level in csv lines
+	CSVLevelColumn = "level"
+	// CSVMessageColumn is the column of message in csv lines
+	CSVMessageColumn = "msg"
+)
+
+var (
+	// ErrCSVColumn is returned when a csv column is unknown
+	ErrCSVColumn = errors.New("Unknown csv column")
+)
+
+// Formatter produces the whole line written for a message, including time,
+// level and the ending EOL. an ending EOL is replaced by eol of the writer
+type Formatter func(t time.Time, level LevelType, message string) []byte
+
+// TextFormatter formats lines the same as the built-in text format,
+// e.g. [2016/07/17:08:05:02] [INFO] message
+func TextFormatter(t time.Time, level LevelType, message string) []byte {
+	line := t.AppendFormat(nil, PrefixTimeFormat)
+	line = append(line, level.prefixBytes()...)
+	line = append(line, message...)
+	return append(line, EOL)
+}
+
+// JSONFormatter formats lines as json with time, level and msg keys
+func JSONFormatter(t time.Time, level LevelType, message string) []byte {
+	return newJSONEncoder().encodeMessage(t, level, message)
+}
+
+// CSVFormatter formats lines as csv records of time, level and message,
+// quoted as RFC 4180, e.g. 2016-07-17T08:05:02Z,INFO,"hello, ""world"""
+func CSVFormatter(t time.Time, level LevelType, message string) []byte {
+	return csvLine([]string{CSVTimeColumn, CSVLevelColumn, CSVMessageColumn}, t, level, message)
+}
+
+// NewCSVFormatter create a formatter writing csv records of columns in
+// order, columns are CSVTimeColumn, CSVLevelColumn and CSVMessageColumn
+func NewCSVFormatter(columns ...string) (Formatter, error) {
+	for _, column := range columns {
+		switch column {
+		case CSVTimeColumn, CSVLevelColumn, CSVMessageColumn:
+		default:
+			return nil, ErrCSVColumn
+		}
+	}
+
+	columns = append([]string(nil), columns...)
+	return func(t time.Time, level LevelType, message string) []byte {
+		return csvLine(columns, t, level, message)
+	}, nil
+}
+
+// csvLine return a csv record of columns ending with EOL
+func csvLine(columns []string, t time.Time, level LevelType, message string) []byte {
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case CSVTimeColumn:
+			record[i] = t.Format(time.RFC3339)
+		case CSVLevelColumn:
+			record[i] = level.String()
+		case CSVMessageColumn:
+			record[i] = message
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Write(record)
+	w.Flush()
+	return buf.Bytes()
+}
+
+// writeFormatted writes the line produced by formatter with a single Write,
+// it is called under lock of BLog
+func (blog *BLog) writeFormatted(level LevelType, message string) (size int) {
+	line := blog.endLine(blog.formatter(blog.now(), level, message))
+
+	w := blog.begin()
+	defer func() {
+		if blog.end(level) {
+			size = 0
+		}
+	}()
+
+	frame := blog.frame(level)
+	w.Write(frame)
+	w.Write(line)
+	return len(frame) + len(line)
+}
+
+// endLine replaces EOL ending a line of formatter or json encoder by eol of
+// BLog, it is called under lock of BLog
+func (blog *BLog) endLine(line []byte) []byte {
+	if EOL != blog.eol && 0 < len(line) && EOL == line[len(line)-1] {
+		line[len(line)-1] = blog.eol
+	}
+	return line
+}
+
+// SetFormatter set formatter producing the whole line written for every
+// message, so that any format can be written without changing BLog. args of
+// writef functions are formatted before calling formatter, fields of json
+// functions are passed as a json object. The formatter is fully responsible
+// for lines, so time format, colors, sequence numbers and default fields are
+// not written. nil restores the built-in format
+func (blog *BLog) SetFormatter(formatter Formatter) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.formatter = formatter
+	return blog
+}
+
+// SetFormatter set formatter producing whole lines of the singleton writer
+func SetFormatter(formatter Formatter) {
+	blog().SetFormatter(formatter)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pipeFormatter(t time.Time, level LevelType, message string) []byte {
+	return []byte(t.Format("2006-01-02T15:04:05") + "|" + level.String() + "|" + message + "\n")
+}
+
+func TestFormatter(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetFormatter(pipeFormatter)
+	blog.SetPrintSequence(true)
+
+	blog.writef(WARNING, "disk %s %d pct full", "sda", 90)
+	blog.write(INFO, "plain")
+	blog.writef(ERROR, "no args 100%")
+	blog.writeJSON(DEBUG, map[string]interface{}{"id": 1})
+	blog.flush()
+
+	expected := "2016-07-17T00:00:00|WARN|disk sda 90 pct full\n" +
+		"2016-07-17T00:00:00|INFO|plain\n" +
+		"2016-07-17T00:00:00|ERROR|no args 100%\n" +
+		"2016-07-17T00:00:00|DEBUG|{\"id\":1}\n"
+	if expected != buf.String() {
+		t.Errorf("lines should be produced by formatter. content: %s", buf.String())
+	}
+
+	// EOL ending lines of formatter is replaced by eol of BLog
+	blog.SetEOL(0)
+	buf.Reset()
+	blog.write(INFO, "plain")
+	blog.writeJSON(DEBUG, map[string]interface{}{"id": 1})
+	blog.flush()
+	expected = "2016-07-17T00:00:00|INFO|plain\x00" +
+		"2016-07-17T00:00:00|DEBUG|{\"id\":1}\x00"
+	if expected != buf.String() {
+		t.Errorf("lines should end with eol of writer. content: %q", buf.String())
+	}
+	blog.SetEOL(EOL)
+
+	// nil restores the built-in format
+	blog.SetPrintSequence(false)
+	blog.SetFormatter(nil)
+	buf.Reset()
+	blog.writef(INFO, "id %d", 1)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] id 1\n") || strings.Contains(buf.String(), "|") {
+		t.Errorf("built-in format should be restored. content: %s", buf.String())
+	}
+}
+
+func TestBuiltinFormatters(t *testing.T) {
+	initPrefix(false)
+	ts := time.Date(2016, 7, 17, 8, 5, 2, 0, time.UTC)
+
+	if line := string(TextFormatter(ts, INFO, "hello")); "[2016/07/17:08:05:02] [INFO] hello\n" != line {
+		t.Errorf("text formatter wrong. line: %s", line)
+	}
+
+	if line := string(JSONFormatter(ts, INFO, "hello")); `{"level":"INFO","msg":"hello","time":"2016-07-17T08:05:02Z"}`+"\n" != line {
+		t.Errorf("json formatter wrong. line: %s", line)
+	}
+}
+
+func TestCSVFormatter(t *testing.T) {
+	ts := time.Date(2016, 7, 17, 8, 5, 2, 0, time.UTC)
+
+	line := string(CSVFormatter(ts, WARNING, `disk "sda", 90 pct`))
+	if `2016-07-17T08:05:02Z,WARN,"disk ""sda"", 90 pct"`+"\n" != line {
+		t.Errorf("csv formatter wrong. line: %s", line)
+	}
+
+	formatter, err := NewCSVFormatter(CSVMessageColumn, CSVLevelColumn)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if line = string(formatter(ts, INFO, "a\nb")); "\"a\nb\",INFO\n" != line {
+		t.Errorf("csv columns wrong. line: %s", line)
+	}
+
+	if _, err = NewCSVFormatter(CSVTimeColumn, "host"); ErrCSVColumn != err {
+		t.Errorf("unknown column should be refused. err: %v", err)
+	}
+
+	// written by writer
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetFormatter(formatter)
+	blog.writef(ERROR, "user %s, id %d", "a,b", 1)
+	blog.flush()
+	if "\"user a,b, id 1\",ERROR\n" != buf.String() {
+		t.Errorf("csv line written wrong. content: %s", buf.String())
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+// Framing is how every line written is framed
+type Framing int
+
+const (
+	// FramingNone writes lines as they are, default
+	FramingNone Framing = iota
+	// FramingLevelByte writes a control byte encoding level ahead every
+	// line, FrameLevelBase + level, so that a supervisor reading the stream
+	// routes lines by the first byte instead of parsing level prefix
+	FramingLevelByte
+)
+
+const (
+	// FrameLevelBase is the control byte of TRACE lines in FramingLevelByte,
+	// bytes between it and FrameLevelBase + CRITICAL never appear in text
+	FrameLevelBase byte = 0x01
+)
+
+var (
+	// frameBytes is level control byte of every level, indexed by level
+	frameBytes [len(Levels)][]byte
+)
+
+func init() {
+	for _, level := range Levels {
+		frameBytes[level] = []byte{FrameLevelBase + byte(level)}
+	}
+}
+
+// frame return level control byte written ahead line with level, nil if
+// lines are not framed
+func (blog *BLog) frame(level LevelType) []byte {
+	if FramingLevelByte != blog.framing || !level.valid() {
+		return nil
+	}
+	return frameBytes[level]
+}
+
+// SetFraming set how every line written is framed, default FramingNone
+func (blog *BLog) SetFraming(framing Framing) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.framing = framing
+	return blog
+}
+
+// Framing get how every line written is framed
+func (blog *BLog) Framing() Framing {
+	return blog.framing
+}
+
+// SetFraming set framing of lines written by the singleton writer
+func SetFraming(framing Framing) {
+	blog().SetFraming(framing)
+}
+
+// DecodeFrame splits a line framed with FramingLevelByte into its level and
+// text. ok is false if line does not begin with a level control byte
+func DecodeFrame(line []byte) (level LevelType, text []byte, ok bool) {
+	if 0 == len(line) || line[0] < FrameLevelBase || line[0] > FrameLevelBase+byte(CRITICAL) {
+		return TRACE, line, false
+	}
+	return LevelType(line[0] - FrameLevelBase), line[1:], true
+}
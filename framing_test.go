@@ -0,0 +1,54 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFramingLevelByte(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetLevel(TRACE)
+	writer.SetFraming(FramingLevelByte)
+
+	writer.Trace("trace")
+	writer.Infof("info %d", 1)
+	writer.Error("error")
+	writer.Raw("raw")
+	writer.blog.flush()
+
+	expected := []LevelType{TRACE, INFO, ERROR, RawLevel}
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte{EOL}), []byte{EOL})
+	if len(expected) != len(lines) {
+		t.Fatalf("lines written wrong. got: %q", lines)
+	}
+	for i, line := range lines {
+		if FrameLevelBase+byte(expected[i]) != line[0] {
+			t.Errorf("line should begin with level byte of %s. got: %q", expected[i], line)
+		}
+
+		level, text, ok := DecodeFrame(line)
+		if !ok || expected[i] != level || bytes.HasPrefix(text, frameBytes[level]) {
+			t.Errorf("line decoded wrong. got: %s %q", level, text)
+		}
+	}
+	if !strings.HasSuffix(string(lines[1]), "[INFO] info 1") {
+		t.Errorf("text should follow level byte. got: %q", lines[1])
+	}
+
+	// lines not framed
+	if _, text, ok := DecodeFrame([]byte("[INFO] text")); ok || "[INFO] text" != string(text) {
+		t.Errorf("line without level byte should not be decoded. got: %q", text)
+	}
+
+	writer.SetFraming(FramingNone)
+	buf.Reset()
+	writer.Info("plain")
+	writer.blog.flush()
+	if '[' != buf.Bytes()[0] {
+		t.Errorf("line should not be framed. got: %q", buf.String())
+	}
+}
@@ -2,13 +2,50 @@
 
 package blog4go
 
+import (
+	"io"
+	"time"
+)
+
 // Hook Interface determine types of functions should be declared and
 // implemented when user offers user defined function call before every
 // logging action end.
 // users may use this hook as a callback function when something happen.
-// Fire function received two parameters.
+// Fire function received three parameters.
+// t is the time associate with that logging action.
 // level is the level associate with that logging action.
-// message is the formatted string already written.
+// message is the formatted message body, without time and level prefix,
+// json messages are the fields encoded as a json object.
 type Hook interface {
-	Fire(level LevelType, args ...interface{})
+	Fire(t time.Time, level LevelType, message string)
+}
+
+// fireHook calls hook with message written at time t, in a new goroutine if
+// async
+func fireHook(hook Hook, async bool, t time.Time, level LevelType, message string) {
+	if async {
+		go safeFire(hook, t, level, message)
+		return
+	}
+	safeFire(hook, t, level, message)
 }
+
+// safeFire calls hook, a panic of hook is recovered and reported as an
+// internal error rather than crashing the logging goroutine
+func safeFire(hook Hook, t time.Time, level LevelType, message string) {
+	defer func() {
+		if r := recover(); nil != r {
+			CountDropped(DropHook)
+			internalError("hook panic: %v", r)
+		}
+	}()
+
+	hook.Fire(t, level, message)
+}
+
+// RotateHook is a user defined logrotate policy of file writers.
+// It is called after every logging action with total size written since last
+// rotation. When it returns true with a non nil io.Writer, following messages
+// are written to that io.Writer instead. It is called with the writer lock
+// held, so it must not log with the same writer.
+type RotateHook func(currentSize int64) (io.Writer, bool)
@@ -3,7 +3,6 @@
 package blog4go
 
 import (
-	"fmt"
 	"os/exec"
 	"sync"
 	"testing"
@@ -12,6 +11,7 @@ import (
 
 type MyHook struct {
 	cnt     int
+	t       time.Time
 	level   LevelType
 	message string
 
@@ -64,10 +64,20 @@ func (hook *MyHook) SetMessage(message string) {
 	hook.message = message
 }
 
-func (hook *MyHook) Fire(level LevelType, args ...interface{}) {
+func (hook *MyHook) Time() time.Time {
+	hook.l.RLock()
+	defer hook.l.RUnlock()
+	return hook.t
+}
+
+func (hook *MyHook) Fire(t time.Time, level LevelType, message string) {
+	hook.l.Lock()
+	hook.t = t
+	hook.l.Unlock()
+
 	hook.Add()
 	hook.SetLevel(level)
-	hook.SetMessage(fmt.Sprint(args...))
+	hook.SetMessage(message)
 }
 
 func TestHook(t *testing.T) {
@@ -79,10 +89,10 @@ func TestHook(t *testing.T) {
 		t.Errorf("initialize file writer faied. err: %s", err.Error())
 	}
 
-	blog.SetHook(hook)
-	blog.SetHookLevel(INFO)
+	blog().SetHook(hook)
+	blog().SetHookLevel(INFO)
 
-	blog.Debug("something")
+	blog().Debug("something")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 0 != hook.Cnt() {
@@ -93,7 +103,7 @@ func TestHook(t *testing.T) {
 		t.Errorf("hook parameters wrong. level: %s, message: %s", hook.level.String(), hook.message)
 	}
 
-	blog.Info("yes")
+	blog().Info("yes")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 1 != hook.Cnt() {
@@ -110,3 +120,94 @@ func TestHook(t *testing.T) {
 		t.Errorf("clean files failed. err: %s", err.Error())
 	}
 }
+
+func TestHookCleanMessage(t *testing.T) {
+	initPrefix(false)
+	writer, err := newBaseFileWriter("/tmp/hook.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	hook := NewMyHook()
+	writer.SetHook(hook)
+	writer.SetHookLevel(INFO)
+	writer.SetHookAsync(false)
+	writer.SetPrintSequence(true)
+
+	before := time.Now().Add(-time.Second)
+	writer.Warnf("user %s failed %d times", "alice", 3)
+	if WARNING != hook.Level() || "user alice failed 3 times" != hook.Message() {
+		t.Errorf("hook should receive message body and level. level: %s, message: %s", hook.Level().String(), hook.Message())
+	}
+	if hook.Time().Before(before) {
+		t.Errorf("hook time wrong. time: %s", hook.Time())
+	}
+
+	writer.InfoJSON(map[string]interface{}{"user": "alice"})
+	if INFO != hook.Level() || `{"user":"alice"}` != hook.Message() {
+		t.Errorf("hook should receive json fields. level: %s, message: %s", hook.Level().String(), hook.Message())
+	}
+}
+
+func TestHookFormattedBody(t *testing.T) {
+	initPrefix(false)
+	writer, err := newBaseFileWriter("/tmp/hook.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	hook := NewMyHook()
+	writer.SetHook(hook)
+	writer.SetHookLevel(INFO)
+	writer.SetHookAsync(false)
+	writer.SetPlaceholder('@')
+	writer.SetNilString("-")
+
+	// format as a variable, placeholder and verbs are not known to vet
+	format := "100% id=@d user=@j raw=@h owner=@v"
+	expected := `100% id=1 user={"name":"alice"} raw=0a ff owner=-`
+	user := map[string]string{"name": "alice"}
+
+	writer.Warnf(format, 1, user, []byte{0x0a, 0xff}, nil)
+	if expected != hook.Message() {
+		t.Errorf("hook should receive message body written. got: %s", hook.Message())
+	}
+
+	hook.SetMessage("")
+	writer.LogfAt(time.Now(), ERROR, format, 1, user, []byte{0x0a, 0xff}, nil)
+	if expected != hook.Message() {
+		t.Errorf("hook of LogfAt should receive message body written. got: %s", hook.Message())
+	}
+
+	// writers wrapping others parse format the same
+	router := NewRouterWriter().Route(TRACE, CRITICAL, NewSinkWriter(newMySink()))
+	router.SetHook(hook)
+	router.SetHookLevel(INFO)
+	router.SetHookAsync(false)
+	router.SetPlaceholder('@')
+	router.SetNilString("-")
+
+	hook.SetMessage("")
+	router.Infof(format, 1, user, []byte{0x0a, 0xff}, nil)
+	if expected != hook.Message() {
+		t.Errorf("hook of router should receive message body written. got: %s", hook.Message())
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"os"
+)
+
+// hostname is the machine hostname cached at startup
+var hostname = func() string {
+	name, err := os.Hostname()
+	if nil != err || "" == name {
+		return "unknown"
+	}
+	return name
+}()
+
+// hostnameOf return name overridden, or the cached machine hostname
+func hostnameOf(name string) string {
+	if "" == name {
+		return hostname
+	}
+	return name
+}
+
+// PrintHostname get whether hostname is written ahead every message
+func (blog *BLog) PrintHostname() bool {
+	return blog.defaults.printHost
+}
+
+// SetPrintHostname toggle writing hostname ahead every message as
+// "host=web01 message", it comes before default fields
+func (blog *BLog) SetPrintHostname(print bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.setPrintHost(print)
+	return blog
+}
+
+// Hostname get hostname written ahead messages
+func (blog *BLog) Hostname() string {
+	return hostnameOf(blog.defaults.host)
+}
+
+// SetHostname overrides hostname written ahead messages, such as a name
+// meaningful inside containers. empty name restores the machine hostname
+func (blog *BLog) SetHostname(name string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.setHost(name)
+	return blog
+}
+
+// SetPrintHostname toggle writing hostname ahead every message of the
+// singleton writer
+func SetPrintHostname(print bool) {
+	blog().SetPrintHostname(print)
+}
+
+// SetHostname overrides hostname written by the singleton writer
+func SetHostname(name string) {
+	blog().SetHostname(name)
+}
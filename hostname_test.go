@@ -0,0 +1,71 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestBaseFileWriterPrintHostname(t *testing.T) {
+	initPrefix(false)
+	writer, err := newBaseFileWriter("/tmp/hostname.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	name, _ := os.Hostname()
+	if "" != name && name != writer.blog.Hostname() {
+		t.Errorf("hostname should be cached from os. expected: %s, got: %s", name, writer.blog.Hostname())
+	}
+
+	writer.SetPrintHostname(true)
+	writer.AddDefaultField("env", "prod")
+	writer.Info("started")
+
+	writer.SetHostname("api-1")
+	writer.Infof("served %d", 3)
+
+	writer.SetPrintHostname(false)
+	writer.Info("plain")
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/hostname.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 3 != len(lines) {
+		t.Fatalf("lines count wrong. content: %s", string(content))
+	}
+
+	expected := []string{
+		" [INFO] host=" + logfmtValue(hostname) + " env=prod started",
+		" [INFO] host=api-1 env=prod served 3",
+		" [INFO] env=prod plain",
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("hostname should be ahead default fields. line: %s, expected: %s", line, expected[i])
+		}
+	}
+}
+
+func TestSinkWriterPrintHostname(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetPrintHostname(true)
+	writer.SetHostname("worker-7")
+	writer.Info("done")
+
+	if 1 != len(sink.messages) || "host=worker-7 done" != sink.messages[0] {
+		t.Errorf("hostname should be ahead message. messages: %v", sink.messages)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package httpwriter provides a blog4go writer shipping formatted lines to an
+// HTTP(S) endpoint in batches, for environments where only HTTP is allowed
+// out, such as serverless functions.
+package httpwriter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+const (
+	// DefaultMaxPayload is the default maximum size of a payload before
+	// compression
+	DefaultMaxPayload = 1024 * 1024
+
+	// DefaultMaxDelay is the default maximum time a line waits in a batch
+	DefaultMaxDelay = time.Second
+
+	// DefaultQueueSize is the default number of lines waiting to be batched,
+	// logging blocks when it is reached
+	DefaultQueueSize = 4096
+
+	// DefaultRetries is the default number of retries on 5xx responses
+	DefaultRetries = 3
+
+	// DefaultBackoff is the default delay before the first retry, it doubles
+	// on every retry
+	DefaultBackoff = 100 * time.Millisecond
+)
+
+// Framing is how lines of a batch are framed in a payload
+type Framing int
+
+const (
+	// NDJSON frames lines as text separated by EOL
+	NDJSON Framing = iota
+	// JSONArray frames lines as a json array of strings
+	JSONArray
+)
+
+// Option configures the writer created by NewHTTPWriter
+type Option func(s *sink)
+
+// WithHeader sets a header sent with every payload, such as Authorization
+func WithHeader(key, value string) Option {
+	return func(s *sink) {
+		s.header.Set(key, value)
+	}
+}
+
+// WithFraming sets how lines are framed in a payload
+func WithFraming(framing Framing) Option {
+	return func(s *sink) {
+		s.framing = framing
+	}
+}
+
+// WithGzip compresses payloads with gzip
+func WithGzip(gzip bool) Option {
+	return func(s *sink) {
+		s.gzip = gzip
+	}
+}
+
+// WithMaxPayload sets maximum size of a payload before compression, a line
+// longer than it is sent alone
+func WithMaxPayload(size int) Option {
+	return func(s *sink) {
+		if 0 < size {
+			s.maxPayload = size
+		}
+	}
+}
+
+// WithMaxDelay sets maximum time a line waits in a batch
+func WithMaxDelay(delay time.Duration) Option {
+	return func(s *sink) {
+		if 0 < delay {
+			s.maxDelay = delay
+		}
+	}
+}
+
+// WithQueueSize sets number of lines waiting to be batched
+func WithQueueSize(size int) Option {
+	return func(s *sink) {
+		if 0 < size {
+			s.queueSize = size
+		}
+	}
+}
+
+// WithRetry sets number of retries on 5xx responses or transport errors and
+// the delay before the first retry
+func WithRetry(retries int, backoff time.Duration) Option {
+	return func(s *sink) {
+		s.retries = retries
+		s.backoff = backoff
+	}
+}
+
+// WithClient sets client sending payloads, http.DefaultClient is used by
+// default
+func WithClient(client *http.Client) Option {
+	return func(s *sink) {
+		s.client = client
+	}
+}
+
+// WithErrorHandler sets handler called when a payload fails to be sent
+func WithErrorHandler(handler blog4go.ErrorHandler) Option {
+	return func(s *sink) {
+		s.handler = handler
+	}
+}
+
+// item is a line to be batched, or a flush request when ack is not nil
+type item struct {
+	line []byte
+	ack  chan error
+}
+
+// sink queues formatted lines and posts them in batches in background
+type sink struct {
+	endpoint string
+	header   http.Header
+	client   *http.Client
+	handler  blog4go.ErrorHandler
+
+	framing    Framing
+	gzip       bool
+	maxPayload int
+	maxDelay   time.Duration
+	queueSize  int
+	retries    int
+	backoff    time.Duration
+
+	queue chan item
+	// exits when queue is closed and drained
+	done chan struct{}
+
+	// lines framed in current batch and size of payload
+	batch [][]byte
+	size  int
+}
+
+// Emit formats message as a line and queues it, it blocks when queue is full
+func (s *sink) Emit(t time.Time, level blog4go.LevelType, message string) error {
+	line := make([]byte, 0, len(blog4go.PrefixTimeFormat)+len(message)+16)
+	line = t.AppendFormat(line, blog4go.PrefixTimeFormat)
+	line = append(line, " ["...)
+	line = append(line, level.String()...)
+	line = append(line, "] "...)
+	line = append(line, message...)
+
+	s.queue <- item{line: line}
+	return nil
+}
+
+// Flush posts lines queued before it
+func (s *sink) Flush() error {
+	ack := make(chan error, 1)
+	s.queue <- item{ack: ack}
+	return <-ack
+}
+
+// Close posts the final batch
+func (s *sink) Close() error {
+	close(s.queue)
+	<-s.done
+	return nil
+}
+
+// loop batches queued lines, a batch is posted when it is about to exceed
+// maximum payload size, when its first line waits for maximum delay, or
+// when it is flushed
+func (s *sink) loop() {
+	defer close(s.done)
+
+	var timer <-chan time.Time
+	for {
+		select {
+		case it, ok := <-s.queue:
+			if !ok {
+				s.post()
+				return
+			}
+
+			if nil != it.ack {
+				it.ack <- s.post()
+				timer = nil
+				continue
+			}
+
+			line := s.frame(it.line)
+			if s.full(line) {
+				s.post()
+				timer = nil
+			}
+			s.batch = append(s.batch, line)
+			s.size += len(line) + 1
+
+			if nil == timer {
+				timer = time.After(s.maxDelay)
+			}
+
+		case <-timer:
+			s.post()
+			timer = nil
+		}
+	}
+}
+
+// frame frames line as an element of payload
+func (s *sink) frame(line []byte) []byte {
+	if JSONArray == s.framing {
+		line, _ = json.Marshal(string(line))
+	}
+	return line
+}
+
+// full determines whether current batch would exceed maximum payload size
+// with line, every line takes an EOL or comma, and json array takes one
+// more byte for brackets
+func (s *sink) full(line []byte) bool {
+	size := s.size + len(line) + 1
+	if JSONArray == s.framing {
+		size++
+	}
+	return 0 < len(s.batch) && size > s.maxPayload
+}
+
+// payload frames current batch
+func (s *sink) payload() []byte {
+	if JSONArray == s.framing {
+		buf := bytes.NewBuffer(make([]byte, 0, s.size))
+		buf.WriteByte('[')
+		buf.Write(bytes.Join(s.batch, []byte{','}))
+		buf.WriteByte(']')
+		return buf.Bytes()
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, s.size))
+	for _, line := range s.batch {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// post sends current batch with retries, the batch is dropped even if it
+// fails so that a broken endpoint would not hold lines in memory
+func (s *sink) post() (err error) {
+	if 0 == len(s.batch) {
+		return nil
+	}
+
+	body := s.payload()
+	s.batch = s.batch[:0]
+	s.size = 0
+
+	if s.gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+		body = buf.Bytes()
+	}
+
+	backoff := s.backoff
+	for i := 0; ; i++ {
+		var retry bool
+		if retry, err = s.send(body); !retry || i >= s.retries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	if nil != err && nil != s.handler {
+		s.handler(err)
+	}
+	return
+}
+
+// send posts body once, and return whether it should be retried
+func (s *sink) send(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewReader(body))
+	if nil != err {
+		return false, err
+	}
+
+	for key, values := range s.header {
+		req.Header[key] = values
+	}
+	if s.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.client.Do(req)
+	if nil != err {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if 500 <= resp.StatusCode {
+		return true, fmt.Errorf("httpwriter: post failed. status: %s", resp.Status)
+	}
+	if 300 <= resp.StatusCode {
+		return false, fmt.Errorf("httpwriter: post failed. status: %s", resp.Status)
+	}
+	return false, nil
+}
+
+// NewHTTPWriter creates a writer posting formatted lines to endpoint in
+// batches, lines are framed as NDJSON by default
+func NewHTTPWriter(endpoint string, opts ...Option) blog4go.Writer {
+	s := &sink{
+		endpoint:   endpoint,
+		header:     make(http.Header),
+		client:     http.DefaultClient,
+		framing:    NDJSON,
+		maxPayload: DefaultMaxPayload,
+		maxDelay:   DefaultMaxDelay,
+		queueSize:  DefaultQueueSize,
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if "" == s.header.Get("Content-Type") {
+		if JSONArray == s.framing {
+			s.header.Set("Content-Type", "application/json")
+		} else {
+			s.header.Set("Content-Type", "application/x-ndjson")
+		}
+	}
+
+	s.queue = make(chan item, s.queueSize)
+	go s.loop()
+
+	return blog4go.NewSinkWriter(s)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package httpwriter
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector keeps every payload posted, failing the first failures requests
+type collector struct {
+	payloads []string
+	headers  []http.Header
+	requests int
+	failures int
+
+	l *sync.Mutex
+}
+
+func newCollector() (*collector, *httptest.Server) {
+	c := &collector{l: new(sync.Mutex)}
+	return c, httptest.NewServer(c)
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.requests++
+	if c.requests <= c.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body := r.Body
+	if "gzip" == r.Header.Get("Content-Encoding") {
+		body, _ = gzip.NewReader(r.Body)
+	}
+	payload, _ := ioutil.ReadAll(body)
+
+	c.payloads = append(c.payloads, string(payload))
+	c.headers = append(c.headers, r.Header)
+}
+
+func TestHTTPWriterNDJSON(t *testing.T) {
+	c, server := newCollector()
+	defer server.Close()
+
+	writer := NewHTTPWriter(server.URL, WithHeader("Authorization", "Bearer token"), WithGzip(true))
+	writer.Info("hello")
+	writer.Errorf("boom %d", 1)
+
+	if err := writer.Drain(); nil != err {
+		t.Fatalf("drain failed. err: %s", err.Error())
+	}
+
+	if 1 != len(c.payloads) {
+		t.Fatalf("lines should be posted in a batch. payloads: %d", len(c.payloads))
+	}
+	lines := strings.Split(c.payloads[0], "\n")
+	if 3 != len(lines) || !strings.HasSuffix(lines[0], " [INFO] hello") || !strings.HasSuffix(lines[1], " [ERROR] boom 1") || "" != lines[2] {
+		t.Errorf("payload should be lines separated by EOL. payload: %q", c.payloads[0])
+	}
+	if "Bearer token" != c.headers[0].Get("Authorization") || "application/x-ndjson" != c.headers[0].Get("Content-Type") {
+		t.Errorf("headers wrong. headers: %v", c.headers[0])
+	}
+
+	writer.Close()
+}
+
+func TestHTTPWriterJSONArray(t *testing.T) {
+	c, server := newCollector()
+	defer server.Close()
+
+	// every line is about 40 bytes, so that a payload keeps 2 lines
+	writer := NewHTTPWriter(server.URL, WithFraming(JSONArray), WithMaxPayload(100))
+	for i := 0; i < 5; i++ {
+		writer.Infof("say \"%d\"", i)
+	}
+	writer.Close()
+
+	if 3 != len(c.payloads) {
+		t.Fatalf("payloads should respect maximum size. payloads: %v", c.payloads)
+	}
+
+	var count int
+	for _, payload := range c.payloads {
+		var lines []string
+		if err := json.Unmarshal([]byte(payload), &lines); nil != err {
+			t.Fatalf("payload should be a json array. payload: %s", payload)
+		}
+		if 100 < len(payload) {
+			t.Errorf("payload exceeds maximum size. payload: %s", payload)
+		}
+		for _, line := range lines {
+			if !strings.Contains(line, "say \"") {
+				t.Errorf("line wrong. line: %s", line)
+			}
+			count++
+		}
+	}
+	if 5 != count {
+		t.Errorf("every line should be posted once closed. count: %d", count)
+	}
+}
+
+func TestHTTPWriterRetry(t *testing.T) {
+	c, server := newCollector()
+	defer server.Close()
+	c.failures = 2
+
+	writer := NewHTTPWriter(server.URL, WithRetry(2, time.Millisecond))
+	writer.Info("retried")
+	if err := writer.Drain(); nil != err {
+		t.Fatalf("payload should succeed after retries. err: %s", err.Error())
+	}
+	if 3 != c.requests || 1 != len(c.payloads) {
+		t.Errorf("payload should be retried on 5xx. requests: %d", c.requests)
+	}
+
+	// give up when retries run out
+	c.failures = 10
+	var failures []error
+	writer = NewHTTPWriter(server.URL, WithRetry(1, time.Millisecond), WithErrorHandler(func(err error) {
+		failures = append(failures, err)
+	}))
+	writer.Info("lost")
+	if nil == writer.Drain() || 1 != len(failures) {
+		t.Errorf("error should be reported when retries run out. failures: %v", failures)
+	}
+	writer.Close()
+}
+
+func TestHTTPWriterMaxDelay(t *testing.T) {
+	c, server := newCollector()
+	defer server.Close()
+
+	writer := NewHTTPWriter(server.URL, WithMaxDelay(10*time.Millisecond))
+	defer writer.Close()
+
+	writer.Info("delayed")
+	time.Sleep(200 * time.Millisecond)
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if 1 != len(c.payloads) {
+		t.Errorf("batch should be posted after maximum delay. payloads: %d", len(c.payloads))
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+var (
+	// internalErrorWriter receives diagnostics of blog4go itself
+	internalErrorWriter io.Writer = os.Stderr
+
+	// internalErrorLock protects internalErrorWriter
+	internalErrorLock = new(sync.Mutex)
+)
+
+// SetInternalErrorWriter set where blog4go writes its own diagnostics, such
+// as failed logrotate, socket reconnect and hook panics, default os.Stderr.
+// They are kept apart from user logs, so that a broken writer never logs
+// into itself. nil discards them
+func SetInternalErrorWriter(w io.Writer) {
+	internalErrorLock.Lock()
+	defer internalErrorLock.Unlock()
+
+	if nil == w {
+		w = ioutil.Discard
+	}
+	internalErrorWriter = w
+}
+
+// internalError writes a diagnostic line of blog4go itself
+func internalError(format string, args ...interface{}) {
+	internalErrorLock.Lock()
+	defer internalErrorLock.Unlock()
+
+	fmt.Fprintf(internalErrorWriter, "%s blog4go: %s\n", timeCache.Format(), fmt.Sprintf(format, args...))
+}
+
+// unsupported reports a setting called on a writer not supporting it, so
+// that it is not ignored silently
+func unsupported(name, setting string) {
+	internalError("%s is not supported by writer %q, ignored", setting, name)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type panicHook struct{}
+
+func (hook *panicHook) Fire(t time.Time, level LevelType, message string) {
+	panic("boom")
+}
+
+func TestInternalErrorWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	SetInternalErrorWriter(buf)
+	defer SetInternalErrorWriter(os.Stderr)
+
+	os.MkdirAll("/tmp/blog4go_internal", 0755)
+	writer, err := newBaseFileWriter("/tmp/blog4go_internal/rotate.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer writer.Close()
+
+	// rotation fails once the directory is gone
+	writer.SetRetentions(2)
+	os.RemoveAll("/tmp/blog4go_internal")
+	if err = writer.Rotate(); nil == err {
+		t.Fatal("rotate should fail without the directory.")
+	}
+
+	if !strings.Contains(buf.String(), " blog4go: rotate /tmp/blog4go_internal/rotate.log failed: ") {
+		t.Errorf("rotation failure should be written to internal error writer. content: %s", buf.String())
+	}
+
+	// hook panics are recovered
+	buf.Reset()
+	writer.SetHook(new(panicHook))
+	writer.SetHookAsync(false)
+	writer.Info("fire")
+	if !strings.Contains(buf.String(), " blog4go: hook panic: boom\n") {
+		t.Errorf("hook panic should be written to internal error writer. content: %s", buf.String())
+	}
+}
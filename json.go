@@ -0,0 +1,86 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// JSONTimeKey is the key of logging time in json lines
+	JSONTimeKey = "time"
+	// JSONLevelKey is the key of logging level in json lines
+	JSONLevelKey = "level"
+	// JSONMessageKey is the key of message in json lines
+	JSONMessageKey = "msg"
+	// JSONErrorKey is the key of error when fields can not be encoded
+	JSONErrorKey = "error"
+
+	// JSONReservedPrefix namespaces user keys which clash with standard keys,
+	// e.g. user key "time" is written as "fields.time"
+	JSONReservedPrefix = "fields."
+)
+
+// jsonRecord merges standard fields and user fields into a single record
+func jsonRecord(t time.Time, level LevelType, fields map[string]interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(fields)+2)
+	for key, value := range fields {
+		switch key {
+		case JSONTimeKey, JSONLevelKey, JSONMessageKey:
+			key = JSONReservedPrefix + key
+		}
+		record[key] = value
+	}
+
+	record[JSONTimeKey] = t.Format(time.RFC3339)
+	record[JSONLevelKey] = level.String()
+	return record
+}
+
+// jsonEncoder encodes records as newline delimited json into a reused buffer.
+// it is not thread safe, callers should hold their own lock
+type jsonEncoder struct {
+	buf     *bytes.Buffer
+	encoder *json.Encoder
+}
+
+// newJSONEncoder create a jsonEncoder
+func newJSONEncoder() *jsonEncoder {
+	buf := new(bytes.Buffer)
+	return &jsonEncoder{buf: buf, encoder: json.NewEncoder(buf)}
+}
+
+// encode return a json line of fields ending with EOL. the line is only
+// valid until next call. when fields can not be encoded, such as channels or
+// functions inside, the line keeps standard fields with the error instead
+func (e *jsonEncoder) encode(t time.Time, level LevelType, fields map[string]interface{}) []byte {
+	e.buf.Reset()
+	if err := e.encoder.Encode(jsonRecord(t, level, fields)); nil != err {
+		e.buf.Reset()
+		e.encoder.Encode(jsonRecord(t, level, map[string]interface{}{JSONErrorKey: err.Error()}))
+	}
+	return e.buf.Bytes()
+}
+
+// encodeMessage return a json line of message ending with EOL, the line is
+// only valid until next call
+func (e *jsonEncoder) encodeMessage(t time.Time, level LevelType, message string) []byte {
+	record := jsonRecord(t, level, nil)
+	record[JSONMessageKey] = message
+
+	e.buf.Reset()
+	e.encoder.Encode(record)
+	return e.buf.Bytes()
+}
+
+// jsonFields return fields encoded as a json object, or the error when they
+// can not be encoded
+func jsonFields(fields map[string]interface{}) string {
+	b, err := json.Marshal(fields)
+	if nil != err {
+		return err.Error()
+	}
+	return string(b)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBLogWriteJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+
+	blog.writeJSON(INFO, map[string]interface{}{
+		"user":  "alice",
+		"code":  401,
+		"ratio": 0.5,
+		"ok":    false,
+		"tags":  []string{"a", "b"},
+		"request": map[string]interface{}{
+			"method": "GET",
+			"header": map[string]interface{}{"retry": 3},
+		},
+		"time": "user time",
+		"msg":  "login",
+	})
+	blog.flush()
+
+	line := buf.String()
+	if 1 != strings.Count(line, "\n") || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("json should be a single line. line: %s", line)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); nil != err {
+		t.Fatalf("json line invalid. err: %s, line: %s", err.Error(), line)
+	}
+
+	if "INFO" != record[JSONLevelKey] || nil == record[JSONTimeKey] || "user time" == record[JSONTimeKey] {
+		t.Errorf("standard fields wrong. line: %s", line)
+	}
+
+	if "user time" != record["fields.time"] || "login" != record["fields.msg"] {
+		t.Errorf("reserved keys should be namespaced. line: %s", line)
+	}
+
+	if "alice" != record["user"] || float64(401) != record["code"] || 0.5 != record["ratio"] || false != record["ok"] {
+		t.Errorf("scalar fields wrong. line: %s", line)
+	}
+
+	if tags, ok := record["tags"].([]interface{}); !ok || 2 != len(tags) || "b" != tags[1] {
+		t.Errorf("array field wrong. line: %s", line)
+	}
+
+	request, ok := record["request"].(map[string]interface{})
+	if !ok || "GET" != request["method"] {
+		t.Fatalf("nested field wrong. line: %s", line)
+	}
+	if header, ok := request["header"].(map[string]interface{}); !ok || float64(3) != header["retry"] {
+		t.Errorf("nested field wrong. line: %s", line)
+	}
+
+	// fields can not be encoded
+	buf.Reset()
+	blog.writeJSON(INFO, map[string]interface{}{"ch": make(chan int)})
+	blog.flush()
+
+	record = nil
+	if err := json.Unmarshal(buf.Bytes(), &record); nil != err || nil == record[JSONErrorKey] || "INFO" != record[JSONLevelKey] {
+		t.Errorf("unsupported fields should be replaced with error. line: %s", buf.String())
+	}
+}
+
+func TestSinkWriterInfoJSON(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+
+	writer.InfoJSON(map[string]interface{}{"user": "alice"})
+	if 1 != len(sink.messages) {
+		t.Fatalf("json message count wrong. count: %d", len(sink.messages))
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(sink.messages[0]), &record); nil != err || "alice" != record["user"] {
+		t.Errorf("json message wrong. message: %s", sink.messages[0])
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package kafka provides a blog4go writer producing every formatted line as
+// a message to a Kafka topic. It lives in its own package so that only users
+// who need it depend on a Kafka client, and it depends on Producer only, a
+// client such as a sarama SyncProducer can be adapted to it with a few lines.
+package kafka
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+const (
+	// DefaultInFlight is the default number of messages waiting for delivery,
+	// logging blocks when it is reached
+	DefaultInFlight = 1024
+)
+
+var (
+	// ErrNoDialer show that Dialer is not set
+	ErrNoDialer = errors.New("kafka: Dialer is not set")
+
+	// Dialer creates a producer connected to brokers, it must be set before
+	// calling NewKafkaWriter
+	Dialer func(brokers []string) (Producer, error)
+)
+
+// Producer delivers a message to topic, SendMessage returns when the
+// message is acknowledged by brokers
+type Producer interface {
+	SendMessage(topic string, key, value []byte) error
+	Close() error
+}
+
+// KeyFunc returns partitioning key of a message, nil key leaves
+// partitioning to the producer
+type KeyFunc func(level blog4go.LevelType, line []byte) []byte
+
+// ByLevel uses level of a message as its key, so that messages of the same
+// level go to the same partition
+func ByLevel(level blog4go.LevelType, line []byte) []byte {
+	return []byte(level.String())
+}
+
+// ByHostname uses hostname as key of every message, so that messages of a
+// host keep in order. hostname is resolved once when it is called, e.g.
+// WithKey(ByHostname())
+func ByHostname() KeyFunc {
+	hostname, _ := os.Hostname()
+	key := []byte(hostname)
+	return func(level blog4go.LevelType, line []byte) []byte {
+		return key
+	}
+}
+
+// Option configures the writer created by NewKafkaWriter
+type Option func(s *sink)
+
+// WithKey sets partitioning key of messages
+func WithKey(key KeyFunc) Option {
+	return func(s *sink) {
+		s.key = key
+	}
+}
+
+// WithInFlight sets number of messages waiting for delivery
+func WithInFlight(inFlight int) Option {
+	return func(s *sink) {
+		if 0 < inFlight {
+			s.inFlight = inFlight
+		}
+	}
+}
+
+// WithErrorHandler sets handler called when a message fails to be delivered
+func WithErrorHandler(handler blog4go.ErrorHandler) Option {
+	return func(s *sink) {
+		s.handler = handler
+	}
+}
+
+// message is a message waiting for delivery
+type message struct {
+	key   []byte
+	value []byte
+}
+
+// sink queues formatted lines and delivers them in background
+type sink struct {
+	topic    string
+	producer Producer
+
+	key      KeyFunc
+	inFlight int
+	handler  blog4go.ErrorHandler
+
+	queue chan message
+	// number of messages not delivered yet, delivered is signaled whenever
+	// it drops to zero
+	pending   int
+	delivered *sync.Cond
+	lock      *sync.Mutex
+	// exits when queue is closed and drained
+	done chan struct{}
+}
+
+// Emit formats message as a line and queues it, it blocks when in-flight
+// buffer is full
+func (s *sink) Emit(t time.Time, level blog4go.LevelType, msg string) error {
+	line := make([]byte, 0, len(blog4go.PrefixTimeFormat)+len(msg)+16)
+	line = t.AppendFormat(line, blog4go.PrefixTimeFormat)
+	line = append(line, " ["...)
+	line = append(line, level.String()...)
+	line = append(line, "] "...)
+	line = append(line, msg...)
+
+	var key []byte
+	if nil != s.key {
+		key = s.key(level, line)
+	}
+
+	s.lock.Lock()
+	s.pending++
+	s.lock.Unlock()
+
+	s.queue <- message{key: key, value: line}
+	return nil
+}
+
+// deliver sends queued messages until queue is closed
+func (s *sink) deliver() {
+	defer close(s.done)
+
+	for m := range s.queue {
+		if err := s.producer.SendMessage(s.topic, m.key, m.value); nil != err && nil != s.handler {
+			s.handler(err)
+		}
+
+		s.lock.Lock()
+		if s.pending--; 0 == s.pending {
+			s.delivered.Broadcast()
+		}
+		s.lock.Unlock()
+	}
+}
+
+// Flush waits for messages queued at the time to be delivered, messages
+// queued meanwhile are waited for as well
+func (s *sink) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for 0 < s.pending {
+		s.delivered.Wait()
+	}
+	return nil
+}
+
+// Close waits for outstanding deliveries and closes the producer
+func (s *sink) Close() error {
+	close(s.queue)
+	<-s.done
+	return s.producer.Close()
+}
+
+// NewKafkaWriter creates a writer producing messages to topic through a
+// producer created by Dialer with brokers
+func NewKafkaWriter(brokers []string, topic string, opts ...Option) (blog4go.Writer, error) {
+	if nil == Dialer {
+		return nil, ErrNoDialer
+	}
+
+	producer, err := Dialer(brokers)
+	if nil != err {
+		return nil, err
+	}
+
+	return NewKafkaWriterWithProducer(producer, topic, opts...), nil
+}
+
+// NewKafkaWriterWithProducer creates a writer producing messages to topic
+// with producer, the producer is closed when the writer is closed
+func NewKafkaWriterWithProducer(producer Producer, topic string, opts ...Option) blog4go.Writer {
+	s := &sink{
+		topic:    topic,
+		producer: producer,
+		inFlight: DefaultInFlight,
+		lock:     new(sync.Mutex),
+		done:     make(chan struct{}),
+	}
+	s.delivered = sync.NewCond(s.lock)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.queue = make(chan message, s.inFlight)
+	go s.deliver()
+
+	return blog4go.NewSinkWriter(s)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package kafka
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// mockProducer keeps every message delivered
+type mockProducer struct {
+	topics []string
+	keys   []string
+	values []string
+	closed bool
+
+	// delay of every delivery
+	delay time.Duration
+	// error returned for every delivery
+	err error
+
+	l *sync.Mutex
+}
+
+func newMockProducer() *mockProducer {
+	return &mockProducer{l: new(sync.Mutex)}
+}
+
+func (p *mockProducer) SendMessage(topic string, key, value []byte) error {
+	time.Sleep(p.delay)
+	if nil != p.err {
+		return p.err
+	}
+
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.topics = append(p.topics, topic)
+	p.keys = append(p.keys, string(key))
+	p.values = append(p.values, string(value))
+	return nil
+}
+
+func (p *mockProducer) Close() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.closed = true
+	return nil
+}
+
+func TestKafkaWriter(t *testing.T) {
+	producer := newMockProducer()
+	writer := NewKafkaWriterWithProducer(producer, "logs", WithKey(ByLevel))
+
+	writer.SetLevel(blog4go.INFO)
+	writer.Debug("dropped")
+	writer.Infof("hello %s", "eddie")
+	writer.Error("boom")
+
+	if err := writer.Drain(); nil != err {
+		t.Fatalf("drain failed. err: %s", err.Error())
+	}
+
+	producer.l.Lock()
+	if 2 != len(producer.values) {
+		t.Fatalf("messages count wrong. count: %d", len(producer.values))
+	}
+	if "logs" != producer.topics[0] || "INFO" != producer.keys[0] || "ERROR" != producer.keys[1] {
+		t.Errorf("message topic or key wrong. topics: %v, keys: %v", producer.topics, producer.keys)
+	}
+	if !strings.HasPrefix(producer.values[0], "[") || !strings.HasSuffix(producer.values[0], " [INFO] hello eddie") {
+		t.Errorf("message should be a formatted line. value: %s", producer.values[0])
+	}
+	producer.l.Unlock()
+
+	writer.Close()
+	if !producer.closed {
+		t.Error("producer should be closed with the writer.")
+	}
+}
+
+func TestKafkaWriterCloseWaits(t *testing.T) {
+	producer := newMockProducer()
+	producer.delay = time.Millisecond
+	writer := NewKafkaWriterWithProducer(producer, "logs", WithInFlight(4))
+
+	for i := 0; i < 20; i++ {
+		writer.Info(i)
+	}
+	writer.Close()
+
+	if 20 != len(producer.values) || "" != producer.keys[0] {
+		t.Errorf("close should wait for outstanding deliveries. count: %d", len(producer.values))
+	}
+}
+
+func TestKafkaWriterFlushWhileLogging(t *testing.T) {
+	producer := newMockProducer()
+	writer := NewKafkaWriterWithProducer(producer, "logs", WithInFlight(4))
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				writer.Info(j)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		writer.Drain()
+	}
+	wg.Wait()
+
+	writer.Drain()
+	producer.l.Lock()
+	if 400 != len(producer.values) {
+		t.Errorf("flush should wait for every message queued. count: %d", len(producer.values))
+	}
+	producer.l.Unlock()
+	writer.Close()
+}
+
+func TestKafkaWriterErrorHandler(t *testing.T) {
+	producer := newMockProducer()
+	producer.err = errors.New("broker down")
+
+	var failures []error
+	writer := NewKafkaWriterWithProducer(producer, "logs", WithErrorHandler(func(err error) {
+		failures = append(failures, err)
+	}))
+
+	writer.Info("lost")
+	writer.Close()
+
+	if 1 != len(failures) || producer.err != failures[0] {
+		t.Errorf("error handler should be called for failed delivery. failures: %v", failures)
+	}
+}
+
+func TestNewKafkaWriter(t *testing.T) {
+	defer func(dialer func(brokers []string) (Producer, error)) {
+		Dialer = dialer
+	}(Dialer)
+
+	Dialer = nil
+	if _, err := NewKafkaWriter([]string{"localhost:9092"}, "logs"); ErrNoDialer != err {
+		t.Error("creating writer without dialer should fail.")
+	}
+
+	producer := newMockProducer()
+	var dialed []string
+	Dialer = func(brokers []string) (Producer, error) {
+		dialed = brokers
+		return producer, nil
+	}
+
+	writer, err := NewKafkaWriter([]string{"localhost:9092"}, "logs", WithKey(ByHostname()))
+	if nil != err {
+		t.Fatalf("create writer failed. err: %s", err.Error())
+	}
+	writer.Info("hello")
+	writer.Close()
+
+	if 1 != len(dialed) || 1 != len(producer.values) || "" == producer.keys[0] {
+		t.Errorf("writer should produce with dialed producer. brokers: %v, messages: %v", dialed, producer.values)
+	}
+}
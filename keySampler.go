@@ -0,0 +1,82 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"container/list"
+	"sync"
+)
+
+var (
+	// SampledKeysLimit bounds keys LogSampled keeps counters of, counter of
+	// the least recently logged key is evicted beyond it and the key starts
+	// over
+	SampledKeysLimit = 10000
+
+	// keySamples holds counters of keys logged by LogSampled
+	keySamples = newKeySampler()
+)
+
+// keyCounter counts messages logged with a key
+type keyCounter struct {
+	key string
+	n   int
+}
+
+// keySampler counts messages of every key, bounded in LRU order
+type keySampler struct {
+	// counters of keys most recently logged at front
+	order    *list.List
+	counters map[string]*list.Element
+
+	lock *sync.Mutex
+}
+
+// newKeySampler create a keySampler without keys
+func newKeySampler() *keySampler {
+	return &keySampler{order: list.New(), counters: make(map[string]*list.Element), lock: new(sync.Mutex)}
+}
+
+// sampled counts a message with key and determines whether it should be
+// dropped, the first one and every everyN-th after are kept
+func (s *keySampler) sampled(key string, everyN int) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	elem, ok := s.counters[key]
+	if ok {
+		s.order.MoveToFront(elem)
+	} else {
+		elem = s.order.PushFront(&keyCounter{key: key})
+		s.counters[key] = elem
+
+		for 0 < SampledKeysLimit && s.order.Len() > SampledKeysLimit {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.counters, oldest.Value.(*keyCounter).key)
+		}
+	}
+
+	counter := elem.Value.(*keyCounter)
+	counter.n++
+	return everyN > 1 && 1 != counter.n%everyN
+}
+
+// LogSampled logs message with level to the singleton writer 1 in everyN
+// times for every key, starting with the first one, e.g. keyed by customer so
+// that a noisy one is sampled without affecting others. everyN <= 1 logs all
+// messages
+func LogSampled(key string, level LevelType, message string, everyN int) {
+	if level < CompileLevel {
+		return
+	}
+
+	if keySamples.sampled(key, everyN) {
+		CountDropped(DropSampled)
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	logs(holder.writer, level, message)
+}
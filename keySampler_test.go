@@ -0,0 +1,48 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogSampled(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+	sink := newMySink()
+	setBlog(NewSinkWriter(sink))
+
+	for i := 1; i <= 10; i++ {
+		LogSampled("customer:noisy", WARNING, fmt.Sprintf("noisy %d", i), 5)
+		if 0 == i%2 {
+			LogSampled("customer:quiet", WARNING, fmt.Sprintf("quiet %d", i), 5)
+		}
+	}
+
+	// keys are sampled independently
+	expected := []string{"noisy 1", "quiet 2", "noisy 6"}
+	if fmt.Sprint(expected) != fmt.Sprint(sink.messages) {
+		t.Errorf("messages sampled wrong. got: %q", sink.messages)
+	}
+}
+
+func TestKeySamplerLimit(t *testing.T) {
+	defer func(limit int) { SampledKeysLimit = limit }(SampledKeysLimit)
+	SampledKeysLimit = 2
+
+	s := newKeySampler()
+	s.sampled("a", 2)
+	s.sampled("b", 2)
+	s.sampled("c", 2)
+	if 2 != s.order.Len() || 2 != len(s.counters) {
+		t.Fatalf("keys should be bounded. got: %d", len(s.counters))
+	}
+
+	// counter of a is evicted, it starts over
+	if s.sampled("a", 2) {
+		t.Errorf("evicted key should start over")
+	}
+	if !s.sampled("c", 2) {
+		t.Errorf("second message of kept key should be sampled")
+	}
+}
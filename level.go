@@ -32,6 +32,9 @@ const (
 	// DefaultLevel default level for writers
 	DefaultLevel = DEBUG
 
+	// noFlushLevel is above every valid level, used to disable flushLevel
+	noFlushLevel = CRITICAL + 1
+
 	// PrefixFormat is the level format ahead every message
 	PrefixFormat = " [%s] " // pure format
 	// ColoredPrefixFormat is the colored level format adhead every message
@@ -66,10 +69,31 @@ var (
 	// Prefix is preformatted level prefix string
 	// help reduce string formatted burden in realtime logging
 	Prefix = make(map[LevelType]string)
+
+	// prefixBytes is Prefix in bytes, indexed by level
+	prefixBytes [len(Levels)][]byte
+
+	// CompileLevel is checked ahead of any other work in every logging
+	// function, messages below it are skipped with a single int compare.
+	// default TRACE, which skips nothing. It is not protected by any lock,
+	// set it before logging starts, or at build time through BuildLevel
+	CompileLevel = TRACE
+
+	// BuildLevel is the level string CompileLevel is initialized with, it is
+	// designed to be set by linker in release builds, e.g.
+	//
+	//	go build -ldflags "-X github.com/YoungPioneers/blog4go.BuildLevel=INFO"
+	//
+	// invalid level strings are ignored
+	BuildLevel = ""
 )
 
 func init() {
 	initPrefix(false) // preformat level prefix string
+
+	if level := LevelFromString(BuildLevel); level.valid() {
+		CompileLevel = level
+	}
 }
 
 // initPrefix is designed to preformat level prefix string for each level.
@@ -91,6 +115,11 @@ func initPrefix(colored bool) {
 		Prefix[ERROR] = fmt.Sprintf(PrefixFormat, ERROR.String())
 		Prefix[CRITICAL] = fmt.Sprintf(PrefixFormat, CRITICAL.String())
 	}
+
+	// convert once here rather than every line
+	for _, level := range Levels {
+		prefixBytes[level] = []byte(Prefix[level])
+	}
 }
 
 // valid determines whether a Level instance is valid or not
@@ -114,6 +143,15 @@ func (level LevelType) prefix() string {
 	return Prefix[level]
 }
 
+// prefixBytes return formatted prefix bytes associate with a Level instance,
+// written in hot path without allocation
+func (level LevelType) prefixBytes() []byte {
+	if !level.valid() {
+		return nil
+	}
+	return prefixBytes[level]
+}
+
 // LevelFromString return Level according to given string
 func LevelFromString(str string) LevelType {
 	level, ok := StringLevels[strings.ToUpper(str)]
@@ -3,6 +3,9 @@
 package blog4go
 
 import (
+	"bufio"
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -92,3 +95,117 @@ func TestStringToLevel(t *testing.T) {
 		t.Error("Empty string to level invalid.")
 	}
 }
+
+func TestPushLevel(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	sink := newMySink()
+	setBlog(NewSinkWriter(sink))
+	blog().SetLevel(DEBUG)
+
+	func() {
+		defer PushLevel(ERROR)()
+
+		if ERROR != Level() {
+			t.Errorf("level should be pushed. level: %s", Level().String())
+		}
+
+		Info("suppressed")
+		Error("written")
+	}()
+
+	if DEBUG != Level() {
+		t.Errorf("level should be restored. level: %s", Level().String())
+	}
+
+	Info("restored")
+	if 2 != len(sink.messages) || "written" != sink.messages[0] || "restored" != sink.messages[1] {
+		t.Errorf("messages wrong. messages: %v", sink.messages)
+	}
+}
+
+func TestCompileLevel(t *testing.T) {
+	defer func(level LevelType) { CompileLevel = level }(CompileLevel)
+
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetLevel(TRACE)
+
+	CompileLevel = INFO
+	writer.Debug("skipped")
+	writer.Debugf("%s", "skipped")
+	writer.Entry(DEBUG).Str("key", "value").Msg("skipped")
+	writer.Info("written")
+
+	if 1 != len(sink.messages) || "written" != sink.messages[0] {
+		t.Errorf("messages below compile level should be skipped. messages: %v", sink.messages)
+	}
+}
+
+func BenchmarkCompileLevelDebugf(b *testing.B) {
+	defer func(level LevelType) { CompileLevel = level }(CompileLevel)
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	setBlog(NewSinkWriter(newMySink()))
+	CompileLevel = INFO
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Debugf("user %s id %d", "alice", i)
+	}
+}
+
+func TestPrefixBytes(t *testing.T) {
+	initPrefix(false)
+	for _, level := range Levels {
+		if level.prefix() != string(level.prefixBytes()) {
+			t.Errorf("prefix bytes wrong. level: %s, prefix: %s", level.String(), string(level.prefixBytes()))
+		}
+	}
+
+	initPrefix(true)
+	defer initPrefix(false)
+	if Prefix[ERROR] != string(ERROR.prefixBytes()) {
+		t.Errorf("prefix bytes should be regenerated. prefix: %s", string(ERROR.prefixBytes()))
+	}
+
+	if nil != LevelType(-1).prefixBytes() {
+		t.Error("prefix bytes of invalid level should be nil.")
+	}
+
+	w := bufio.NewWriter(ioutil.Discard)
+	if allocs := testing.AllocsPerRun(100, func() { w.Write(INFO.prefixBytes()) }); 0 != allocs {
+		t.Errorf("writing prefix should not allocate. allocs: %f", allocs)
+	}
+}
+
+func BenchmarkPrefixBytes(b *testing.B) {
+	w := bufio.NewWriter(ioutil.Discard)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Write(Levels[i%len(Levels)].prefixBytes())
+	}
+}
+
+func TestSetLevelChaining(t *testing.T) {
+	fileWriter, err := newBaseFileWriter("/tmp/chain.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		fileWriter.Close()
+		os.Remove("/tmp/chain.log")
+	}()
+
+	for _, writer := range []Writer{fileWriter, NewSinkWriter(newMySink())} {
+		writer.SetLevel(WARNING).SetTimeFormat("2006-01-02")
+		if WARNING != writer.Level() {
+			t.Errorf("level should be set through chaining. level: %s", writer.Level().String())
+		}
+	}
+
+	if "2006-01-02" != fileWriter.Config().TimeFormat {
+		t.Errorf("time format should be set through chaining. time format: %s", fileWriter.Config().TimeFormat)
+	}
+}
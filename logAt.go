@@ -0,0 +1,51 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"time"
+)
+
+// writeAt writes message with specific level, prefixed with time t instead of
+// now. It bypasses the time cache
+func (blog *BLog) writeAt(t time.Time, level LevelType, message string) int {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.at = t
+	defer func() { blog.at = time.Time{} }()
+	return blog.writeMessage(level, message)
+}
+
+// writefAt formats message with specific level, prefixed with time t instead
+// of now. It return the message body written as well
+func (blog *BLog) writefAt(t time.Time, level LevelType, format string, args ...interface{}) (int, string) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.at = t
+	defer func() { blog.at = time.Time{} }()
+	return blog.writefBodyLocked(level, format, args...)
+}
+
+// LogAt static function for LogAt
+func LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.LogAt(t, level, message)
+}
+
+// LogfAt static function for LogfAt
+func LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	if level < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.LogfAt(t, level, format, args...)
+}
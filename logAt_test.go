@@ -0,0 +1,88 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogAt(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	initPrefix(false)
+
+	at := time.Date(2016, 7, 17, 8, 30, 15, 0, time.Local)
+	writer.LogAt(at, INFO, "replayed")
+	writer.LogfAt(at.Add(time.Second), WARNING, "replayed %d", 2)
+	writer.Info("now")
+	writer.blog.flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if 3 != len(lines) {
+		t.Fatalf("lines written wrong. got: %q", lines)
+	}
+	if "[2016/07/17:08:30:15] [INFO] replayed" != lines[0] {
+		t.Errorf("line should show the time given. got: %s", lines[0])
+	}
+	if "[2016/07/17:08:30:16] [WARN] replayed 2" != lines[1] {
+		t.Errorf("formatted line should show the time given. got: %s", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "["+timeCache.Now().Format("2006")) {
+		t.Errorf("line after should show the current time. got: %s", lines[2])
+	}
+
+	// layout set by SetTimeFormat applies to the time given
+	buf.Reset()
+	writer.blog.SetTimeFormat(time.RFC3339)
+	writer.LogAt(at, INFO, "replayed")
+	writer.blog.flush()
+	if !strings.HasPrefix(buf.String(), at.Format(time.RFC3339)) {
+		t.Errorf("line should show the time given in layout set. got: %s", buf.String())
+	}
+
+	// sink receives the time given
+	sink := &timeSink{mySink: newMySink()}
+	NewSinkWriter(sink).LogAt(at, ERROR, "replayed")
+	if 1 != len(sink.times) || !sink.times[0].Equal(at) {
+		t.Errorf("sink should receive the time given. got: %v", sink.times)
+	}
+}
+
+// timeSink records time of messages emitted
+type timeSink struct {
+	*mySink
+	times []time.Time
+}
+
+func (sink *timeSink) Emit(t time.Time, level LevelType, message string) error {
+	sink.times = append(sink.times, t)
+	return sink.mySink.Emit(t, level, message)
+}
+
+func TestLogAtHookTime(t *testing.T) {
+	at := time.Date(2016, 7, 17, 8, 30, 15, 0, time.Local)
+
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	sinkWriter := NewSinkWriter(newMySink())
+
+	for _, w := range []Writer{writer, sinkWriter, NewRouterWriter().Route(TRACE, CRITICAL, sinkWriter)} {
+		hook := NewMyHook()
+		w.SetHook(hook)
+		w.SetHookLevel(INFO)
+		w.SetHookAsync(false)
+
+		w.LogAt(at, INFO, "replayed")
+		if !hook.Time().Equal(at) {
+			t.Errorf("hook should receive the time given. got: %s", hook.Time())
+		}
+
+		w.LogfAt(at.Add(time.Second), WARNING, "replayed %d", 2)
+		if !hook.Time().Equal(at.Add(time.Second)) {
+			t.Errorf("hook of LogfAt should receive the time given. got: %s", hook.Time())
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoLogFiles is returned when no log files match the pattern
+	ErrNoLogFiles = errors.New("No log files match the pattern")
+)
+
+// LogReader reads lines of a log file and its archives in chronological
+// order, it is the read side of writers for tooling
+type LogReader interface {
+	// ReadLine return the next line without EOL, io.EOF after the last one
+	ReadLine() (string, error)
+	// Tail reads through the rest lines and return the last n of them
+	Tail(n int) ([]string, error)
+	// Close closes the file being read
+	Close() error
+}
+
+// logReader reads files one by one
+type logReader struct {
+	files []string
+
+	// file being read and its reader, nil if none
+	file   *os.File
+	reader *bufio.Reader
+}
+
+// NewLogReader creates a LogReader of files matching pattern, such as
+// "/var/log/app.log*" for app.log and its archives. Archives rotated by size
+// or lines, xxx.N, are older with bigger N and come first, the others such as
+// the current file and time based archives follow sorted by name. Archives
+// compressed with gzip, xxx.gz, are decompressed transparently
+func NewLogReader(pattern string) (LogReader, error) {
+	files, err := filepath.Glob(pattern)
+	if nil != err {
+		return nil, err
+	}
+	if 0 == len(files) {
+		return nil, ErrNoLogFiles
+	}
+
+	sort.Sort(byArchive(files))
+	return &logReader{files: files}, nil
+}
+
+// archiveIndex return N of archive xxx.N or xxx.N.gz, -1 if it is not
+func archiveIndex(name string) int {
+	name = strings.TrimSuffix(name, ".gz")
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		return -1
+	}
+
+	n, err := strconv.Atoi(name[i+1:])
+	if nil != err || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// byArchive sorts log files in chronological order
+type byArchive []string
+
+func (files byArchive) Len() int      { return len(files) }
+func (files byArchive) Swap(i, j int) { files[i], files[j] = files[j], files[i] }
+
+func (files byArchive) Less(i, j int) bool {
+	a, b := archiveIndex(files[i]), archiveIndex(files[j])
+	if a != b {
+		return a > b
+	}
+	return files[i] < files[j]
+}
+
+// next opens the next file, it return io.EOF after the last one
+func (r *logReader) next() error {
+	r.Close()
+	if 0 == len(r.files) {
+		return io.EOF
+	}
+
+	file, err := os.Open(r.files[0])
+	if nil != err {
+		return err
+	}
+	name := r.files[0]
+	r.files = r.files[1:]
+
+	var in io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		if in, err = gzip.NewReader(file); nil != err {
+			file.Close()
+			return err
+		}
+	}
+
+	r.file = file
+	r.reader = bufio.NewReader(in)
+	return nil
+}
+
+// ReadLine return the next line without EOL, io.EOF after the last one
+func (r *logReader) ReadLine() (string, error) {
+	for {
+		if nil == r.reader {
+			if err := r.next(); nil != err {
+				return "", err
+			}
+		}
+
+		line, err := r.reader.ReadString(EOL)
+		if "" != line {
+			return strings.TrimSuffix(line, string(EOL)), nil
+		}
+		if io.EOF != err {
+			return "", err
+		}
+
+		// 当前文件读完，读下一个
+		r.Close()
+	}
+}
+
+// Tail reads through the rest lines and return the last n of them
+func (r *logReader) Tail(n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	// ring of the last n lines
+	lines := make([]string, 0, n)
+	var head int
+	for {
+		line, err := r.ReadLine()
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, err
+		}
+
+		if len(lines) < n {
+			lines = append(lines, line)
+		} else {
+			lines[head] = line
+			head = (head + 1) % n
+		}
+	}
+
+	return append(lines[head:], lines[:head]...), nil
+}
+
+// Close closes the file being read
+func (r *logReader) Close() error {
+	r.reader = nil
+	if nil == r.file {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestLogReader(t *testing.T) {
+	defer func() {
+		// clean logs
+		_, err := exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	// oldest archive gzipped, newest lines in current file
+	gz, err := os.Create("/tmp/reader.log.10.gz")
+	if nil != err {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(gz)
+	fmt.Fprint(zw, "line 1\nline 2\n")
+	zw.Close()
+	gz.Close()
+
+	ioutil.WriteFile("/tmp/reader.log.2", []byte("line 3\nline 4\n"), DefaultFileMode)
+	ioutil.WriteFile("/tmp/reader.log.1", []byte("line 5\n"), DefaultFileMode)
+	// the last line without EOL is kept
+	ioutil.WriteFile("/tmp/reader.log", []byte("line 6\nline 7"), DefaultFileMode)
+
+	reader, err := NewLogReader("/tmp/reader.log*")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	for i := 1; i <= 7; i++ {
+		line, err := reader.ReadLine()
+		if nil != err || fmt.Sprintf("line %d", i) != line {
+			t.Fatalf("lines should be read in chronological order. expected: line %d, line: %s, err: %v", i, line, err)
+		}
+	}
+	if _, err = reader.ReadLine(); io.EOF != err {
+		t.Errorf("io.EOF should be returned after the last line. err: %v", err)
+	}
+
+	// the last lines across archives
+	reader, err = NewLogReader("/tmp/reader.log*")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	lines, err := reader.Tail(4)
+	if nil != err || 4 != len(lines) || "line 4" != lines[0] || "line 7" != lines[3] {
+		t.Errorf("tail lines wrong. lines: %v, err: %v", lines, err)
+	}
+
+	if _, err = NewLogReader("/tmp/nothing.log*"); ErrNoLogFiles != err {
+		t.Errorf("no files should be an error. err: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryLine is a message kept in memory
+type memoryLine struct {
+	t       time.Time
+	level   LevelType
+	message string
+}
+
+// memorySink keeps the last capacity messages in a ring
+type memorySink struct {
+	lines []memoryLine
+	// next is where the next message goes, full tells whether ring wrapped
+	next int
+	full bool
+
+	lock *sync.RWMutex
+}
+
+// Emit keeps message, overwriting the oldest one when ring is full
+func (sink *memorySink) Emit(t time.Time, level LevelType, message string) error {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	sink.lines[sink.next] = memoryLine{t: t, level: level, message: message}
+	sink.next++
+	if sink.next == len(sink.lines) {
+		sink.next = 0
+		sink.full = true
+	}
+	return nil
+}
+
+// Flush do nothing
+func (sink *memorySink) Flush() error {
+	return nil
+}
+
+// Close do nothing, lines are kept for viewing
+func (sink *memorySink) Close() error {
+	return nil
+}
+
+// recent return lines at or above level, at most tail of the newest ones if
+// tail is positive, oldest first
+func (sink *memorySink) recent(level LevelType, tail int) []memoryLine {
+	sink.lock.RLock()
+	defer sink.lock.RUnlock()
+
+	ordered := sink.lines[:sink.next]
+	if sink.full {
+		ordered = append(append([]memoryLine{}, sink.lines[sink.next:]...), ordered...)
+	}
+
+	lines := make([]memoryLine, 0, len(ordered))
+	for _, line := range ordered {
+		if !(line.level < level) {
+			lines = append(lines, line)
+		}
+	}
+
+	if 0 < tail && tail < len(lines) {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines
+}
+
+// MemoryWriter is a writer keeping the last lines in memory, it is a
+// http.Handler serving them as plain text
+type MemoryWriter struct {
+	*SinkWriter
+
+	sink *memorySink
+}
+
+// NewMemoryWriter creates a writer keeping the last capacity lines in memory,
+// not singlton. mount it to view recent logs in browser, e.g.
+// http.Handle("/debug/logs", writer)
+func NewMemoryWriter(capacity int) *MemoryWriter {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	sink := &memorySink{lines: make([]memoryLine, capacity), lock: new(sync.RWMutex)}
+	sinkWriter := NewSinkWriter(sink)
+	sinkWriter.name = "memory"
+	return &MemoryWriter{SinkWriter: sinkWriter, sink: sink}
+}
+
+// ServeHTTP writes lines kept oldest first. query level=WARN keeps lines at or
+// above WARN only, and tail=N keeps the newest N lines only
+func (writer *MemoryWriter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	level := TRACE
+	if s := r.FormValue("level"); "" != s {
+		if level = LevelFromString(s); !level.valid() {
+			http.Error(w, fmt.Sprintf("invalid level %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	tail := 0
+	if s := r.FormValue("tail"); "" != s {
+		var err error
+		if tail, err = strconv.Atoi(s); nil != err || tail < 0 {
+			http.Error(w, fmt.Sprintf("invalid tail %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	for _, line := range writer.sink.recent(level, tail) {
+		buf.WriteString(line.t.Format(PrefixTimeFormat))
+		fmt.Fprintf(buf, PrefixFormat, line.level.String())
+		buf.WriteString(line.message)
+		buf.WriteByte(EOL)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(buf.Bytes())
+}
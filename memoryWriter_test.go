@@ -0,0 +1,78 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMemoryWriter(t *testing.T) {
+	writer := NewMemoryWriter(3)
+	writer.Info("dropped from ring")
+	writer.Info("first")
+	writer.Error("second")
+	writer.Infof("third %d", 3)
+
+	server := httptest.NewServer(writer)
+	defer server.Close()
+
+	get := func(query string) (int, []string) {
+		resp, err := http.Get(server.URL + "/debug/logs" + query)
+		if nil != err {
+			t.Fatalf("request failed. err: %s", err.Error())
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	}
+
+	_, lines := get("")
+	if 3 != len(lines) || !strings.HasSuffix(lines[0], "[INFO] first") || !strings.HasSuffix(lines[2], "[INFO] third 3") {
+		t.Errorf("recent lines wrong. lines: %v", lines)
+	}
+
+	_, lines = get("?tail=1")
+	if 1 != len(lines) || !strings.HasSuffix(lines[0], "third 3") {
+		t.Errorf("tail should keep the newest lines. lines: %v", lines)
+	}
+
+	_, lines = get("?level=error")
+	if 1 != len(lines) || !strings.HasSuffix(lines[0], "[ERROR] second") {
+		t.Errorf("level should filter lines. lines: %v", lines)
+	}
+
+	if code, _ := get("?level=loud"); http.StatusBadRequest != code {
+		t.Errorf("invalid level should be rejected. code: %d", code)
+	}
+}
+
+func TestMemoryWriterConcurrent(t *testing.T) {
+	writer := NewMemoryWriter(16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				writer.Infof("line %d", j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				writer.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/?tail=5", nil))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if 16 != len(writer.sink.recent(TRACE, 0)) {
+		t.Errorf("ring should be full")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"os"
+	"time"
+)
+
+// mirrorSink renders every message twice, as a text line into one file and
+// as a json line into another. message is formatted once by SinkWriter,
+// only the envelope differs
+type mirrorSink struct {
+	textFile *os.File
+	jsonFile *os.File
+
+	text *bufio.Writer
+	json *bufio.Writer
+
+	// line is reused for text lines
+	line    []byte
+	encoder *jsonEncoder
+}
+
+// Emit writes message as a text line and a json line
+func (sink *mirrorSink) Emit(t time.Time, level LevelType, message string) error {
+	sink.line = t.AppendFormat(sink.line[:0], PrefixTimeFormat)
+	sink.line = append(sink.line, level.prefixBytes()...)
+	sink.line = append(sink.line, message...)
+	sink.line = append(sink.line, EOL)
+	if _, err := sink.text.Write(sink.line); nil != err {
+		return err
+	}
+
+	_, err := sink.json.Write(sink.encoder.encodeMessage(t, level, message))
+	return err
+}
+
+// Flush flushes both files
+func (sink *mirrorSink) Flush() error {
+	err := sink.text.Flush()
+	if jsonErr := sink.json.Flush(); nil == err {
+		err = jsonErr
+	}
+	return err
+}
+
+// Close flushes and closes both files
+func (sink *mirrorSink) Close() error {
+	err := sink.Flush()
+	if textErr := sink.textFile.Close(); nil == err {
+		err = textErr
+	}
+	if jsonErr := sink.jsonFile.Close(); nil == err {
+		err = jsonErr
+	}
+	return err
+}
+
+// NewMirrorWriter creates a writer logging every message into two files, not
+// singlton. textPath receives human readable lines like a file writer does,
+// and jsonPath receives the same messages as json lines like InfoJSON writes,
+// e.g. {"level":"INFO","msg":"started","time":"..."}. messages are formatted
+// only once for both files
+func NewMirrorWriter(textPath, jsonPath string) (*SinkWriter, error) {
+	textFile, err := openLogFile(textPath, DefaultFileMode)
+	if nil != err {
+		return nil, err
+	}
+
+	jsonFile, err := openLogFile(jsonPath, DefaultFileMode)
+	if nil != err {
+		textFile.Close()
+		return nil, err
+	}
+
+	return NewSinkWriter(&mirrorSink{
+		textFile: textFile,
+		jsonFile: jsonFile,
+		text:     bufio.NewWriterSize(textFile, DefaultBufferSize),
+		json:     bufio.NewWriterSize(jsonFile, DefaultBufferSize),
+		encoder:  newJSONEncoder(),
+	}), nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestMirrorWriter(t *testing.T) {
+	initPrefix(false)
+	writer, err := NewMirrorWriter("/tmp/mirror.log", "/tmp/mirror.json.log")
+	if nil != err {
+		t.Fatalf("Failed when initializing mirror writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.Infof("user %s logged in", "alice")
+	writer.Error("disk full")
+	writer.Close()
+
+	text, err := os.Open("/tmp/mirror.log")
+	if nil != err {
+		t.Fatalf("open text file failed. err: %s", err.Error())
+	}
+	defer text.Close()
+	jsonFile, err := os.Open("/tmp/mirror.json.log")
+	if nil != err {
+		t.Fatalf("open json file failed. err: %s", err.Error())
+	}
+	defer jsonFile.Close()
+
+	expected := []struct {
+		level   LevelType
+		message string
+	}{
+		{INFO, "user alice logged in"},
+		{ERROR, "disk full"},
+	}
+
+	texts := bufio.NewScanner(text)
+	jsons := bufio.NewScanner(jsonFile)
+	for _, e := range expected {
+		if !texts.Scan() || !jsons.Scan() {
+			t.Fatalf("lines missing. expected: %s", e.message)
+		}
+
+		if !strings.HasSuffix(texts.Text(), e.level.prefix()+e.message) {
+			t.Errorf("text line wrong. line: %s, expected: %s", texts.Text(), e.message)
+		}
+
+		var record map[string]string
+		if err := json.Unmarshal(jsons.Bytes(), &record); nil != err {
+			t.Fatalf("json line should be valid. line: %s, err: %s", jsons.Text(), err.Error())
+		}
+		if e.message != record[JSONMessageKey] || e.level.String() != record[JSONLevelKey] || "" == record[JSONTimeKey] {
+			t.Errorf("json line wrong. line: %s, expected: %s", jsons.Text(), e.message)
+		}
+	}
+
+	if texts.Scan() || jsons.Scan() {
+		t.Errorf("extra lines written")
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package msgpack encodes log records as MessagePack maps framed by a length
+// prefix, which is more compact and faster to parse than json lines. It lives
+// in its own package and implements the subset of MessagePack log records
+// need, so that blog4go does not depend on a MessagePack library.
+package msgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+const (
+	// PrefixSize is the size of big endian length prefix of every record
+	PrefixSize = 4
+)
+
+var (
+	// ErrShortRecord is returned when a record is truncated
+	ErrShortRecord = errors.New("msgpack: short record")
+	// ErrBadRecord is returned when a record is not a map with string keys
+	ErrBadRecord = errors.New("msgpack: bad record")
+)
+
+// Formatter formats lines as framed records of time, level and msg, it can
+// be set by SetFormatter of writers. args of writef functions are formatted
+// into msg, fields of json functions are passed as a json object in msg
+func Formatter(t time.Time, level blog4go.LevelType, message string) []byte {
+	return Encode(t, level, message, nil)
+}
+
+// Encode return a record of time, level, msg and fields as a MessagePack map
+// framed by a length prefix. Keys of fields clashing with standard keys are
+// namespaced the same as json lines. Times are encoded as RFC3339 strings,
+// values of unsupported types are encoded as strings formatted by fmt
+func Encode(t time.Time, level blog4go.LevelType, message string, fields map[string]interface{}) []byte {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := make([]byte, PrefixSize, 64+len(message))
+	buf = appendMapHeader(buf, len(keys)+3)
+	buf = appendString(appendString(buf, blog4go.JSONTimeKey), t.Format(time.RFC3339Nano))
+	buf = appendString(appendString(buf, blog4go.JSONLevelKey), level.String())
+	buf = appendString(appendString(buf, blog4go.JSONMessageKey), message)
+	for _, key := range keys {
+		value := fields[key]
+		switch key {
+		case blog4go.JSONTimeKey, blog4go.JSONLevelKey, blog4go.JSONMessageKey:
+			key = blog4go.JSONReservedPrefix + key
+		}
+		buf = appendValue(appendString(buf, key), value)
+	}
+
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-PrefixSize))
+	return buf
+}
+
+func appendMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return append(buf, 0xde, byte(n>>8), byte(n))
+	}
+	return append(buf, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func appendArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(buf, 0xdc, byte(n>>8), byte(n))
+	}
+	return append(buf, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func appendString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xda, byte(n>>8), byte(n))
+	default:
+		buf = append(buf, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(buf, s...)
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = append(buf, 0xc5, byte(n>>8), byte(n))
+	default:
+		buf = append(buf, 0xc6, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(buf, b...)
+}
+
+func appendInt(buf []byte, v int64) []byte {
+	if -32 <= v && v < 128 {
+		return append(buf, byte(v))
+	}
+	buf = append(buf, 0xd3, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], uint64(v))
+	return buf
+}
+
+func appendUint(buf []byte, v uint64) []byte {
+	if v < 128 {
+		return append(buf, byte(v))
+	}
+	buf = append(buf, 0xcf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], v)
+	return buf
+}
+
+func appendFloat(buf []byte, v float64) []byte {
+	buf = append(buf, 0xcb, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(buf[len(buf)-8:], math.Float64bits(v))
+	return buf
+}
+
+func appendValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 0xc0)
+	case bool:
+		if v {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case int:
+		return appendInt(buf, int64(v))
+	case int8:
+		return appendInt(buf, int64(v))
+	case int16:
+		return appendInt(buf, int64(v))
+	case int32:
+		return appendInt(buf, int64(v))
+	case int64:
+		return appendInt(buf, v)
+	case uint:
+		return appendUint(buf, uint64(v))
+	case uint8:
+		return appendUint(buf, uint64(v))
+	case uint16:
+		return appendUint(buf, uint64(v))
+	case uint32:
+		return appendUint(buf, uint64(v))
+	case uint64:
+		return appendUint(buf, v)
+	case float32:
+		return appendFloat(buf, float64(v))
+	case float64:
+		return appendFloat(buf, v)
+	case string:
+		return appendString(buf, v)
+	case []byte:
+		return appendBytes(buf, v)
+	case time.Time:
+		return appendString(buf, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return appendString(buf, v.String())
+	case error:
+		return appendString(buf, v.Error())
+	case []interface{}:
+		buf = appendArrayHeader(buf, len(v))
+		for _, e := range v {
+			buf = appendValue(buf, e)
+		}
+		return buf
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf = appendMapHeader(buf, len(keys))
+		for _, key := range keys {
+			buf = appendValue(appendString(buf, key), v[key])
+		}
+		return buf
+	}
+	return appendString(buf, fmt.Sprint(value))
+}
+
+// Decode decodes the first framed record of b, it return the record and
+// bytes following it. Integers are decoded as int64 or uint64, floats as
+// float64, binaries as []byte, arrays as []interface{} and maps as
+// map[string]interface{}
+func Decode(b []byte) (record map[string]interface{}, rest []byte, err error) {
+	if len(b) < PrefixSize {
+		return nil, b, ErrShortRecord
+	}
+	n := int(binary.BigEndian.Uint32(b))
+	if len(b)-PrefixSize < n {
+		return nil, b, ErrShortRecord
+	}
+
+	d := &decoder{b: b[PrefixSize : PrefixSize+n]}
+	value, err := d.value()
+	if nil != err {
+		return nil, b, err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok || 0 != len(d.b) {
+		return nil, b, ErrBadRecord
+	}
+	return record, b[PrefixSize+n:], nil
+}
+
+// decoder reads values from b
+type decoder struct {
+	b []byte
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if len(d.b) < n {
+		return nil, ErrShortRecord
+	}
+	p := d.b[:n]
+	d.b = d.b[n:]
+	return p, nil
+}
+
+// size reads a big endian length of n bytes
+func (d *decoder) size(n int) (int, error) {
+	p, err := d.next(n)
+	if nil != err {
+		return 0, err
+	}
+
+	var size int
+	for _, c := range p {
+		size = size<<8 | int(c)
+	}
+	return size, nil
+}
+
+func (d *decoder) value() (interface{}, error) {
+	p, err := d.next(1)
+	if nil != err {
+		return nil, err
+	}
+
+	c := p[0]
+	switch {
+	case c < 0x80:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.mapOf(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.arrayOf(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.size(1 << (c - 0xc4))
+		if nil != err {
+			return nil, err
+		}
+		b, err := d.next(n)
+		return append([]byte(nil), b...), err
+	case 0xca:
+		b, err := d.next(4)
+		if nil != err {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		b, err := d.next(8)
+		if nil != err {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.next(1 << (c - 0xcc))
+		if nil != err {
+			return nil, err
+		}
+		var v uint64
+		for _, x := range b {
+			v = v<<8 | uint64(x)
+		}
+		return v, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		b, err := d.next(n)
+		if nil != err {
+			return nil, err
+		}
+		var v uint64
+		for _, x := range b {
+			v = v<<8 | uint64(x)
+		}
+		// 符号扩展
+		shift := uint(64 - 8*n)
+		return int64(v<<shift) >> shift, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.size(1 << (c - 0xd9))
+		if nil != err {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.size(2 << (c - 0xdc))
+		if nil != err {
+			return nil, err
+		}
+		return d.arrayOf(n)
+	case 0xde, 0xdf:
+		n, err := d.size(2 << (c - 0xde))
+		if nil != err {
+			return nil, err
+		}
+		return d.mapOf(n)
+	}
+	return nil, ErrBadRecord
+}
+
+func (d *decoder) str(n int) (interface{}, error) {
+	b, err := d.next(n)
+	if nil != err {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) arrayOf(n int) (interface{}, error) {
+	array := make([]interface{}, n)
+	for i := range array {
+		v, err := d.value()
+		if nil != err {
+			return nil, err
+		}
+		array[i] = v
+	}
+	return array, nil
+}
+
+func (d *decoder) mapOf(n int) (interface{}, error) {
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if nil != err {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, ErrBadRecord
+		}
+
+		if m[key], err = d.value(); nil != err {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package msgpack
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	ts := time.Date(2016, 7, 17, 8, 5, 2, 123000000, time.UTC)
+	fields := map[string]interface{}{
+		"user":   "eddie",
+		"id":     42,
+		"small":  -3,
+		"big":    int64(-1) << 40,
+		"count":  uint64(1) << 63,
+		"ratio":  0.5,
+		"ok":     true,
+		"none":   nil,
+		"raw":    []byte{0xde, 0xad},
+		"tags":   []interface{}{"a", 1},
+		"nested": map[string]interface{}{"k": "v"},
+		"long":   strings.Repeat("x", 300),
+		"msg":    "clash",
+	}
+
+	frame := Encode(ts, blog4go.WARNING, "hello", fields)
+	record, rest, err := Decode(append(frame, 0x01))
+	if nil != err {
+		t.Fatalf("decode failed. err: %s", err.Error())
+	}
+	if !bytes.Equal([]byte{0x01}, rest) {
+		t.Errorf("bytes following the record wrong. rest: %v", rest)
+	}
+
+	expected := map[string]interface{}{
+		"time":       "2016-07-17T08:05:02.123Z",
+		"level":      "WARN",
+		"msg":        "hello",
+		"user":       "eddie",
+		"id":         int64(42),
+		"small":      int64(-3),
+		"big":        int64(-1) << 40,
+		"count":      uint64(1) << 63,
+		"ratio":      0.5,
+		"ok":         true,
+		"none":       nil,
+		"raw":        []byte{0xde, 0xad},
+		"tags":       []interface{}{"a", int64(1)},
+		"nested":     map[string]interface{}{"k": "v"},
+		"long":       strings.Repeat("x", 300),
+		"fields.msg": "clash",
+	}
+	if !reflect.DeepEqual(expected, record) {
+		t.Errorf("record decoded wrong. record: %v", record)
+	}
+
+	if _, _, err = Decode(frame[:len(frame)-1]); ErrShortRecord != err {
+		t.Errorf("truncated record should be refused. err: %v", err)
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	ts := time.Date(2016, 7, 17, 8, 5, 2, 0, time.UTC)
+	stream := append(Formatter(ts, blog4go.INFO, "first"), Formatter(ts, blog4go.ERROR, "second")...)
+
+	var messages []string
+	for 0 != len(stream) {
+		record, rest, err := Decode(stream)
+		if nil != err {
+			t.Fatalf("decode failed. err: %s", err.Error())
+		}
+		messages = append(messages, record["level"].(string)+" "+record["msg"].(string))
+		stream = rest
+	}
+
+	if !reflect.DeepEqual([]string{"INFO first", "ERROR second"}, messages) {
+		t.Errorf("framed records wrong. messages: %v", messages)
+	}
+}
@@ -5,6 +5,9 @@ package blog4go
 import (
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"time"
 )
 
 var (
@@ -18,6 +21,7 @@ var (
 
 // MultiWriter struct defines an instance for multi writers with different message level
 type MultiWriter struct {
+	name  string
 	level LevelType
 
 	// file writers
@@ -40,6 +44,30 @@ type MultiWriter struct {
 	retentions  int64
 	rotateSize  int64
 	rotateLines int
+
+	maxTotalSize int64
+
+	fileMode os.FileMode
+
+	atomic bool
+
+	multiline bool
+
+	flushLevel LevelType
+
+	// placeholder and escape characters used in formatting, and character
+	// ending every line
+	placeholder byte
+	escape      byte
+	eol         byte
+
+	strict bool
+
+	// formats messages for log hook with the parser of BLog
+	format *BLog
+
+	// separators of structured fields, nil means default ones
+	sep *fieldSeparator
 }
 
 // TimeRotated get timeRotated
@@ -72,6 +100,91 @@ func (writer *MultiWriter) SetRetentions(retentions int64) {
 	}
 }
 
+// Rotate force a logrotate for every writers, writers sharing the same
+// file are rotated only once. first error met will be returned
+func (writer *MultiWriter) Rotate() (err error) {
+	rotated := make(map[string]bool)
+	for _, fileWriter := range writer.writers {
+		if w, ok := fileWriter.(*baseFileWriter); ok {
+			if rotated[w.fileName] {
+				continue
+			}
+			rotated[w.fileName] = true
+		}
+
+		if e := fileWriter.Rotate(); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
+// MaxTotalSize get max total size of all archives
+func (writer *MultiWriter) MaxTotalSize() int64 {
+	return writer.maxTotalSize
+}
+
+// SetMaxTotalSize set max total size of all archives after logrotate
+func (writer *MultiWriter) SetMaxTotalSize(maxTotalSize int64) {
+	if maxTotalSize < 0 {
+		return
+	}
+
+	writer.maxTotalSize = maxTotalSize
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetMaxTotalSize(maxTotalSize)
+	}
+}
+
+// SetRotateHook set a user defined logrotate policy for every file writer
+func (writer *MultiWriter) SetRotateHook(hook RotateHook) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetRotateHook(hook)
+	}
+}
+
+// FileMode get permission of log files
+func (writer *MultiWriter) FileMode() os.FileMode {
+	return writer.fileMode
+}
+
+// SetFileMode set permission of log files for every file writer
+func (writer *MultiWriter) SetFileMode(mode os.FileMode) (err error) {
+	writer.fileMode = mode.Perm()
+	for _, fileWriter := range writer.writers {
+		if e := fileWriter.SetFileMode(mode); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
+// SetOpenBanner toggle writing a banner line whenever a log file is opened,
+// writers sharing the same file write it once
+func (writer *MultiWriter) SetOpenBanner(banner bool) {
+	files := make(map[*logFile]bool)
+	for _, fileWriter := range writer.writers {
+		if w, ok := fileWriter.(*baseFileWriter); ok {
+			if files[w.file] {
+				continue
+			}
+			files[w.file] = true
+		}
+
+		fileWriter.SetOpenBanner(banner)
+	}
+}
+
+// SetFileOwner set owner of log files for every file writer
+func (writer *MultiWriter) SetFileOwner(uid, gid int) (err error) {
+	for _, fileWriter := range writer.writers {
+		if e := fileWriter.SetFileOwner(uid, gid); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
 // RotateSize get rotateSize
 func (writer *MultiWriter) RotateSize() int64 {
 	return writer.rotateSize
@@ -111,6 +224,379 @@ func (writer *MultiWriter) SetColored(colored bool) {
 	}
 }
 
+// AtomicWrite get whether every line is written with a single Write call
+func (writer *MultiWriter) AtomicWrite() bool {
+	return writer.atomic
+}
+
+// SetAtomicWrite toggle writing every line with a single Write call
+func (writer *MultiWriter) SetAtomicWrite(atomic bool) {
+	writer.atomic = atomic
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetAtomicWrite(atomic)
+	}
+}
+
+// SetReorderWindow hold lines for d and write them sorted by time for every writer
+func (writer *MultiWriter) SetReorderWindow(d time.Duration) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetReorderWindow(d)
+	}
+}
+
+// SetDevMode toggle rendering json lines in human readable format on
+// terminal for every writer
+func (writer *MultiWriter) SetDevMode(dev bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetDevMode(dev)
+	}
+}
+
+// MultilinePrefix get whether every line of a multi-line message is prefixed
+func (writer *MultiWriter) MultilinePrefix() bool {
+	return writer.multiline
+}
+
+// SetMultilinePrefix toggle prefixing every line of a multi-line message
+func (writer *MultiWriter) SetMultilinePrefix(multiline bool) {
+	writer.multiline = multiline
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetMultilinePrefix(multiline)
+	}
+}
+
+// StrictFormat get whether it is in strict format mode
+func (writer *MultiWriter) StrictFormat() bool {
+	return writer.strict
+}
+
+// SetStrictFormat toggle strict format mode
+func (writer *MultiWriter) SetStrictFormat(strict bool) {
+	writer.format.SetStrictFormat(strict)
+	writer.strict = strict
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetStrictFormat(strict)
+	}
+}
+
+// SetErrorHandler set handler called when writers meet an error
+func (writer *MultiWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.format.SetErrorHandler(handler)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetErrorHandler(handler)
+	}
+}
+
+// SetWriteTimeout bound every write of every writer
+func (writer *MultiWriter) SetWriteTimeout(d time.Duration) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetWriteTimeout(d)
+	}
+}
+
+// Placeholder get placeholder character used in formatting
+func (writer *MultiWriter) Placeholder() byte {
+	return writer.placeholder
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func (writer *MultiWriter) SetPlaceholder(placeholder byte) {
+	if writer.escape == placeholder || writer.eol == placeholder {
+		return
+	}
+
+	writer.placeholder = placeholder
+	writer.format.SetPlaceholder(placeholder)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPlaceholder(placeholder)
+	}
+}
+
+// EOL get character ending every line
+func (writer *MultiWriter) EOL() byte {
+	return writer.eol
+}
+
+// SetEOL set character ending every line for every writer
+func (writer *MultiWriter) SetEOL(eol byte) {
+	if writer.placeholder == eol || writer.escape == eol {
+		return
+	}
+
+	writer.eol = eol
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetEOL(eol)
+	}
+}
+
+// Escape get escape character used in formatting
+func (writer *MultiWriter) Escape() byte {
+	return writer.escape
+}
+
+// SetEscape set escape character used in formatting for every writer
+func (writer *MultiWriter) SetEscape(escape byte) {
+	if writer.placeholder == escape || writer.eol == escape {
+		return
+	}
+
+	writer.escape = escape
+	writer.format.SetEscape(escape)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetEscape(escape)
+	}
+}
+
+// SetTimeFormat set layout of time prefix for every writer
+func (writer *MultiWriter) SetTimeFormat(layout string) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetTimeFormat(layout)
+	}
+}
+
+// SetTimeFormatPreset set time prefix as a preset format for every writer
+func (writer *MultiWriter) SetTimeFormatPreset(preset TimePreset) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetTimeFormatPreset(preset)
+	}
+}
+
+// SetFraming set how every line is framed for every writer
+func (writer *MultiWriter) SetFraming(framing Framing) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetFraming(framing)
+	}
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly for every writer
+func (writer *MultiWriter) SetSmartTimeVerb(smart bool) {
+	writer.format.SetSmartTimeVerb(smart)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetSmartTimeVerb(smart)
+	}
+}
+
+// SetFormatter set formatter producing whole lines for every writer
+func (writer *MultiWriter) SetFormatter(formatter Formatter) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetFormatter(formatter)
+	}
+}
+
+// SetNilString set the token nil args are written as in formatting
+func (writer *MultiWriter) SetNilString(s string) {
+	writer.format.SetNilString(s)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetNilString(s)
+	}
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with
+// recover for every writer
+func (writer *MultiWriter) SetSafeStringer(safe bool) {
+	writer.format.SetSafeStringer(safe)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetSafeStringer(safe)
+	}
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args for every
+// writer
+func (writer *MultiWriter) SetRawStringer(raw bool) {
+	writer.format.SetRawStringer(raw)
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetRawStringer(raw)
+	}
+}
+
+// SetDebounce logs messages with format at most once per window for every
+// writer
+func (writer *MultiWriter) SetDebounce(format string, window time.Duration) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetDebounce(format, window)
+	}
+}
+
+// SetSamplerPolicy samples messages for every writer
+func (writer *MultiWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetSamplerPolicy(first, thereafter, tick)
+	}
+}
+
+// SetPrintSequence toggle writing sequence numbers for every writer, every
+// writer counts on its own
+func (writer *MultiWriter) SetPrintSequence(sequence bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintSequence(sequence)
+	}
+}
+
+// SetPrintElapsed toggle writing milliseconds since start for every writer
+func (writer *MultiWriter) SetPrintElapsed(elapsed bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintElapsed(elapsed)
+	}
+}
+
+// SetSequenceWidth set width sequence numbers are zero padded to for every
+// writer
+func (writer *MultiWriter) SetSequenceWidth(width int) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetSequenceWidth(width)
+	}
+}
+
+// SetDefaultFields sets static fields prepended to every message for every
+// writer
+func (writer *MultiWriter) SetDefaultFields(fields map[string]string) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetDefaultFields(fields)
+	}
+}
+
+// AddDefaultField appends a static field prepended to every message for
+// every writer
+func (writer *MultiWriter) AddDefaultField(key, value string) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.AddDefaultField(key, value)
+	}
+}
+
+// separators return separators in use
+func (writer *MultiWriter) separators() *fieldSeparator {
+	if nil == writer.sep {
+		return defaultSeparator
+	}
+	return writer.sep
+}
+
+// setSeparators set separators for every writer, the first error is returned
+func (writer *MultiWriter) setSeparators(sep *fieldSeparator) (err error) {
+	writer.sep = sep
+	for _, fileWriter := range writer.writers {
+		s, ok := fileWriter.(separated)
+		if !ok {
+			continue
+		}
+		if e := s.SetFieldSeparator(sep.field); nil != e && nil == err {
+			err = e
+		}
+		if e := s.SetKVSeparator(sep.kv); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
+// SetFieldSeparator set what is written between structured fields for every
+// writer
+func (writer *MultiWriter) SetFieldSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	return writer.setSeparators(&fieldSeparator{field: sep, kv: writer.separators().kv})
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields for every writer
+func (writer *MultiWriter) SetKVSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	return writer.setSeparators(&fieldSeparator{field: writer.separators().field, kv: sep})
+}
+
+// SetPrintHostname toggle writing hostname ahead every message for every
+// writer
+func (writer *MultiWriter) SetPrintHostname(print bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintHostname(print)
+	}
+}
+
+// SetHostname overrides hostname written ahead messages for every writer
+func (writer *MultiWriter) SetHostname(name string) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetHostname(name)
+	}
+}
+
+// SetPrintPID toggle writing process id ahead every message for every writer
+func (writer *MultiWriter) SetPrintPID(print bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintPID(print)
+	}
+}
+
+// SetPrintProgram toggle writing program name ahead every message for every
+// writer
+func (writer *MultiWriter) SetPrintProgram(print bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintProgram(print)
+	}
+}
+
+// SetPrintCallerFunc toggle writing the calling function for every writer
+func (writer *MultiWriter) SetPrintCallerFunc(print bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintCallerFunc(print)
+	}
+}
+
+// SetPrintCallerLine toggle writing file:line of the caller for every writer
+func (writer *MultiWriter) SetPrintCallerLine(print bool) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetPrintCallerLine(print)
+	}
+}
+
+// AddDropSubstring drops messages containing s for every writer
+func (writer *MultiWriter) AddDropSubstring(s string) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.AddDropSubstring(s)
+	}
+}
+
+// AddDropRegexp drops messages matching re for every writer
+func (writer *MultiWriter) AddDropRegexp(re *regexp.Regexp) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.AddDropRegexp(re)
+	}
+}
+
+// Dropped return number of messages dropped by every writer
+func (writer *MultiWriter) Dropped() (dropped int64) {
+	for _, fileWriter := range writer.writers {
+		dropped += fileWriter.Dropped()
+	}
+	return
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately
+func (writer *MultiWriter) FlushLevel() LevelType {
+	return writer.flushLevel
+}
+
+// SetFlushLevel set the level at or above which messages are flushed immediately
+func (writer *MultiWriter) SetFlushLevel(level LevelType) {
+	if !level.valid() {
+		level = noFlushLevel
+	}
+
+	writer.flushLevel = level
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetFlushLevel(level)
+	}
+}
+
+// SetBufferSize resize buffer keeping bytes buffered for every writer
+func (writer *MultiWriter) SetBufferSize(size int) {
+	for _, fileWriter := range writer.writers {
+		fileWriter.SetBufferSize(size)
+	}
+}
+
 // SetHook set hook for every logging actions
 func (writer *MultiWriter) SetHook(hook Hook) {
 	writer.hook = hook
@@ -127,11 +613,12 @@ func (writer *MultiWriter) SetHookLevel(level LevelType) {
 }
 
 // SetLevel set logging level threshold
-func (writer *MultiWriter) SetLevel(level LevelType) {
+func (writer *MultiWriter) SetLevel(level LevelType) Writer {
 	writer.level = level
 	for _, fileWriter := range writer.writers {
 		fileWriter.SetLevel(level)
 	}
+	return writer
 }
 
 // Level return logging level threshold
@@ -139,6 +626,16 @@ func (writer *MultiWriter) Level() LevelType {
 	return writer.level
 }
 
+// Name get name of the writer, "multi" by default
+func (writer *MultiWriter) Name() string {
+	return writer.name
+}
+
+// SetName set name of the writer
+func (writer *MultiWriter) SetName(name string) {
+	writer.name = name
+}
+
 // Close close file writer
 func (writer *MultiWriter) Close() {
 	for _, fileWriter := range writer.writers {
@@ -151,14 +648,7 @@ func (writer *MultiWriter) write(level LevelType, args ...interface{}) {
 	defer func() {
 		// 异步调用log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, args ...interface{}) {
-					writer.hook.Fire(level, args...)
-				}(level, args...)
-
-			} else {
-				writer.hook.Fire(level, args...)
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, fmt.Sprint(args...))
 		}
 	}()
 
@@ -169,15 +659,7 @@ func (writer *MultiWriter) writef(level LevelType, format string, args ...interf
 	defer func() {
 		// 异步调用log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, format string, args ...interface{}) {
-					writer.hook.Fire(level, fmt.Sprintf(format, args...))
-				}(level, format, args...)
-
-			} else {
-				writer.hook.Fire(level, fmt.Sprintf(format, args...))
-
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, writer.format.sprintf(format, args...))
 		}
 	}()
 
@@ -191,8 +673,32 @@ func (writer *MultiWriter) flush() {
 	}
 }
 
+// Drain drains every writer, the first error is returned
+func (writer *MultiWriter) Drain() (err error) {
+	for _, w := range writer.writers {
+		if e := w.Drain(); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
+// Ping pings every writer, the first error is returned
+func (writer *MultiWriter) Ping() (err error) {
+	for _, w := range writer.writers {
+		if e := w.Ping(); nil != e && nil == err {
+			err = e
+		}
+	}
+	return
+}
+
 // Trace trace
 func (writer *MultiWriter) Trace(args ...interface{}) {
+	if TRACE < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[TRACE]
 	if !ok || TRACE < writer.level {
 		return
@@ -203,6 +709,10 @@ func (writer *MultiWriter) Trace(args ...interface{}) {
 
 // Tracef tracef
 func (writer *MultiWriter) Tracef(format string, args ...interface{}) {
+	if TRACE < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[TRACE]
 	if !ok || TRACE < writer.level {
 		return
@@ -213,6 +723,10 @@ func (writer *MultiWriter) Tracef(format string, args ...interface{}) {
 
 // Debug debug
 func (writer *MultiWriter) Debug(args ...interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[DEBUG]
 	if !ok || DEBUG < writer.level {
 		return
@@ -223,6 +737,10 @@ func (writer *MultiWriter) Debug(args ...interface{}) {
 
 // Debugf debugf
 func (writer *MultiWriter) Debugf(format string, args ...interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[DEBUG]
 	if !ok || DEBUG < writer.level {
 		return
@@ -231,8 +749,61 @@ func (writer *MultiWriter) Debugf(format string, args ...interface{}) {
 	writer.writef(DEBUG, format, args...)
 }
 
+// DebugPretty debug pretty
+func (writer *MultiWriter) DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[DEBUG]
+	if !ok || DEBUG < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(DEBUG < writer.hookLevel) {
+			message := prettyFormat(v)
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), DEBUG, message)
+		}
+	}()
+
+	writer.writers[DEBUG].DebugPretty(v)
+}
+
+// ErrorStack writes message with stack trace of the caller to writer of
+// error level
+func (writer *MultiWriter) ErrorStack(message string) {
+	if ERROR < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[ERROR]
+	if !ok || ERROR < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(ERROR < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), ERROR, message)
+		}
+	}()
+
+	writer.writers[ERROR].ErrorStack(message)
+}
+
+// Entry starts building a structured message with specific level
+func (writer *MultiWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
 // Info info
 func (writer *MultiWriter) Info(args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[INFO]
 	if !ok || INFO < writer.level {
 		return
@@ -241,8 +812,121 @@ func (writer *MultiWriter) Info(args ...interface{}) {
 	writer.write(INFO, args...)
 }
 
+// InfoJSON writes fields as a json line with info level
+func (writer *MultiWriter) InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[INFO]
+	if !ok || INFO < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, jsonFields(fields))
+		}
+	}()
+
+	writer.writers[INFO].InfoJSON(fields)
+}
+
+// WriteBatch writes messages contiguously to the writer of level
+func (writer *MultiWriter) WriteBatch(level LevelType, messages []string) int {
+	if level < CompileLevel {
+		return 0
+	}
+
+	_, ok := writer.writers[level]
+	if !ok || level < writer.level {
+		return 0
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			for _, message := range messages {
+				fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+			}
+		}
+	}()
+
+	return writer.writers[level].WriteBatch(level, messages)
+}
+
+// LogAt writes message prefixed with time t instead of now to the writer of
+// level
+func (writer *MultiWriter) LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[level]
+	if !ok || level < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, message)
+		}
+	}()
+
+	writer.writers[level].LogAt(t, level, message)
+}
+
+// LogfAt formats message prefixed with time t instead of now to the writer of
+// level
+func (writer *MultiWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	if level < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[level]
+	if !ok || level < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, writer.format.sprintf(format, args...))
+		}
+	}()
+
+	writer.writers[level].LogfAt(t, level, format, args...)
+}
+
+// Raw writes message verbatim to the writer of RawLevel
+func (writer *MultiWriter) Raw(message string) {
+	if RawLevel < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[RawLevel]
+	if !ok || RawLevel < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(RawLevel < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), RawLevel, message)
+		}
+	}()
+
+	writer.writers[RawLevel].Raw(message)
+}
+
 // Infof infof
 func (writer *MultiWriter) Infof(format string, args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[INFO]
 	if !ok || INFO < writer.level {
 		return
@@ -251,8 +935,54 @@ func (writer *MultiWriter) Infof(format string, args ...interface{}) {
 	writer.writef(INFO, format, args...)
 }
 
+// InfoSync writes message with info level and flushes it immediately
+func (writer *MultiWriter) InfoSync(message string) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[INFO]
+	if !ok || INFO < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, message)
+		}
+	}()
+
+	writer.writers[INFO].InfoSync(message)
+}
+
+// InfofSync formats message with info level and flushes it immediately
+func (writer *MultiWriter) InfofSync(format string, args ...interface{}) {
+	if INFO < CompileLevel {
+		return
+	}
+
+	_, ok := writer.writers[INFO]
+	if !ok || INFO < writer.level {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, writer.format.sprintf(format, args...))
+		}
+	}()
+
+	writer.writers[INFO].InfofSync(format, args...)
+}
+
 // Warn warn
 func (writer *MultiWriter) Warn(args ...interface{}) {
+	if WARNING < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[WARNING]
 	if !ok || WARNING < writer.level {
 		return
@@ -263,6 +993,10 @@ func (writer *MultiWriter) Warn(args ...interface{}) {
 
 // Warnf warnf
 func (writer *MultiWriter) Warnf(format string, args ...interface{}) {
+	if WARNING < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[WARNING]
 	if !ok || WARNING < writer.level {
 		return
@@ -273,6 +1007,10 @@ func (writer *MultiWriter) Warnf(format string, args ...interface{}) {
 
 // Error error
 func (writer *MultiWriter) Error(args ...interface{}) {
+	if ERROR < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[ERROR]
 	if !ok || ERROR < writer.level {
 		return
@@ -283,6 +1021,10 @@ func (writer *MultiWriter) Error(args ...interface{}) {
 
 // Errorf error
 func (writer *MultiWriter) Errorf(format string, args ...interface{}) {
+	if ERROR < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[ERROR]
 	if !ok || ERROR < writer.level {
 		return
@@ -293,6 +1035,10 @@ func (writer *MultiWriter) Errorf(format string, args ...interface{}) {
 
 // Critical critical
 func (writer *MultiWriter) Critical(args ...interface{}) {
+	if CRITICAL < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[CRITICAL]
 	if !ok || CRITICAL < writer.level {
 		return
@@ -303,6 +1049,10 @@ func (writer *MultiWriter) Critical(args ...interface{}) {
 
 // Criticalf criticalf
 func (writer *MultiWriter) Criticalf(format string, args ...interface{}) {
+	if CRITICAL < CompileLevel {
+		return
+	}
+
 	_, ok := writer.writers[CRITICAL]
 	if !ok || CRITICAL < writer.level {
 		return
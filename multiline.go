@@ -0,0 +1,115 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+)
+
+// multilineWriter writes the message of a line in multiline prefix mode,
+// every eol inside the message is followed by time and level prefix, so that
+// every physical line of the message carries its own prefix
+type multilineWriter struct {
+	writer lineWriter
+	eol    byte
+	frame  []byte
+	ts     []byte
+	prefix []byte
+
+	// bytes of prefixes written
+	extra int
+}
+
+// reset prepares for a new message
+func (m *multilineWriter) reset(writer lineWriter, eol byte, frame []byte, level LevelType, ts []byte) {
+	m.writer = writer
+	m.eol = eol
+	m.frame = frame
+	m.ts = ts
+	m.prefix = level.prefixBytes()
+	m.extra = 0
+}
+
+// newline writes frame, time and level prefix of a new physical line
+func (m *multilineWriter) newline() {
+	m.writer.Write(m.frame)
+	m.writer.Write(m.ts)
+	m.writer.Write(m.prefix)
+	m.extra += len(m.frame) + len(m.ts) + len(m.prefix)
+}
+
+func (m *multilineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for i := bytes.IndexByte(p, m.eol); i >= 0; i = bytes.IndexByte(p, m.eol) {
+		m.writer.Write(p[:i+1])
+		m.newline()
+		p = p[i+1:]
+	}
+	m.writer.Write(p)
+	return n, nil
+}
+
+func (m *multilineWriter) WriteString(s string) (int, error) {
+	n := len(s)
+	for i := strings.IndexByte(s, m.eol); i >= 0; i = strings.IndexByte(s, m.eol) {
+		m.writer.WriteString(s[:i+1])
+		m.newline()
+		s = s[i+1:]
+	}
+	m.writer.WriteString(s)
+	return n, nil
+}
+
+func (m *multilineWriter) WriteByte(c byte) error {
+	m.writer.WriteByte(c)
+	if m.eol == c {
+		m.newline()
+	}
+	return nil
+}
+
+// body returns where message of a line should be written into
+func (blog *BLog) body(w lineWriter, level LevelType, ts []byte) lineWriter {
+	blog.elapsedLen = blog.writeElapsed(w)
+	blog.seqLen = blog.writeSequence(w)
+	if "" != blog.defaults.prefix {
+		w.WriteString(blog.defaults.prefix)
+	}
+	blog.callerLen = blog.writeCaller(w)
+
+	// drop rules match message only
+	blog.mark = blog.line.Len()
+
+	if !blog.multiline {
+		return w
+	}
+
+	blog.multi.reset(w, blog.eol, blog.frame(level), level, ts)
+	return blog.multi
+}
+
+// extra return bytes of sequence number, default fields and prefixes written
+// inside the last message
+func (blog *BLog) extra() int {
+	if !blog.multiline {
+		return blog.elapsedLen + blog.seqLen + len(blog.defaults.prefix) + blog.callerLen
+	}
+	return blog.elapsedLen + blog.seqLen + len(blog.defaults.prefix) + blog.callerLen + blog.multi.extra
+}
+
+// MultilinePrefix get whether every line of a multi-line message is prefixed
+func (blog *BLog) MultilinePrefix() bool {
+	return blog.multiline
+}
+
+// SetMultilinePrefix toggle multiline prefix mode. In multiline prefix mode
+// every line of a message containing EOL, such as stack traces or sql, is
+// written with its own time and level prefix, so that log parsers handle them
+// consistently. Otherwise lines following the first one have no prefix
+func (blog *BLog) SetMultilinePrefix(multiline bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.multiline = multiline
+	return blog
+}
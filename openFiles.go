@@ -0,0 +1,216 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"container/list"
+	"os"
+	"sync"
+)
+
+var (
+	// maxOpenFiles bounds log files kept open, 0 means no limit
+	maxOpenFiles int
+
+	// openFiles holds log files opened, most recently written at front
+	openFiles = list.New()
+
+	// openFilesLock protects maxOpenFiles && openFiles
+	openFilesLock = new(sync.Mutex)
+)
+
+// logFile is a log file which may be closed when too many files are open,
+// and is opened again with O_APPEND on next use
+type logFile struct {
+	name string
+	mode os.FileMode
+
+	// nil when closed by open files limit
+	file *os.File
+	// element in openFiles, nil when not in it
+	elem *list.Element
+
+	closed bool
+	lock   *sync.Mutex
+}
+
+// newLogFile opens fileName as a logFile
+func newLogFile(fileName string, mode os.FileMode) (*logFile, error) {
+	file, err := openLogFile(fileName, mode)
+	if nil != err {
+		return nil, err
+	}
+
+	f := &logFile{name: fileName, mode: mode, file: file, lock: new(sync.Mutex)}
+	f.touch(true)
+	return f, nil
+}
+
+// SetMaxOpenFiles set how many log files can be open at the same time, the
+// least recently written ones are closed beyond it, and opened again with
+// O_APPEND when bytes buffered are flushed to them. It bounds fds of daily
+// files and level files on long running processes. 0 means no limit,
+// default 0
+func SetMaxOpenFiles(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	openFilesLock.Lock()
+	maxOpenFiles = n
+	victims := evictions(nil)
+	openFilesLock.Unlock()
+
+	for _, victim := range victims {
+		victim.evict()
+	}
+}
+
+// MaxOpenFiles get how many log files can be open at the same time
+func MaxOpenFiles() int {
+	openFilesLock.Lock()
+	defer openFilesLock.Unlock()
+	return maxOpenFiles
+}
+
+// OpenFiles get how many log files are open now
+func OpenFiles() int {
+	openFilesLock.Lock()
+	defer openFilesLock.Unlock()
+	return openFiles.Len()
+}
+
+// evictions removes the least recently written files beyond maxOpenFiles
+// except keep from openFiles, and returns them to be closed.
+// openFilesLock must be held by the caller
+func evictions(keep *logFile) (victims []*logFile) {
+	if maxOpenFiles <= 0 {
+		return
+	}
+
+	for e := openFiles.Back(); nil != e && openFiles.Len() > maxOpenFiles; {
+		prev := e.Prev()
+		if f := e.Value.(*logFile); f != keep {
+			openFiles.Remove(e)
+			f.elem = nil
+			victims = append(victims, f)
+		}
+		e = prev
+	}
+	return
+}
+
+// touch marks f most recently used, and closes files beyond limit.
+// f.lock must NOT be held, locks of files are always taken before
+// openFilesLock
+func (f *logFile) touch(opened bool) {
+	openFilesLock.Lock()
+	if nil != f.elem {
+		openFiles.MoveToFront(f.elem)
+	} else if opened {
+		f.elem = openFiles.PushFront(f)
+	}
+	// f is removed but not closed yet otherwise, it is closed soon
+	victims := evictions(f)
+	openFilesLock.Unlock()
+
+	for _, victim := range victims {
+		victim.evict()
+	}
+}
+
+// evict closes f removed from openFiles, unless it is used again meanwhile
+func (f *logFile) evict() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	openFilesLock.Lock()
+	used := nil != f.elem
+	openFilesLock.Unlock()
+
+	if used || nil == f.file {
+		return
+	}
+
+	f.file.Close()
+	f.file = nil
+}
+
+// do runs fn with the file, opening it again if it is closed by limit
+func (f *logFile) do(fn func(file *os.File) error) (err error) {
+	var opened = false
+
+	f.lock.Lock()
+	if f.closed {
+		f.lock.Unlock()
+		return os.ErrClosed
+	}
+
+	if nil == f.file {
+		// O_APPEND, nothing written before is truncated
+		if f.file, err = openLogFile(f.name, f.mode); nil != err {
+			f.lock.Unlock()
+			return
+		}
+		opened = true
+	}
+	err = fn(f.file)
+	f.lock.Unlock()
+
+	f.touch(opened)
+	return
+}
+
+// Write writes bytes flushed by BLog to the file
+func (f *logFile) Write(p []byte) (n int, err error) {
+	err = f.do(func(file *os.File) (e error) {
+		n, e = file.Write(p)
+		return
+	})
+	return
+}
+
+// Sync commits the file to disk
+func (f *logFile) Sync() error {
+	return f.do(func(file *os.File) error {
+		return file.Sync()
+	})
+}
+
+// Chmod changes mode of the file
+func (f *logFile) Chmod(mode os.FileMode) error {
+	return f.do(func(file *os.File) error {
+		return file.Chmod(mode)
+	})
+}
+
+// Chown changes owner of the file
+func (f *logFile) Chown(uid, gid int) error {
+	return f.do(func(file *os.File) error {
+		return file.Chown(uid, gid)
+	})
+}
+
+// Close closes the file, it is never opened again
+func (f *logFile) Close() (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return
+	}
+	f.closed = true
+
+	openFilesLock.Lock()
+	if nil != f.elem {
+		openFiles.Remove(f.elem)
+		f.elem = nil
+	}
+	openFilesLock.Unlock()
+
+	if nil != f.file {
+		err = f.file.Close()
+		f.file = nil
+	}
+	return
+}
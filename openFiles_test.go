@@ -0,0 +1,83 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// openedLogs counts fds of the process opened on files with prefix
+func openedLogs(prefix string) (n int) {
+	fds, _ := ioutil.ReadDir("/proc/self/fd")
+	for _, fd := range fds {
+		if name, err := os.Readlink("/proc/self/fd/" + fd.Name()); nil == err && strings.HasPrefix(name, prefix) {
+			n++
+		}
+	}
+	return
+}
+
+func TestMaxOpenFiles(t *testing.T) {
+	SetMaxOpenFiles(2)
+	defer func() {
+		SetMaxOpenFiles(0)
+		exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+	}()
+
+	if 2 != MaxOpenFiles() {
+		t.Errorf("max open files not set. got: %d", MaxOpenFiles())
+	}
+
+	writers := make([]*baseFileWriter, 5)
+	for i := range writers {
+		writer, err := newBaseFileWriter(fmt.Sprintf("/tmp/fds%d.log", i), false)
+		if nil != err {
+			t.Fatalf("create file writer failed. err: %s", err.Error())
+		}
+		writers[i] = writer
+	}
+
+	for round := 0; round < 3; round++ {
+		for i, writer := range writers {
+			writer.Infof("round %d file %d", round, i)
+			writer.blog.flush()
+
+			if n := openedLogs("/tmp/fds"); n > 2 {
+				t.Errorf("fds not recycled. open: %d", n)
+			}
+		}
+	}
+
+	if n := OpenFiles(); n > 2 {
+		t.Errorf("open files over limit. got: %d", n)
+	}
+
+	for _, writer := range writers {
+		writer.Close()
+	}
+
+	if n := openedLogs("/tmp/fds"); 0 != n {
+		t.Errorf("fds left after close. open: %d", n)
+	}
+
+	for i := range writers {
+		data, err := ioutil.ReadFile(fmt.Sprintf("/tmp/fds%d.log", i))
+		if nil != err {
+			t.Fatalf("read log failed. err: %s", err.Error())
+		}
+
+		for round := 0; round < 3; round++ {
+			if line := fmt.Sprintf("round %d file %d", round, i); !strings.Contains(string(data), line) {
+				t.Errorf("line lost, reopening truncates. want: %s, got: %q", line, string(data))
+			}
+		}
+		if 3 != strings.Count(string(data), "\n") {
+			t.Errorf("lines of file %d wrong. got: %q", i, string(data))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package otel provides a blog4go writer emitting every message as an
+// OpenTelemetry log record. It lives in its own package so that only users
+// who need it depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+var (
+	// Severities maps blog4go levels to OpenTelemetry severity numbers,
+	// which can be replaced by blog4go.SetSeverityMapper
+	Severities = map[blog4go.LevelType]log.Severity{
+		blog4go.TRACE:    log.SeverityTrace,
+		blog4go.DEBUG:    log.SeverityDebug,
+		blog4go.INFO:     log.SeverityInfo,
+		blog4go.WARNING:  log.SeverityWarn,
+		blog4go.ERROR:    log.SeverityError,
+		blog4go.CRITICAL: log.SeverityFatal,
+	}
+)
+
+// sink emits messages to an OpenTelemetry logger
+type sink struct {
+	ctx    context.Context
+	logger log.Logger
+}
+
+// Emit emits message as a log record, timestamp of the record is the time
+// cached by blog4go when message is written
+func (s *sink) Emit(t time.Time, level blog4go.LevelType, message string) error {
+	var record log.Record
+	record.SetTimestamp(t)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(log.Severity(blog4go.Severity(level, int(Severities[level]))))
+	record.SetSeverityText(level.String())
+	record.SetBody(attribute.StringValue(message))
+
+	s.logger.Emit(s.ctx, record)
+	return nil
+}
+
+// Flush do nothing, records are exported by the logger provider
+func (s *sink) Flush() error {
+	return nil
+}
+
+// Close do nothing, the logger provider is owned by the caller
+func (s *sink) Close() error {
+	return nil
+}
+
+// NewOTelWriter creates a writer emitting every message to logger
+func NewOTelWriter(logger log.Logger) blog4go.Writer {
+	return NewOTelWriterWithContext(context.Background(), logger)
+}
+
+// NewOTelWriterWithContext creates a writer emitting every message to logger
+// with ctx, so that trace and span ids carried by ctx are set into records
+// by the logger provider. It is cheap enough to create one for each request.
+func NewOTelWriterWithContext(ctx context.Context, logger log.Logger) blog4go.Writer {
+	return blog4go.NewSinkWriter(&sink{ctx: ctx, logger: logger})
+}
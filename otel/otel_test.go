@@ -0,0 +1,100 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package otel
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/YoungPioneers/blog4go"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+type ctxKey struct{}
+
+// recorder is a logger keeps every record emitted
+type recorder struct {
+	embedded.Logger
+
+	records []log.Record
+	ctxs    []context.Context
+
+	l *sync.Mutex
+}
+
+func (r *recorder) Emit(ctx context.Context, record log.Record) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.records = append(r.records, record)
+	r.ctxs = append(r.ctxs, ctx)
+}
+
+func (r *recorder) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return true
+}
+
+func TestOTelWriter(t *testing.T) {
+	r := &recorder{l: new(sync.Mutex)}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "span")
+
+	writer := NewOTelWriterWithContext(ctx, r)
+	defer writer.Close()
+
+	writer.SetLevel(blog4go.INFO)
+	writer.Debug("dropped")
+	writer.Infof("hello %s", "eddie")
+	writer.Critical("boom")
+
+	if 2 != len(r.records) {
+		t.Fatalf("records count wrong. count: %d", len(r.records))
+	}
+
+	record := r.records[0]
+	if log.SeverityInfo != record.Severity() || "INFO" != record.SeverityText() {
+		t.Errorf("record severity wrong. severity: %d, text: %s", record.Severity(), record.SeverityText())
+	}
+	if "hello eddie" != record.Body().AsString() {
+		t.Errorf("record body wrong. body: %s", record.Body().AsString())
+	}
+	if record.Timestamp().IsZero() {
+		t.Error("record timestamp not set.")
+	}
+	if "span" != r.ctxs[0].Value(ctxKey{}) {
+		t.Error("record not emitted with given context.")
+	}
+
+	if log.SeverityFatal != r.records[1].Severity() {
+		t.Errorf("critical severity wrong. severity: %d", r.records[1].Severity())
+	}
+}
+
+func TestSeverityMapper(t *testing.T) {
+	r := &recorder{l: new(sync.Mutex)}
+	writer := NewOTelWriter(r)
+	defer writer.Close()
+
+	// trace treated as debug
+	blog4go.SetSeverityMapper(func(level blog4go.LevelType) int {
+		if blog4go.TRACE == level {
+			return int(log.SeverityDebug)
+		}
+		return int(Severities[level])
+	})
+	writer.SetLevel(blog4go.TRACE)
+	writer.Trace("mapped")
+
+	blog4go.SetSeverityMapper(nil)
+	writer.Trace("default")
+
+	if 2 != len(r.records) {
+		t.Fatalf("records count wrong. count: %d", len(r.records))
+	}
+	if log.SeverityDebug != r.records[0].Severity() {
+		t.Errorf("severity should be mapped by mapper. severity: %d", r.records[0].Severity())
+	}
+	if log.SeverityTrace != r.records[1].Severity() {
+		t.Errorf("default severity should be restored. severity: %d", r.records[1].Severity())
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+var (
+	// PipeBufferSize is how many bytes a pipe writer holds unread, lines
+	// beyond it are dropped instead of blocking the writer
+	PipeBufferSize = 1 << 20
+)
+
+// pipeBuffer is a goroutine-safe bounded buffer between a writer and a
+// reader. Read blocks until bytes arrive or the buffer is closed
+type pipeBuffer struct {
+	buf    bytes.Buffer
+	limit  int
+	closed bool
+
+	cond *sync.Cond
+}
+
+// newPipeBuffer create a pipeBuffer holding at most limit bytes
+func newPipeBuffer(limit int) *pipeBuffer {
+	return &pipeBuffer{limit: limit, cond: sync.NewCond(new(sync.Mutex))}
+}
+
+// Write keeps p for the reader, lines in p are dropped altogether if p
+// exceeds room left, the writer never waits for the reader
+func (pipe *pipeBuffer) Write(p []byte) (int, error) {
+	pipe.cond.L.Lock()
+	defer pipe.cond.L.Unlock()
+
+	if pipe.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if pipe.buf.Len()+len(p) > pipe.limit {
+		for i := bytes.Count(p, []byte{EOL}); i > 0; i-- {
+			CountDropped(DropQueueFull)
+		}
+		return len(p), nil
+	}
+
+	pipe.buf.Write(p)
+	pipe.cond.Broadcast()
+	return len(p), nil
+}
+
+// Read reads bytes written, io.EOF after the pipe is closed and drained
+func (pipe *pipeBuffer) Read(p []byte) (int, error) {
+	pipe.cond.L.Lock()
+	defer pipe.cond.L.Unlock()
+
+	for 0 == pipe.buf.Len() && !pipe.closed {
+		pipe.cond.Wait()
+	}
+
+	if 0 == pipe.buf.Len() {
+		return 0, io.EOF
+	}
+	return pipe.buf.Read(p)
+}
+
+// Close closes the write end, reader sees io.EOF after bytes left are read
+func (pipe *pipeBuffer) Close() error {
+	pipe.cond.L.Lock()
+	defer pipe.cond.L.Unlock()
+
+	pipe.closed = true
+	pipe.cond.Broadcast()
+	return nil
+}
+
+// PipeWriter is a writer whose output is read back in-process from its
+// reader, e.g. to forward it elsewhere
+type PipeWriter struct {
+	*ConsoleWriter
+
+	pipe *pipeBuffer
+}
+
+// NewPipeWriter create a writer and the read end of its output, not singlton.
+// At most PipeBufferSize bytes unread are held, so that a slow reader never
+// blocks logging. Close closes the write end and the reader sees io.EOF
+func NewPipeWriter() (Writer, io.Reader) {
+	pipe := newPipeBuffer(PipeBufferSize)
+
+	pipeWriter := new(PipeWriter)
+	pipeWriter.ConsoleWriter = new(ConsoleWriter)
+	pipeWriter.blog = NewBLog(pipe)
+	pipeWriter.blog.name = "pipe"
+	pipeWriter.closed = false
+	pipeWriter.colored = false
+
+	// log hook
+	pipeWriter.hook = nil
+	pipeWriter.hookLevel = DEBUG
+	pipeWriter.hookAsync = true
+
+	pipeWriter.pipe = pipe
+	return pipeWriter, pipe
+}
+
+// SetLevel set logging level threshold of the pipe writer and return it for
+// chaining
+func (writer *PipeWriter) SetLevel(level LevelType) Writer {
+	writer.ConsoleWriter.SetLevel(level)
+	return writer
+}
+
+// Close flushes the writer and closes the write end of the pipe
+func (writer *PipeWriter) Close() {
+	writer.ConsoleWriter.Close()
+	writer.pipe.Close()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPipeWriter(t *testing.T) {
+	writer, reader := NewPipeWriter()
+	writer.SetLevel(INFO)
+
+	done := make(chan []string)
+	go func() {
+		var lines []string
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		done <- lines
+	}()
+
+	writer.Debug("filtered")
+	for i := 0; i < 100; i++ {
+		writer.Infof("line %d", i)
+	}
+	writer.Close()
+
+	// reader sees EOF after close
+	lines := <-done
+	if 100 != len(lines) {
+		t.Fatalf("lines read back wrong. got: %d", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("[INFO] line %d", i)) {
+			t.Errorf("line read back wrong. got: %s", line)
+		}
+	}
+}
+
+func TestPipeWriterFull(t *testing.T) {
+	defer func(size int) { PipeBufferSize = size }(PipeBufferSize)
+	PipeBufferSize = 250
+	ResetDroppedStats()
+
+	writer, reader := NewPipeWriter()
+	for i := 0; i < 3; i++ {
+		// writer is never blocked by the reader
+		writer.Info(strings.Repeat("x", 100))
+		writer.flush()
+	}
+	writer.Close()
+
+	scanner := bufio.NewScanner(reader)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+	}
+	if 0 == lines || 3 == lines || int64(3-lines) != DroppedStats().QueueFull {
+		t.Errorf("lines beyond pipe buffer should be dropped. read: %d, dropped: %d", lines, DroppedStats().QueueFull)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// PrettyIndent is the indent used when pretty printing values
+	PrettyIndent = "  "
+)
+
+// prettyFormat marshals v into indented json, so that nested maps, slices
+// and structs can be read line by line.
+// values which can not be marshaled fall back to the %+v format
+func prettyFormat(v interface{}) string {
+	b, err := json.MarshalIndent(v, "", PrettyIndent)
+	if nil != err {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDebugPretty(t *testing.T) {
+	err := NewBaseFileWriter("/tmp/pretty.log", false)
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	if nil != err {
+		t.Errorf("initialize base file writer faied. err: %s", err.Error())
+	}
+
+	initPrefix(false)
+
+	v := map[string]interface{}{
+		"name": "eddie",
+		"tags": []string{"a", "b"},
+		"db": map[string]interface{}{
+			"host": "127.0.0.1",
+			"port": 3306,
+		},
+	}
+
+	DebugPretty(v)
+	Flush()
+
+	content, err := ioutil.ReadFile("/tmp/pretty.log")
+	if nil != err {
+		t.Errorf("read log file failed. err: %s", err.Error())
+	}
+
+	expected := []string{
+		"{",
+		`  "db": {`,
+		`    "host": "127.0.0.1",`,
+		`    "port": 3306`,
+		"  },",
+		`  "name": "eddie",`,
+		`  "tags": [`,
+		`    "a",`,
+		`    "b"`,
+		"  ]",
+		"}",
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), string(EOL)), string(EOL))
+	if len(expected) != len(lines) {
+		t.Fatalf("pretty block lines wrong. expected: %d, got: %d", len(expected), len(lines))
+	}
+
+	for i, line := range lines {
+		arrs := strings.SplitN(line, "[DEBUG] ", 2)
+		if 2 != len(arrs) {
+			t.Errorf("line %d has no prefix. line: %s", i, line)
+			continue
+		}
+
+		if expected[i] != arrs[1] {
+			t.Errorf("line %d content wrong. expected: %s, got: %s", i, expected[i], arrs[1])
+		}
+	}
+}
+
+func TestPrettyFormatFallback(t *testing.T) {
+	if s := prettyFormat(T{123, "test"}); "{\n  \"A\": 123,\n  \"B\": \"test\"\n}" != s {
+		t.Errorf("pretty format struct wrong. got: %s", s)
+	}
+
+	// channels can not be marshaled into json
+	ch := make(chan int)
+	if s := prettyFormat(ch); "" == s || strings.HasPrefix(s, "{") {
+		t.Errorf("pretty format fallback wrong. got: %s", s)
+	}
+}
+
+func TestDebugPrettyPrefix(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetLevel(DEBUG)
+	writer.SetPrintSequence(true)
+	writer.AddDefaultField("app", "api")
+
+	writer.DebugPretty(map[string]int{"port": 3306})
+	writer.blog.flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), string(EOL)), string(EOL))
+	if 3 != len(lines) {
+		t.Fatalf("pretty block lines wrong. got: %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], "[DEBUG] seq=000001 app=api {") {
+		t.Errorf("first line should carry sequence and default fields. got: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], `[DEBUG]   "port": 3306`) {
+		t.Errorf("following lines should carry time and level prefix only. got: %s", lines[1])
+	}
+
+	// lines are split by EOL of the writer, a null terminated record keeps
+	// the block whole
+	buf.Reset()
+	writer.SetEOL(0)
+	writer.DebugPretty(map[string]int{"port": 3306})
+	writer.blog.flush()
+	if 1 != strings.Count(buf.String(), "\x00") || 2 != strings.Count(buf.String(), "\n") {
+		t.Errorf("block should be written as a record. got: %q", buf.String())
+	}
+}
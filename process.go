@@ -0,0 +1,63 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+var (
+	// pid is the process id cached at startup
+	pid = strconv.Itoa(os.Getpid())
+
+	// program is basename of os.Args[0] cached at startup
+	program = func() string {
+		if 0 == len(os.Args) || "" == os.Args[0] {
+			return "unknown"
+		}
+		return filepath.Base(os.Args[0])
+	}()
+)
+
+// PrintPID get whether process id is written ahead every message
+func (blog *BLog) PrintPID() bool {
+	return blog.defaults.printPID
+}
+
+// SetPrintPID toggle writing process id ahead every message syslog style as
+// "[1234] message", or "prog[1234] message" with program name, so that
+// processes sharing a log file are told apart
+func (blog *BLog) SetPrintPID(print bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.setPrintPID(print)
+	return blog
+}
+
+// PrintProgram get whether program name is written ahead every message
+func (blog *BLog) PrintProgram() bool {
+	return blog.defaults.printProgram
+}
+
+// SetPrintProgram toggle writing basename of os.Args[0] ahead every message
+// as "prog message", it comes before hostname && default fields
+func (blog *BLog) SetPrintProgram(print bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.defaults.setPrintProgram(print)
+	return blog
+}
+
+// SetPrintPID toggle writing process id ahead every message of the
+// singleton writer
+func SetPrintPID(print bool) {
+	blog().SetPrintPID(print)
+}
+
+// SetPrintProgram toggle writing program name ahead every message of the
+// singleton writer
+func SetPrintProgram(print bool) {
+	blog().SetPrintProgram(print)
+}
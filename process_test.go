@@ -0,0 +1,49 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintPIDProgram(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetPrintProgram(true)
+	writer.SetPrintPID(true)
+	writer.SetPrintHostname(true)
+	writer.SetHostname("web01")
+	writer.Info("started")
+
+	writer.SetPrintProgram(false)
+	writer.SetPrintHostname(false)
+	writer.Info("pid only")
+	writer.flush()
+
+	expected := []string{
+		fmt.Sprintf(" [INFO] %s[%d] host=web01 started", filepath.Base(os.Args[0]), os.Getpid()),
+		fmt.Sprintf(" [INFO] [%d] pid only", os.Getpid()),
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(expected) != len(lines) {
+		t.Fatalf("lines count wrong. content: %s", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("program[pid] should be ahead hostname. line: %s, expected: %s", line, expected[i])
+		}
+	}
+
+	cfg := new(WriterConfig)
+	writer.blog.config(cfg)
+	if !cfg.PrintPID || cfg.PrintProgram {
+		t.Errorf("config should carry pid && program settings. config: %+v", cfg)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+//go:build race
+// +build race
+
+package blog4go
+
+func init() {
+	raceEnabled = true
+}
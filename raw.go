@@ -0,0 +1,20 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+var (
+	// RawLevel is the level Raw lines are filtered, flushed and routed with,
+	// default CRITICAL so that they are always written
+	RawLevel = CRITICAL
+)
+
+// Raw static function for Raw
+func Raw(message string) {
+	if RawLevel < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Raw(message)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRaw(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetLevel(WARNING)
+
+	writer.Info("filtered")
+	writer.Warn("warn")
+	writer.Raw(`{"event":"deploy"}`)
+	writer.blog.flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if 2 != len(lines) {
+		t.Fatalf("lines written wrong. got: %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], "[WARN] warn") || !strings.HasPrefix(lines[0], "["+timeCache.Now().Format("2006")) {
+		t.Errorf("warn line should have time && level prefix. got: %s", lines[0])
+	}
+	if `{"event":"deploy"}` != lines[1] {
+		t.Errorf("raw line should be written verbatim. got: %s", lines[1])
+	}
+
+	// raw lines are filtered with RawLevel
+	defer func(level LevelType) { RawLevel = level }(RawLevel)
+	RawLevel = INFO
+	buf.Reset()
+	writer.Raw("filtered")
+	writer.blog.flush()
+	if 0 != buf.Len() {
+		t.Errorf("raw line below level should be filtered. got: %s", buf.String())
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"time"
+)
+
+const (
+	// ReorderLimit is the maximum number of lines held in reorder mode,
+	// the oldest lines are written at once when it is exceeded
+	ReorderLimit = 1024
+)
+
+// reorderEntry is a line held with the time it is captured
+type reorderEntry struct {
+	t    time.Time
+	line []byte
+}
+
+// reorderBuffer holds lines for window and keeps them sorted by time
+type reorderBuffer struct {
+	window  time.Duration
+	entries []reorderEntry
+	// timer releasing lines held longer than window
+	timer *time.Timer
+}
+
+// hold keeps a copy of line captured now in timestamp order,
+// it is called under lock of BLog
+func (blog *BLog) hold(line []byte) {
+	r := blog.reorder
+	e := reorderEntry{t: now(), line: append([]byte(nil), line...)}
+
+	// lines mostly come in order, insert from the tail
+	i := len(r.entries)
+	r.entries = append(r.entries, e)
+	for ; i > 0 && r.entries[i-1].t.After(e.t); i-- {
+		r.entries[i] = r.entries[i-1]
+	}
+	r.entries[i] = e
+
+	if len(r.entries) > ReorderLimit {
+		blog.release(len(r.entries) - ReorderLimit)
+	}
+
+	if nil == r.timer {
+		r.timer = time.AfterFunc(r.window, func() {
+			blog.releaseDue(r)
+		})
+	}
+}
+
+// releaseDue writes lines of r held longer than window
+func (blog *BLog) releaseDue(r *reorderBuffer) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if r != blog.reorder || blog.closed {
+		return
+	}
+
+	r.timer = nil
+	deadline := now().Add(-r.window)
+	var n int
+	for n < len(r.entries) && !r.entries[n].t.After(deadline) {
+		n++
+	}
+	blog.release(n)
+
+	if 0 < len(r.entries) {
+		r.timer = time.AfterFunc(r.entries[0].t.Sub(deadline), func() {
+			blog.releaseDue(r)
+		})
+	}
+}
+
+// release writes the first n lines held, it is called under lock of BLog
+func (blog *BLog) release(n int) {
+	r := blog.reorder
+	for _, e := range r.entries[:n] {
+		if blog.atomic {
+			blog.in.Write(e.line)
+		} else {
+			blog.buffered().Write(e.line)
+		}
+	}
+
+	// 不持有已写出的行
+	rest := copy(r.entries, r.entries[n:])
+	for i := rest; i < len(r.entries); i++ {
+		r.entries[i] = reorderEntry{}
+	}
+	r.entries = r.entries[:rest]
+}
+
+// releaseAll writes all lines held and stops releasing timer,
+// it is called under lock of BLog
+func (blog *BLog) releaseAll() {
+	if nil == blog.reorder {
+		return
+	}
+
+	if nil != blog.reorder.timer {
+		blog.reorder.timer.Stop()
+		blog.reorder.timer = nil
+	}
+	blog.release(len(blog.reorder.entries))
+}
+
+// SetReorderWindow holds lines for d and writes them sorted by the time they
+// are captured, which smooths ordering jitter of lines from concurrent
+// goroutines at the cost of d latency. At most ReorderLimit lines are held,
+// lines are written at once on flush and close. d <= 0 disables it
+func (blog *BLog) SetReorderWindow(d time.Duration) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.releaseAll()
+	if d <= 0 {
+		blog.reorder = nil
+		return blog
+	}
+
+	blog.reorder = &reorderBuffer{window: d}
+	return blog
+}
+
+// SetReorderWindow holds lines of the singleton writer for d and writes
+// them sorted by time
+func SetReorderWindow(d time.Duration) {
+	blog().SetReorderWindow(d)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReorderWindow(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetReorderWindow(50 * time.Millisecond)
+
+	// lines captured out of order
+	c.Add(10 * time.Millisecond)
+	blog.write(INFO, "second")
+	c.Add(-5 * time.Millisecond)
+	blog.write(INFO, "first")
+	c.Add(15 * time.Millisecond)
+	blog.write(INFO, "third")
+
+	buffered := func() int {
+		blog.lock.Lock()
+		defer blog.lock.Unlock()
+		return blog.writer.Buffered()
+	}
+	if 0 != buffered() {
+		t.Errorf("lines should be held within the window. buffered: %d", buffered())
+	}
+
+	// released after the window
+	c.Add(time.Second)
+	time.Sleep(200 * time.Millisecond)
+	if 0 == buffered() {
+		t.Error("lines should be released after the window.")
+	}
+
+	blog.flush()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if 3 != len(lines) || !strings.HasSuffix(lines[0], "first") || !strings.HasSuffix(lines[1], "second") || !strings.HasSuffix(lines[2], "third") {
+		t.Errorf("lines should be written in timestamp order. content: %s", buf.String())
+	}
+
+	// flushed on close
+	buf.Reset()
+	blog.write(INFO, "held")
+	blog.Close()
+	if !strings.HasSuffix(buf.String(), " [INFO] held\n") {
+		t.Errorf("lines held should be written on close. content: %s", buf.String())
+	}
+}
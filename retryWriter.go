@@ -0,0 +1,70 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"time"
+)
+
+// retryPolicy retries a failed delivery, waiting backoff before the first
+// retry and doubling it after every retry
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// do calls deliver until it succeeds or attempts retries are used up, and
+// return the last error
+func (policy *retryPolicy) do(deliver func() error) (err error) {
+	backoff := policy.backoff
+	for i := 0; ; i++ {
+		if err = deliver(); nil == err || i >= policy.attempts {
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retrySink retries messages the underlying sink fails to emit
+type retrySink struct {
+	Sink
+	policy *retryPolicy
+}
+
+// Emit emits message, retrying on failure
+func (sink *retrySink) Emit(t time.Time, level LevelType, message string) error {
+	return sink.policy.do(func() error {
+		return sink.Sink.Emit(t, level, message)
+	})
+}
+
+// NewRetryWriter makes w retry a line failed to be delivered up to attempts
+// times, waiting backoff before the first retry and doubling it after every
+// retry. when all retries fail the error is reported to the error handler of
+// w. retries happen before the next line is written, so lines keep their
+// order and lines delivered are never written twice.
+// sink and socket writers are supported, file and console writers report no
+// error per line and are returned as they are. w itself is returned
+func NewRetryWriter(w Writer, attempts int, backoff time.Duration) Writer {
+	if attempts < 1 {
+		return w
+	}
+	policy := &retryPolicy{attempts: attempts, backoff: backoff}
+
+	switch writer := w.(type) {
+	case *SinkWriter:
+		writer.lock.Lock()
+		defer writer.lock.Unlock()
+		writer.sink = &retrySink{Sink: writer.sink, policy: policy}
+	case *SocketWriter:
+		writer.lock.Lock()
+		defer writer.lock.Unlock()
+		writer.retry = policy
+	case *MultiWriter:
+		for _, fileWriter := range writer.writers {
+			NewRetryWriter(fileWriter, attempts, backoff)
+		}
+	}
+	return w
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakySink fails the first failures emits then succeeds
+type flakySink struct {
+	*mySink
+	failures int
+	emits    int
+}
+
+func (sink *flakySink) Emit(t time.Time, level LevelType, message string) error {
+	sink.emits++
+	if sink.emits <= sink.failures {
+		return errors.New("sink unavailable")
+	}
+	return sink.mySink.Emit(t, level, message)
+}
+
+func TestRetryWriter(t *testing.T) {
+	sink := &flakySink{mySink: newMySink(), failures: 2}
+	writer := NewRetryWriter(NewSinkWriter(sink), 3, time.Millisecond)
+
+	var errs []error
+	writer.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	writer.Info("first")
+	writer.Info("second")
+
+	if 4 != sink.emits {
+		t.Errorf("failed emits should be retried. emits: %d", sink.emits)
+	}
+	if 2 != len(sink.messages) || "first" != sink.messages[0] || "second" != sink.messages[1] {
+		t.Errorf("messages should be delivered once in order. messages: %v", sink.messages)
+	}
+	if 0 != len(errs) {
+		t.Errorf("no error should be reported. errors: %v", errs)
+	}
+
+	// give up after all retries
+	sink.failures = sink.emits + 10
+	writer.Info("lost")
+	if 1 != len(errs) {
+		t.Errorf("error should be reported after retries. errors: %v", errs)
+	}
+	if 2 != len(sink.messages) {
+		t.Errorf("lost message should not be delivered. messages: %v", sink.messages)
+	}
+}
@@ -0,0 +1,891 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// routeRule sends messages with level between min and max to writer
+type routeRule struct {
+	min    LevelType
+	max    LevelType
+	writer Writer
+}
+
+// RouterWriter routes every message to all writers whose level range covers
+// the message level, e.g. DEBUG to a discarding writer, INFO to WARNING to a
+// file, ERROR and above to both the file and a socket. Settings are applied
+// to every writer routed to
+type RouterWriter struct {
+	name  string
+	level LevelType
+
+	closed bool
+
+	// routing rules in order of Route
+	rules []routeRule
+	// writers routed to, every writer appears once
+	writers []Writer
+	// writers every level is routed to, every writer appears once
+	routes map[LevelType][]Writer
+
+	// log hook
+	hook      Hook
+	hookLevel LevelType
+	hookAsync bool
+
+	// formats messages of writef functions with the parser of BLog
+	format *BLog
+
+	// separators of structured fields, nil means default ones
+	sep *fieldSeparator
+
+	lock *sync.RWMutex
+}
+
+// NewRouterWriter creates a writer without any route, not singlton
+func NewRouterWriter() (routerWriter *RouterWriter) {
+	routerWriter = new(RouterWriter)
+	routerWriter.name = "router"
+	routerWriter.level = TRACE
+	routerWriter.closed = false
+	routerWriter.routes = make(map[LevelType][]Writer)
+	routerWriter.format = newFormatBLog()
+	routerWriter.lock = new(sync.RWMutex)
+
+	// log hook
+	routerWriter.hook = nil
+	routerWriter.hookLevel = DEBUG
+	routerWriter.hookAsync = true
+	return routerWriter
+}
+
+// Route sends messages with level between minLevel and maxLevel inclusive to
+// w, and return the router for chaining. a message matching more than one
+// rule of the same writer is written to it once
+func (writer *RouterWriter) Route(minLevel, maxLevel LevelType, w Writer) *RouterWriter {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.rules = append(writer.rules, routeRule{min: minLevel, max: maxLevel, writer: w})
+	if !containsWriter(writer.writers, w) {
+		writer.writers = append(writer.writers, w)
+	}
+
+	for _, level := range Levels {
+		if minLevel <= level && level <= maxLevel && !containsWriter(writer.routes[level], w) {
+			writer.routes[level] = append(writer.routes[level], w)
+		}
+	}
+	return writer
+}
+
+// containsWriter determines whether w is one of writers
+func containsWriter(writers []Writer, w Writer) bool {
+	for _, writer := range writers {
+		if writer == w {
+			return true
+		}
+	}
+	return false
+}
+
+// routed return writers messages with level are sent to, nil if the message
+// should not be written
+func (writer *RouterWriter) routed(level LevelType) []Writer {
+	if level < CompileLevel || level < writer.level {
+		return nil
+	}
+
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	if writer.closed {
+		return nil
+	}
+	return writer.routes[level]
+}
+
+// first return the first writer routed to, nil if there is none
+func (writer *RouterWriter) first() Writer {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	if 0 == len(writer.writers) {
+		return nil
+	}
+	return writer.writers[0]
+}
+
+// each calls f with every writer routed to
+func (writer *RouterWriter) each(f func(w Writer)) {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	for _, w := range writer.writers {
+		f(w)
+	}
+}
+
+// eachErr calls f with every writer routed to, the first error is returned
+func (writer *RouterWriter) eachErr(f func(w Writer) error) (err error) {
+	writer.each(func(w Writer) {
+		if e := f(w); nil != e && nil == err {
+			err = e
+		}
+	})
+	return
+}
+
+// Close closes every writer routed to once
+func (writer *RouterWriter) Close() {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return
+	}
+
+	for _, w := range writer.writers {
+		w.Close()
+	}
+	writer.closed = true
+}
+
+// SetLevel set logging level threshold of the router, messages below it are
+// not routed. levels of writers routed to are kept
+func (writer *RouterWriter) SetLevel(level LevelType) Writer {
+	writer.level = level
+	return writer
+}
+
+// Level get logging level threshold of the router
+func (writer *RouterWriter) Level() LevelType {
+	return writer.level
+}
+
+// Name get name of the writer, "router" by default
+func (writer *RouterWriter) Name() string {
+	return writer.name
+}
+
+// SetName set name of the writer
+func (writer *RouterWriter) SetName(name string) {
+	writer.name = name
+}
+
+// SetHook set hook for logging action
+func (writer *RouterWriter) SetHook(hook Hook) {
+	writer.hook = hook
+}
+
+// SetHookLevel set when hook will be called
+func (writer *RouterWriter) SetHookLevel(level LevelType) {
+	writer.hookLevel = level
+}
+
+// SetHookAsync set whether hook is called async
+func (writer *RouterWriter) SetHookAsync(async bool) {
+	writer.hookAsync = async
+}
+
+func (writer *RouterWriter) write(level LevelType, args ...interface{}) {
+	writers := writer.routed(level)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, fmt.Sprint(args...))
+		}
+	}()
+
+	for _, w := range writers {
+		w.write(level, args...)
+	}
+}
+
+func (writer *RouterWriter) writef(level LevelType, format string, args ...interface{}) {
+	writers := writer.routed(level)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, writer.format.sprintf(format, args...))
+		}
+	}()
+
+	for _, w := range writers {
+		w.writef(level, format, args...)
+	}
+}
+
+// flush flush logs of every writer routed to
+func (writer *RouterWriter) flush() {
+	writer.each(func(w Writer) { w.flush() })
+}
+
+// Trace trace
+func (writer *RouterWriter) Trace(args ...interface{}) {
+	writer.write(TRACE, args...)
+}
+
+// Tracef tracef
+func (writer *RouterWriter) Tracef(format string, args ...interface{}) {
+	writer.writef(TRACE, format, args...)
+}
+
+// Debug debug
+func (writer *RouterWriter) Debug(args ...interface{}) {
+	writer.write(DEBUG, args...)
+}
+
+// Debugf debugf
+func (writer *RouterWriter) Debugf(format string, args ...interface{}) {
+	writer.writef(DEBUG, format, args...)
+}
+
+// Info info
+func (writer *RouterWriter) Info(args ...interface{}) {
+	writer.write(INFO, args...)
+}
+
+// Infof infof
+func (writer *RouterWriter) Infof(format string, args ...interface{}) {
+	writer.writef(INFO, format, args...)
+}
+
+// InfoSync writes message with info level to writers routed and flushes
+// them immediately
+func (writer *RouterWriter) InfoSync(message string) {
+	writers := writer.routed(INFO)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, message)
+		}
+	}()
+
+	for _, w := range writers {
+		w.InfoSync(message)
+	}
+}
+
+// InfofSync formats message with info level to writers routed and flushes
+// them immediately
+func (writer *RouterWriter) InfofSync(format string, args ...interface{}) {
+	writers := writer.routed(INFO)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, writer.format.sprintf(format, args...))
+		}
+	}()
+
+	for _, w := range writers {
+		w.InfofSync(format, args...)
+	}
+}
+
+// Warn warn
+func (writer *RouterWriter) Warn(args ...interface{}) {
+	writer.write(WARNING, args...)
+}
+
+// Warnf warnf
+func (writer *RouterWriter) Warnf(format string, args ...interface{}) {
+	writer.writef(WARNING, format, args...)
+}
+
+// Error error
+func (writer *RouterWriter) Error(args ...interface{}) {
+	writer.write(ERROR, args...)
+}
+
+// Errorf errorf
+func (writer *RouterWriter) Errorf(format string, args ...interface{}) {
+	writer.writef(ERROR, format, args...)
+}
+
+// Critical critical
+func (writer *RouterWriter) Critical(args ...interface{}) {
+	writer.write(CRITICAL, args...)
+}
+
+// Criticalf criticalf
+func (writer *RouterWriter) Criticalf(format string, args ...interface{}) {
+	writer.writef(CRITICAL, format, args...)
+}
+
+// DebugPretty debug pretty
+func (writer *RouterWriter) DebugPretty(v interface{}) {
+	writers := writer.routed(DEBUG)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(DEBUG < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), DEBUG, prettyFormat(v))
+		}
+	}()
+
+	for _, w := range writers {
+		w.DebugPretty(v)
+	}
+}
+
+// ErrorStack writes message with stack trace of the caller to writers
+// routed, each of them writes a stack in full once
+func (writer *RouterWriter) ErrorStack(message string) {
+	writers := writer.routed(ERROR)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(ERROR < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), ERROR, message)
+		}
+	}()
+
+	for _, w := range writers {
+		w.ErrorStack(message)
+	}
+}
+
+// InfoJSON writes fields as a json line with info level
+func (writer *RouterWriter) InfoJSON(fields map[string]interface{}) {
+	writers := writer.routed(INFO)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(INFO < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), INFO, jsonFields(fields))
+		}
+	}()
+
+	for _, w := range writers {
+		w.InfoJSON(fields)
+	}
+}
+
+// WriteBatch writes messages contiguously to every writer routed with level,
+// and return total size written to all of them
+func (writer *RouterWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	writers := writer.routed(level)
+	if 0 == len(writers) {
+		return 0
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			for _, message := range messages {
+				fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+			}
+		}
+	}()
+
+	for _, w := range writers {
+		size += w.WriteBatch(level, messages)
+	}
+	return
+}
+
+// LogAt writes message prefixed with time t instead of now to every writer
+// routed with level
+func (writer *RouterWriter) LogAt(t time.Time, level LevelType, message string) {
+	writers := writer.routed(level)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, message)
+		}
+	}()
+
+	for _, w := range writers {
+		w.LogAt(t, level, message)
+	}
+}
+
+// LogfAt formats message prefixed with time t instead of now to every writer
+// routed with level
+func (writer *RouterWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	writers := writer.routed(level)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, writer.format.sprintf(format, args...))
+		}
+	}()
+
+	for _, w := range writers {
+		w.LogfAt(t, level, format, args...)
+	}
+}
+
+// Raw writes message verbatim to writers routed with RawLevel
+func (writer *RouterWriter) Raw(message string) {
+	writers := writer.routed(RawLevel)
+	if 0 == len(writers) {
+		return
+	}
+
+	defer func() {
+		// 异步调用log hook
+		if nil != writer.hook && !(RawLevel < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), RawLevel, message)
+		}
+	}()
+
+	for _, w := range writers {
+		w.Raw(message)
+	}
+}
+
+// Entry starts building a structured message with specific level
+func (writer *RouterWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
+// Config return settings of the first writer routed to, with level of the
+// router
+func (writer *RouterWriter) Config() (cfg WriterConfig) {
+	if w := writer.first(); nil != w {
+		cfg = w.Config()
+	}
+	cfg.Level = levelString(writer.level)
+	return
+}
+
+// ApplyConfig applies cfg to every writer routed to, the first error is
+// returned. level of the router is kept
+func (writer *RouterWriter) ApplyConfig(cfg WriterConfig) error {
+	return writer.eachErr(func(w Writer) error { return w.ApplyConfig(cfg) })
+}
+
+// Dropped return number of messages dropped by every writer routed to
+func (writer *RouterWriter) Dropped() (dropped int64) {
+	writer.each(func(w Writer) { dropped += w.Dropped() })
+	return
+}
+
+// Rotate rotates every writer routed to, the first error is returned
+func (writer *RouterWriter) Rotate() error {
+	return writer.eachErr(func(w Writer) error { return w.Rotate() })
+}
+
+// Drain drains every writer routed to, the first error is returned
+func (writer *RouterWriter) Drain() error {
+	return writer.eachErr(func(w Writer) error { return w.Drain() })
+}
+
+// Ping pings every writer routed to, the first error is returned
+func (writer *RouterWriter) Ping() error {
+	return writer.eachErr(func(w Writer) error { return w.Ping() })
+}
+
+// SetFileMode set permission bits of files of every writer routed to, the first error is returned
+func (writer *RouterWriter) SetFileMode(mode os.FileMode) error {
+	return writer.eachErr(func(w Writer) error { return w.SetFileMode(mode) })
+}
+
+// SetFileOwner set owner of files of every writer routed to, the first error is returned
+func (writer *RouterWriter) SetFileOwner(uid, gid int) error {
+	return writer.eachErr(func(w Writer) error { return w.SetFileOwner(uid, gid) })
+}
+
+// SetTimeRotated toggle time base logrotate for every writer routed to
+func (writer *RouterWriter) SetTimeRotated(timeRotated bool) {
+	writer.each(func(w Writer) { w.SetTimeRotated(timeRotated) })
+}
+
+// SetRotateSize set size base logrotate threshold for every writer routed to
+func (writer *RouterWriter) SetRotateSize(rotateSize int64) {
+	writer.each(func(w Writer) { w.SetRotateSize(rotateSize) })
+}
+
+// SetRotateLines set line number base logrotate threshold for every writer routed to
+func (writer *RouterWriter) SetRotateLines(rotateLines int) {
+	writer.each(func(w Writer) { w.SetRotateLines(rotateLines) })
+}
+
+// SetRetentions set how many logs will keep after logrotate for every writer routed to
+func (writer *RouterWriter) SetRetentions(retentions int64) {
+	writer.each(func(w Writer) { w.SetRetentions(retentions) })
+}
+
+// SetMaxTotalSize set max bytes that log files may take in total for every writer routed to
+func (writer *RouterWriter) SetMaxTotalSize(maxTotalSize int64) {
+	writer.each(func(w Writer) { w.SetMaxTotalSize(maxTotalSize) })
+}
+
+// SetRotateHook set user defined logrotate policy for every writer routed to
+func (writer *RouterWriter) SetRotateHook(hook RotateHook) {
+	writer.each(func(w Writer) { w.SetRotateHook(hook) })
+}
+
+// SetOpenBanner toggle writing a banner line when files are opened for every writer routed to
+func (writer *RouterWriter) SetOpenBanner(banner bool) {
+	writer.each(func(w Writer) { w.SetOpenBanner(banner) })
+}
+
+// SetColored toggle colored prefixes for every writer routed to
+func (writer *RouterWriter) SetColored(colored bool) {
+	writer.each(func(w Writer) { w.SetColored(colored) })
+}
+
+// SetAtomicWrite toggle writing every message with a single write call for every writer routed to
+func (writer *RouterWriter) SetAtomicWrite(atomic bool) {
+	writer.each(func(w Writer) { w.SetAtomicWrite(atomic) })
+}
+
+// SetReorderWindow hold lines for d and write them sorted by time for every writer routed to
+func (writer *RouterWriter) SetReorderWindow(d time.Duration) {
+	writer.each(func(w Writer) { w.SetReorderWindow(d) })
+}
+
+// SetDevMode toggle rendering json lines in human readable format on
+// terminal for every writer routed to
+func (writer *RouterWriter) SetDevMode(dev bool) {
+	writer.each(func(w Writer) { w.SetDevMode(dev) })
+}
+
+// SetMultilinePrefix toggle prefixing every line of a multi-line message for every writer routed to
+func (writer *RouterWriter) SetMultilinePrefix(multiline bool) {
+	writer.each(func(w Writer) { w.SetMultilinePrefix(multiline) })
+}
+
+// SetFlushLevel set the level at or above which messages are flushed immediately for every writer routed to
+func (writer *RouterWriter) SetFlushLevel(level LevelType) {
+	writer.each(func(w Writer) { w.SetFlushLevel(level) })
+}
+
+// SetBufferSize resize buffer keeping bytes buffered for every writer routed to
+func (writer *RouterWriter) SetBufferSize(size int) {
+	writer.each(func(w Writer) { w.SetBufferSize(size) })
+}
+
+// SetPlaceholder set placeholder character used in formatting for every writer routed to
+func (writer *RouterWriter) SetPlaceholder(placeholder byte) {
+	writer.format.SetPlaceholder(placeholder)
+	writer.each(func(w Writer) { w.SetPlaceholder(placeholder) })
+}
+
+// SetEOL set character ending every line for every writer routed to
+func (writer *RouterWriter) SetEOL(eol byte) {
+	writer.each(func(w Writer) { w.SetEOL(eol) })
+}
+
+// SetEscape set escape character used in formatting for every writer routed to
+func (writer *RouterWriter) SetEscape(escape byte) {
+	writer.format.SetEscape(escape)
+	writer.each(func(w Writer) { w.SetEscape(escape) })
+}
+
+// SetNilString set the token nil args are written as in formatting for every writer routed to
+func (writer *RouterWriter) SetNilString(s string) {
+	writer.format.SetNilString(s)
+	writer.each(func(w Writer) { w.SetNilString(s) })
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with recover for every writer routed to
+func (writer *RouterWriter) SetSafeStringer(safe bool) {
+	writer.format.SetSafeStringer(safe)
+	writer.each(func(w Writer) { w.SetSafeStringer(safe) })
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args for every writer routed to
+func (writer *RouterWriter) SetRawStringer(raw bool) {
+	writer.format.SetRawStringer(raw)
+	writer.each(func(w Writer) { w.SetRawStringer(raw) })
+}
+
+// SetDebounce set window messages of format are written at most once in for every writer routed to
+func (writer *RouterWriter) SetDebounce(format string, window time.Duration) {
+	writer.each(func(w Writer) { w.SetDebounce(format, window) })
+}
+
+// SetSamplerPolicy samples messages for every writer routed to
+func (writer *RouterWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	writer.each(func(w Writer) { w.SetSamplerPolicy(first, thereafter, tick) })
+}
+
+// SetPrintSequence toggle writing sequence numbers, every writer counts on its own for every writer routed to
+func (writer *RouterWriter) SetPrintSequence(sequence bool) {
+	writer.each(func(w Writer) { w.SetPrintSequence(sequence) })
+}
+
+// SetPrintElapsed toggle writing milliseconds since start for every writer routed to
+func (writer *RouterWriter) SetPrintElapsed(elapsed bool) {
+	writer.each(func(w Writer) { w.SetPrintElapsed(elapsed) })
+}
+
+// SetSequenceWidth set width sequence numbers are zero padded to for every writer routed to
+func (writer *RouterWriter) SetSequenceWidth(width int) {
+	writer.each(func(w Writer) { w.SetSequenceWidth(width) })
+}
+
+// SetDefaultFields sets static fields prepended to every message for every writer routed to
+func (writer *RouterWriter) SetDefaultFields(fields map[string]string) {
+	writer.each(func(w Writer) { w.SetDefaultFields(fields) })
+}
+
+// AddDefaultField appends a static field prepended to every message for every writer routed to
+func (writer *RouterWriter) AddDefaultField(key, value string) {
+	writer.each(func(w Writer) { w.AddDefaultField(key, value) })
+}
+
+// separators return separators in use
+func (writer *RouterWriter) separators() *fieldSeparator {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	if nil == writer.sep {
+		return defaultSeparator
+	}
+	return writer.sep
+}
+
+// setSeparators set separators for every writer routed to, the first error
+// is returned
+func (writer *RouterWriter) setSeparators(sep *fieldSeparator) error {
+	writer.lock.Lock()
+	writer.sep = sep
+	writer.lock.Unlock()
+
+	return writer.eachErr(func(w Writer) error {
+		s, ok := w.(separated)
+		if !ok {
+			return nil
+		}
+		if err := s.SetFieldSeparator(sep.field); nil != err {
+			return err
+		}
+		return s.SetKVSeparator(sep.kv)
+	})
+}
+
+// SetFieldSeparator set what is written between structured fields for every
+// writer routed to
+func (writer *RouterWriter) SetFieldSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	return writer.setSeparators(&fieldSeparator{field: sep, kv: writer.separators().kv})
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields for every writer routed to
+func (writer *RouterWriter) SetKVSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	return writer.setSeparators(&fieldSeparator{field: writer.separators().field, kv: sep})
+}
+
+// SetPrintHostname toggle writing hostname ahead every message for every writer routed to
+func (writer *RouterWriter) SetPrintHostname(print bool) {
+	writer.each(func(w Writer) { w.SetPrintHostname(print) })
+}
+
+// SetHostname overrides hostname written ahead messages for every writer routed to
+func (writer *RouterWriter) SetHostname(name string) {
+	writer.each(func(w Writer) { w.SetHostname(name) })
+}
+
+// SetPrintPID toggle writing process id ahead every message for every writer routed to
+func (writer *RouterWriter) SetPrintPID(print bool) {
+	writer.each(func(w Writer) { w.SetPrintPID(print) })
+}
+
+// SetPrintProgram toggle writing program name ahead every message for every writer routed to
+func (writer *RouterWriter) SetPrintProgram(print bool) {
+	writer.each(func(w Writer) { w.SetPrintProgram(print) })
+}
+
+// SetPrintCallerFunc toggle writing the calling function for every writer routed to
+func (writer *RouterWriter) SetPrintCallerFunc(print bool) {
+	writer.each(func(w Writer) { w.SetPrintCallerFunc(print) })
+}
+
+// SetPrintCallerLine toggle writing file:line of the caller for every writer routed to
+func (writer *RouterWriter) SetPrintCallerLine(print bool) {
+	writer.each(func(w Writer) { w.SetPrintCallerLine(print) })
+}
+
+// AddDropSubstring drops messages containing s for every writer routed to
+func (writer *RouterWriter) AddDropSubstring(s string) {
+	writer.each(func(w Writer) { w.AddDropSubstring(s) })
+}
+
+// AddDropRegexp drops messages matching re for every writer routed to
+func (writer *RouterWriter) AddDropRegexp(re *regexp.Regexp) {
+	writer.each(func(w Writer) { w.AddDropRegexp(re) })
+}
+
+// SetTimeFormat set layout of time prefix for every writer routed to
+func (writer *RouterWriter) SetTimeFormat(layout string) {
+	writer.each(func(w Writer) { w.SetTimeFormat(layout) })
+}
+
+// SetTimeFormatPreset set time prefix to a preset for every writer routed to
+func (writer *RouterWriter) SetTimeFormatPreset(preset TimePreset) {
+	writer.each(func(w Writer) { w.SetTimeFormatPreset(preset) })
+}
+
+// SetFraming set how every line is framed for every writer routed to
+func (writer *RouterWriter) SetFraming(framing Framing) {
+	writer.each(func(w Writer) { w.SetFraming(framing) })
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly for every writer routed to
+func (writer *RouterWriter) SetSmartTimeVerb(smart bool) {
+	writer.format.SetSmartTimeVerb(smart)
+	writer.each(func(w Writer) { w.SetSmartTimeVerb(smart) })
+}
+
+// SetFormatter set formatter producing whole lines for every writer routed to
+func (writer *RouterWriter) SetFormatter(formatter Formatter) {
+	writer.each(func(w Writer) { w.SetFormatter(formatter) })
+}
+
+// SetStrictFormat toggle strict format mode for every writer routed to
+func (writer *RouterWriter) SetStrictFormat(strict bool) {
+	writer.format.SetStrictFormat(strict)
+	writer.each(func(w Writer) { w.SetStrictFormat(strict) })
+}
+
+// SetErrorHandler set handler called when writers meet an error for every writer routed to
+func (writer *RouterWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.format.SetErrorHandler(handler)
+	writer.each(func(w Writer) { w.SetErrorHandler(handler) })
+}
+
+// SetWriteTimeout bound every write of every writer routed to
+func (writer *RouterWriter) SetWriteTimeout(d time.Duration) {
+	writer.each(func(w Writer) { w.SetWriteTimeout(d) })
+}
+
+// TimeRotated get whether time base logrotate is enabled of the first writer routed to
+func (writer *RouterWriter) TimeRotated() bool {
+	if w := writer.first(); nil != w {
+		return w.TimeRotated()
+	}
+	return false
+}
+
+// RotateSize get size base logrotate threshold of the first writer routed to
+func (writer *RouterWriter) RotateSize() int64 {
+	if w := writer.first(); nil != w {
+		return w.RotateSize()
+	}
+	return 0
+}
+
+// RotateLines get line number base logrotate threshold of the first writer routed to
+func (writer *RouterWriter) RotateLines() int {
+	if w := writer.first(); nil != w {
+		return w.RotateLines()
+	}
+	return 0
+}
+
+// Retentions get how many logs are kept after logrotate of the first writer routed to
+func (writer *RouterWriter) Retentions() int64 {
+	if w := writer.first(); nil != w {
+		return w.Retentions()
+	}
+	return 0
+}
+
+// MaxTotalSize get max bytes log files may take in total of the first writer routed to
+func (writer *RouterWriter) MaxTotalSize() int64 {
+	if w := writer.first(); nil != w {
+		return w.MaxTotalSize()
+	}
+	return 0
+}
+
+// FileMode get permission bits of log files of the first writer routed to
+func (writer *RouterWriter) FileMode() os.FileMode {
+	if w := writer.first(); nil != w {
+		return w.FileMode()
+	}
+	return DefaultFileMode
+}
+
+// Colored get whether prefixes are colored of the first writer routed to
+func (writer *RouterWriter) Colored() bool {
+	if w := writer.first(); nil != w {
+		return w.Colored()
+	}
+	return false
+}
+
+// AtomicWrite get whether every message is written with a single write call of the first writer routed to
+func (writer *RouterWriter) AtomicWrite() bool {
+	if w := writer.first(); nil != w {
+		return w.AtomicWrite()
+	}
+	return false
+}
+
+// MultilinePrefix get whether every line of a multi-line message is prefixed of the first writer routed to
+func (writer *RouterWriter) MultilinePrefix() bool {
+	if w := writer.first(); nil != w {
+		return w.MultilinePrefix()
+	}
+	return false
+}
+
+// FlushLevel get the level at or above which messages are flushed immediately of the first writer routed to
+func (writer *RouterWriter) FlushLevel() LevelType {
+	if w := writer.first(); nil != w {
+		return w.FlushLevel()
+	}
+	return noFlushLevel
+}
+
+// Placeholder get placeholder character used in formatting of the first writer routed to
+func (writer *RouterWriter) Placeholder() byte {
+	if w := writer.first(); nil != w {
+		return w.Placeholder()
+	}
+	return PLACEHOLDER
+}
+
+// StrictFormat get whether strict format mode is on of the first writer routed to
+func (writer *RouterWriter) StrictFormat() bool {
+	if w := writer.first(); nil != w {
+		return w.StrictFormat()
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"reflect"
+	"testing"
+)
+
+// closeCounter counts calls of Close
+type closeCounter struct {
+	*SinkWriter
+	closes int
+}
+
+func (writer *closeCounter) Close() {
+	writer.closes++
+	writer.SinkWriter.Close()
+}
+
+func TestRouterWriter(t *testing.T) {
+	devnull, file, socket := newMySink(), newMySink(), newMySink()
+	fileWriter := &closeCounter{SinkWriter: NewSinkWriter(file)}
+
+	var writer Writer = NewRouterWriter().
+		Route(TRACE, DEBUG, NewSinkWriter(devnull)).
+		Route(INFO, WARNING, fileWriter).
+		Route(ERROR, CRITICAL, fileWriter).
+		Route(ERROR, CRITICAL, NewSinkWriter(socket))
+
+	writer.Debug("debug")
+	writer.Infof("info %d", 1)
+	writer.Warn("warn")
+	writer.Error("error")
+	writer.Criticalf("critical %d", 2)
+
+	if !reflect.DeepEqual([]string{"debug"}, devnull.messages) {
+		t.Errorf("debug messages routed wrong. messages: %v", devnull.messages)
+	}
+	if !reflect.DeepEqual([]string{"info 1", "warn", "error", "critical 2"}, file.messages) {
+		t.Errorf("file messages routed wrong. messages: %v", file.messages)
+	}
+	if !reflect.DeepEqual([]string{"error", "critical 2"}, socket.messages) {
+		t.Errorf("socket messages routed wrong. messages: %v", socket.messages)
+	}
+
+	// threshold of the router filters messages before routing
+	writer.SetLevel(ERROR).Warn("filtered")
+	if 4 != len(file.messages) {
+		t.Errorf("messages below level should not be routed. messages: %v", file.messages)
+	}
+
+	writer.Close()
+	if 1 != fileWriter.closes {
+		t.Errorf("writer routed by two rules should be closed once. closes: %d", fileWriter.closes)
+	}
+	if !devnull.closed || !file.closed || !socket.closed {
+		t.Errorf("every writer routed to should be closed")
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"time"
+)
+
+// sampler logs the first messages of a format in every tick, and only every
+// Mth of the rest
+type sampler struct {
+	first      int
+	thereafter int
+	tick       time.Duration
+
+	// counters are reset after until
+	until  time.Time
+	counts map[string]int
+}
+
+// sampled determines whether a message with format should be dropped,
+// it is called under lock of BLog
+func (s *sampler) sampled(format string) bool {
+	t := now()
+	if !t.Before(s.until) {
+		// 新的tick，计数清零
+		s.until = t.Add(s.tick)
+		s.counts = make(map[string]int)
+	}
+
+	s.counts[format]++
+	n := s.counts[format]
+	if n <= s.first {
+		return false
+	}
+
+	return s.thereafter <= 0 || 0 != (n-s.first)%s.thereafter
+}
+
+// SetSamplerPolicy logs the first messages of every format in full in every
+// tick, then only every thereafter-th of them, like the sampler of zap. It
+// keeps the context of a burst while thinning out repetitive messages,
+// messages are matched by format string of writef functions such as Infof.
+// thereafter <= 0 drops all messages after the first ones, tick <= 0 removes
+// the policy
+func (blog *BLog) SetSamplerPolicy(first, thereafter int, tick time.Duration) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if tick <= 0 {
+		blog.sampler = nil
+		return blog
+	}
+
+	blog.sampler = &sampler{first: first, thereafter: thereafter, tick: tick}
+	return blog
+}
+
+// SetSamplerPolicy samples messages of the singleton writer
+func SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	blog().SetSamplerPolicy(first, thereafter, tick)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplerPolicy(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetSamplerPolicy(3, 5, time.Second)
+
+	for i := 1; i <= 20; i++ {
+		blog.writef(INFO, "request %d", i)
+	}
+	blog.writef(INFO, "other %d", 1)
+	blog.flush()
+
+	expected := []string{"request 1", "request 2", "request 3", "request 8", "request 13", "request 18", "other 1"}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(expected) != len(lines) {
+		t.Fatalf("messages not sampled. content: %s", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, " [INFO] "+expected[i]) {
+			t.Errorf("sampled message wrong. expected: %s, line: %s", expected[i], line)
+		}
+	}
+
+	// counters reset every tick
+	c.Add(time.Second)
+	buf.Reset()
+	for i := 1; i <= 4; i++ {
+		blog.writef(INFO, "request %d", i)
+	}
+	blog.flush()
+	if 3 != strings.Count(buf.String(), "\n") {
+		t.Errorf("counters should be reset in a new tick. content: %s", buf.String())
+	}
+
+	// thereafter <= 0 drops all after the first ones
+	blog.SetSamplerPolicy(1, 0, time.Second)
+	buf.Reset()
+	for i := 1; i <= 4; i++ {
+		blog.writef(INFO, "request %d", i)
+	}
+	blog.flush()
+	if 1 != strings.Count(buf.String(), "\n") {
+		t.Errorf("only the first message should be written. content: %s", buf.String())
+	}
+
+	// tick <= 0 removes the policy
+	blog.SetSamplerPolicy(1, 0, 0)
+	buf.Reset()
+	for i := 1; i <= 4; i++ {
+		blog.writef(INFO, "request %d", i)
+	}
+	blog.flush()
+	if 4 != strings.Count(buf.String(), "\n") {
+		t.Errorf("all messages should be written without policy. content: %s", buf.String())
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// DefaultFieldSeparator is written between structured fields
+	DefaultFieldSeparator = " "
+	// DefaultKVSeparator is written between key and value of a field
+	DefaultKVSeparator = "="
+)
+
+var (
+	// ErrEmptySeparator separator given is empty
+	ErrEmptySeparator = errors.New("Separator must not be empty")
+	// ErrSeparatorNotSupported the writer does not support custom separators
+	ErrSeparatorNotSupported = errors.New("Writer does not support custom separators")
+
+	// defaultSeparator is used by writers not setting separators
+	defaultSeparator = &fieldSeparator{field: DefaultFieldSeparator, kv: DefaultKVSeparator}
+)
+
+// fieldSeparator is how structured fields are separated, it is never changed
+// after created so that it can be shared by entries in flight
+type fieldSeparator struct {
+	field string
+	kv    string
+}
+
+// custom return whether s contains a separator not quoted by logfmtValue
+// already
+func (sep *fieldSeparator) custom(s string) bool {
+	if DefaultFieldSeparator != sep.field && strings.Contains(s, sep.field) {
+		return true
+	}
+	return DefaultKVSeparator != sep.kv && strings.Contains(s, sep.kv)
+}
+
+// separated is implemented by writers supporting custom separators
+type separated interface {
+	separators() *fieldSeparator
+	SetFieldSeparator(sep string) error
+	SetKVSeparator(sep string) error
+}
+
+// separatorsOf return separators used by writer, default ones if it does not
+// support custom separators
+func separatorsOf(writer Writer) *fieldSeparator {
+	if s, ok := writer.(separated); ok {
+		return s.separators()
+	}
+	return defaultSeparator
+}
+
+// separator return separators of default fields
+func (defaults *defaultFields) separator() *fieldSeparator {
+	if nil == defaults.sep {
+		return defaultSeparator
+	}
+	return defaults.sep
+}
+
+// setFieldSeparator replaces field separator and renders fields again
+func (defaults *defaultFields) setFieldSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	defaults.sep = &fieldSeparator{field: sep, kv: defaults.separator().kv}
+	defaults.render()
+	return nil
+}
+
+// setKVSeparator replaces kv separator and renders fields again
+func (defaults *defaultFields) setKVSeparator(sep string) error {
+	if "" == sep {
+		return ErrEmptySeparator
+	}
+	defaults.sep = &fieldSeparator{field: defaults.separator().field, kv: sep}
+	defaults.render()
+	return nil
+}
+
+// separators return separators in use
+func (blog *BLog) separators() *fieldSeparator {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	return blog.defaults.separator()
+}
+
+// SetFieldSeparator set what is written between structured fields of Entry
+// and default fields, default a space, e.g. "\t" for tab separated output.
+// values containing it are quoted
+func (blog *BLog) SetFieldSeparator(sep string) error {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	return blog.defaults.setFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields, default "=". values containing it are quoted
+func (blog *BLog) SetKVSeparator(sep string) error {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	return blog.defaults.setKVSeparator(sep)
+}
+
+// SetFieldSeparator set what is written between structured fields of the
+// singleton writer, ErrSeparatorNotSupported if it does not support custom
+// separators
+func SetFieldSeparator(sep string) error {
+	holder := acquireSingleton()
+	defer holder.release()
+
+	s, ok := holder.writer.(separated)
+	if !ok {
+		return ErrSeparatorNotSupported
+	}
+	return s.SetFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields of the singleton writer, ErrSeparatorNotSupported if it does not
+// support custom separators
+func SetKVSeparator(sep string) error {
+	holder := acquireSingleton()
+	defer holder.release()
+
+	s, ok := holder.writer.(separated)
+	if !ok {
+		return ErrSeparatorNotSupported
+	}
+	return s.SetKVSeparator(sep)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFieldSeparator(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := newCaptureWriter(buf)
+	writer.SetDefaultFields(map[string]string{"env": "prod", "service": "auth"})
+
+	if ErrEmptySeparator != writer.SetFieldSeparator("") || ErrEmptySeparator != writer.SetKVSeparator("") {
+		t.Fatalf("empty separators should be rejected")
+	}
+
+	// default fields set already are rendered again
+	if err := writer.SetFieldSeparator("\t"); nil != err {
+		t.Fatalf("Failed when setting field separator. err: %s", err.Error())
+	}
+	if err := writer.SetKVSeparator(":"); nil != err {
+		t.Fatalf("Failed when setting kv separator. err: %s", err.Error())
+	}
+
+	writer.Entry(INFO).Str("user", "a b").Int("code", 200).Str("path", "/x:y").Msg("login")
+	writer.blog.flush()
+
+	expected := "env:prod\tservice:auth\tlogin\tuser:\"a b\"\tcode:200\tpath:\"/x:y\"\n"
+	if !strings.HasSuffix(buf.String(), "[INFO] "+expected) {
+		t.Errorf("fields should be tab separated. got: %q", buf.String())
+	}
+
+	// separators are kept per writer
+	otherBuf := new(bytes.Buffer)
+	other := newCaptureWriter(otherBuf)
+	other.AddDefaultField("env", "dev")
+	other.Entry(INFO).Str("path", "/x:y").Msg("login")
+	other.blog.flush()
+	if !strings.HasSuffix(otherBuf.String(), "[INFO] env=dev login path=/x:y\n") {
+		t.Errorf("other writer should keep default separators. got: %q", otherBuf.String())
+	}
+}
+
+func TestSingletonFieldSeparator(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	buf := new(bytes.Buffer)
+	setBlog(newCaptureWriter(buf))
+	if err := SetKVSeparator(":"); nil != err {
+		t.Fatalf("Failed when setting kv separator. err: %s", err.Error())
+	}
+
+	NewEntry(INFO).Int("code", 200).Msg("login")
+	Flush()
+	if !strings.HasSuffix(buf.String(), "[INFO] login code:200\n") {
+		t.Errorf("separators of singleton should be used. got: %q", buf.String())
+	}
+
+	// a wrapper only implementing Writer
+	setBlog(struct{ Writer }{newCaptureWriter(buf)})
+	if ErrSeparatorNotSupported != SetFieldSeparator("\t") {
+		t.Errorf("writer without custom separators should be reported")
+	}
+}
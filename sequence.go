@@ -0,0 +1,87 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+const (
+	// DefaultSequenceWidth is the default width sequence numbers are zero
+	// padded to, so that they sort as strings
+	DefaultSequenceWidth = 6
+)
+
+// writeSequence writes the next sequence number as "seq=000123 " in
+// sequence mode, and return number of bytes written
+func (blog *BLog) writeSequence(w lineWriter) int {
+	if !blog.sequence {
+		return 0
+	}
+
+	seq := atomic.AddUint64(&blog.seq, 1)
+
+	digits := 1
+	for v := seq; v >= 10; v /= 10 {
+		digits++
+	}
+
+	buf := append(blog.seqBuf[:0], "seq="...)
+	for i := digits; i < blog.seqWidth; i++ {
+		buf = append(buf, '0')
+	}
+	buf = strconv.AppendUint(buf, seq, 10)
+	buf = append(buf, ' ')
+
+	blog.seqBuf = buf
+	w.Write(buf)
+	return len(buf)
+}
+
+// unwriteSequence gives back sequence number of a dropped line, so that
+// numbers of lines written keep contiguous. lines of formatter carry no
+// sequence number
+func (blog *BLog) unwriteSequence() {
+	if blog.sequence && nil == blog.formatter {
+		atomic.AddUint64(&blog.seq, ^uint64(0))
+	}
+}
+
+// PrintSequence get whether sequence numbers are written
+func (blog *BLog) PrintSequence() bool {
+	return blog.sequence
+}
+
+// SetPrintSequence toggle writing an increasing sequence number ahead every
+// message, such as "seq=000123 message", so that downstream systems detect
+// dropped or reordered lines. The counter belongs to BLog and keeps counting
+// across logrotate
+func (blog *BLog) SetPrintSequence(sequence bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.sequence = sequence
+	return blog
+}
+
+// SequenceWidth get width sequence numbers are zero padded to
+func (blog *BLog) SequenceWidth() int {
+	return blog.seqWidth
+}
+
+// SetSequenceWidth set width sequence numbers are zero padded to, 0 means
+// no padding. numbers wider than it are written as they are
+func (blog *BLog) SetSequenceWidth(width int) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	if width < 0 {
+		width = 0
+	}
+	blog.seqWidth = width
+	return blog
+}
+
+// Sequence return the last sequence number written
+func (blog *BLog) Sequence() uint64 {
+	return atomic.LoadUint64(&blog.seq)
+}
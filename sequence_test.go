@@ -0,0 +1,83 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBLogSequence(t *testing.T) {
+	initPrefix(false)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetPrintSequence(true)
+	blog.AddDropSubstring("/healthz")
+
+	blog.write(INFO, "first")
+	blog.writef(INFO, "GET %s", "/healthz")
+	blog.writef(ERROR, "second %d", 2)
+	blog.SetSequenceWidth(2)
+	blog.write(WARNING, "third")
+	blog.flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expected := []string{" [INFO] seq=000001 first", " [ERROR] seq=000002 second 2", " [WARN] seq=03 third"}
+	if len(expected) != len(lines) {
+		t.Fatalf("lines count wrong. content: %s", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("sequence number wrong. line: %s, expected: %s", line, expected[i])
+		}
+	}
+}
+
+func TestBaseFileWriterSequenceConcurrent(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/sequence.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.SetPrintSequence(true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				writer.Infof("goroutine %d line %d", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	writer.flush()
+
+	// lines are written in the order of their numbers
+	content, _ := ioutil.ReadFile("/tmp/sequence.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 1000 != len(lines) {
+		t.Fatalf("lines count wrong. lines: %d", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf("] seq=%06d goroutine", i+1)) {
+			t.Fatalf("sequence numbers should be contiguous. line %d: %s", i+1, line)
+		}
+	}
+
+	writer.Close()
+}
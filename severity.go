@@ -0,0 +1,40 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+)
+
+// SeverityMapper maps a level to severity of an external system, such as
+// syslog or OpenTelemetry
+type SeverityMapper func(level LevelType) int
+
+var (
+	// severityMapper used by integration writers, nil means their defaults
+	severityMapper SeverityMapper
+
+	// severityLock protects severityMapper
+	severityLock = new(sync.RWMutex)
+)
+
+// SetSeverityMapper set mapper used by integration writers to map levels to
+// severities of their destinations, e.g. treating TRACE as DEBUG. Every
+// integration ships its own mapping, nil restores them
+func SetSeverityMapper(mapper SeverityMapper) {
+	severityLock.Lock()
+	defer severityLock.Unlock()
+	severityMapper = mapper
+}
+
+// Severity return severity of level mapped by the mapper set, or def of the
+// integration if no mapper is set. It is called by integration writers
+func Severity(level LevelType, def int) int {
+	severityLock.RLock()
+	defer severityLock.RUnlock()
+
+	if nil == severityMapper {
+		return def
+	}
+	return severityMapper(level)
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	// ErrInvalidShards shards must be positive and key function given
+	ErrInvalidShards = errors.New("Invalid shards setting")
+)
+
+// ShardedWriter splits messages across N files by hash of a key taken from
+// every message, so that messages of the same key land in the same file and
+// files stay balanced for parallel ingestion. Every shard is a file writer
+// with its own buffer && lock. Settings, flush and Close are applied to all
+// shards as RouterWriter does
+type ShardedWriter struct {
+	*RouterWriter
+
+	// shards in order of index
+	shards []Writer
+	// key return the key of a message
+	key func(message string) string
+}
+
+// NewShardedFileWriter creates a writer splitting messages across shards
+// files named <prefix>.<index>.log by hash of keyFunc(message), not singlton
+func NewShardedFileWriter(prefix string, shards int, keyFunc func(message string) string) (shardedWriter *ShardedWriter, err error) {
+	if shards <= 0 || nil == keyFunc {
+		return nil, ErrInvalidShards
+	}
+
+	shardedWriter = new(ShardedWriter)
+	shardedWriter.RouterWriter = NewRouterWriter()
+	shardedWriter.name = "sharded"
+	shardedWriter.key = keyFunc
+
+	for i := 0; i < shards; i++ {
+		shard, err := newBaseFileWriter(ShardFileName(prefix, i), false)
+		if nil != err {
+			shardedWriter.Close()
+			return nil, err
+		}
+
+		shardedWriter.shards = append(shardedWriter.shards, shard)
+		shardedWriter.Route(TRACE, CRITICAL, shard)
+	}
+	return shardedWriter, nil
+}
+
+// ShardFileName return file name of shard index
+func ShardFileName(prefix string, index int) string {
+	return fmt.Sprintf("%s.%d.log", prefix, index)
+}
+
+// Shard return index of the shard message is written to
+func (writer *ShardedWriter) Shard(message string) int {
+	h := fnv.New32a()
+	h.Write([]byte(writer.key(message)))
+	return int(h.Sum32() % uint32(len(writer.shards)))
+}
+
+// shard return the shard message is written to, nil if messages with
+// level should not be written
+func (writer *ShardedWriter) shard(level LevelType, message string) Writer {
+	if 0 == len(writer.routed(level)) {
+		return nil
+	}
+	return writer.shards[writer.Shard(message)]
+}
+
+// SetLevel set logging level threshold of the sharded writer and return it
+// for chaining
+func (writer *ShardedWriter) SetLevel(level LevelType) Writer {
+	writer.RouterWriter.SetLevel(level)
+	return writer
+}
+
+// fire calls hook of the sharded writer
+func (writer *ShardedWriter) fire(t time.Time, level LevelType, message string) {
+	// 异步调用log hook
+	if nil != writer.hook && !(level < writer.hookLevel) {
+		fireHook(writer.hook, writer.hookAsync, t, level, message)
+	}
+}
+
+func (writer *ShardedWriter) write(level LevelType, args ...interface{}) {
+	message := fmt.Sprint(args...)
+	if w := writer.shard(level, message); nil != w {
+		w.write(level, args...)
+		writer.fire(timeCache.Now(), level, message)
+	}
+}
+
+func (writer *ShardedWriter) writef(level LevelType, format string, args ...interface{}) {
+	message := writer.format.sprintf(format, args...)
+	if w := writer.shard(level, message); nil != w {
+		w.writef(level, format, args...)
+		writer.fire(timeCache.Now(), level, message)
+	}
+}
+
+// Trace trace
+func (writer *ShardedWriter) Trace(args ...interface{}) {
+	writer.write(TRACE, args...)
+}
+
+// Tracef tracef
+func (writer *ShardedWriter) Tracef(format string, args ...interface{}) {
+	writer.writef(TRACE, format, args...)
+}
+
+// Debug debug
+func (writer *ShardedWriter) Debug(args ...interface{}) {
+	writer.write(DEBUG, args...)
+}
+
+// Debugf debugf
+func (writer *ShardedWriter) Debugf(format string, args ...interface{}) {
+	writer.writef(DEBUG, format, args...)
+}
+
+// Info info
+func (writer *ShardedWriter) Info(args ...interface{}) {
+	writer.write(INFO, args...)
+}
+
+// Infof infof
+func (writer *ShardedWriter) Infof(format string, args ...interface{}) {
+	writer.writef(INFO, format, args...)
+}
+
+// Warn warn
+func (writer *ShardedWriter) Warn(args ...interface{}) {
+	writer.write(WARNING, args...)
+}
+
+// Warnf warnf
+func (writer *ShardedWriter) Warnf(format string, args ...interface{}) {
+	writer.writef(WARNING, format, args...)
+}
+
+// Error error
+func (writer *ShardedWriter) Error(args ...interface{}) {
+	writer.write(ERROR, args...)
+}
+
+// Errorf errorf
+func (writer *ShardedWriter) Errorf(format string, args ...interface{}) {
+	writer.writef(ERROR, format, args...)
+}
+
+// Critical critical
+func (writer *ShardedWriter) Critical(args ...interface{}) {
+	writer.write(CRITICAL, args...)
+}
+
+// Criticalf criticalf
+func (writer *ShardedWriter) Criticalf(format string, args ...interface{}) {
+	writer.writef(CRITICAL, format, args...)
+}
+
+// DebugPretty debug pretty, the key is taken from the pretty block
+func (writer *ShardedWriter) DebugPretty(v interface{}) {
+	message := prettyFormat(v)
+	if w := writer.shard(DEBUG, message); nil != w {
+		w.DebugPretty(v)
+		writer.fire(timeCache.Now(), DEBUG, message)
+	}
+}
+
+// ErrorStack writes message with stack trace of the caller to its shard
+func (writer *ShardedWriter) ErrorStack(message string) {
+	if w := writer.shard(ERROR, message); nil != w {
+		w.ErrorStack(message)
+		writer.fire(timeCache.Now(), ERROR, message)
+	}
+}
+
+// InfoJSON writes fields as a json line with info level, the key is taken
+// from the json line
+func (writer *ShardedWriter) InfoJSON(fields map[string]interface{}) {
+	message := jsonFields(fields)
+	if w := writer.shard(INFO, message); nil != w {
+		w.InfoJSON(fields)
+		writer.fire(timeCache.Now(), INFO, message)
+	}
+}
+
+// WriteBatch writes messages of every shard contiguously to it, keeping their
+// order, and return total size written
+func (writer *ShardedWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	if 0 == len(writer.routed(level)) {
+		return 0
+	}
+
+	batches := make([][]string, len(writer.shards))
+	for _, message := range messages {
+		i := writer.Shard(message)
+		batches[i] = append(batches[i], message)
+	}
+
+	for i, batch := range batches {
+		if 0 == len(batch) {
+			continue
+		}
+
+		size += writer.shards[i].WriteBatch(level, batch)
+		for _, message := range batch {
+			writer.fire(timeCache.Now(), level, message)
+		}
+	}
+	return
+}
+
+// LogAt writes message prefixed with time t instead of now to its shard
+func (writer *ShardedWriter) LogAt(t time.Time, level LevelType, message string) {
+	if w := writer.shard(level, message); nil != w {
+		w.LogAt(t, level, message)
+		writer.fire(t, level, message)
+	}
+}
+
+// LogfAt formats message prefixed with time t instead of now to its shard
+func (writer *ShardedWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	message := writer.format.sprintf(format, args...)
+	if w := writer.shard(level, message); nil != w {
+		w.LogfAt(t, level, format, args...)
+		writer.fire(t, level, message)
+	}
+}
+
+// Raw writes message verbatim to its shard
+func (writer *ShardedWriter) Raw(message string) {
+	if w := writer.shard(RawLevel, message); nil != w {
+		w.Raw(message)
+		writer.fire(timeCache.Now(), RawLevel, message)
+	}
+}
+
+// InfoSync writes message with info level to its shard and flushes it
+func (writer *ShardedWriter) InfoSync(message string) {
+	if w := writer.shard(INFO, message); nil != w {
+		w.InfoSync(message)
+		writer.fire(timeCache.Now(), INFO, message)
+	}
+}
+
+// InfofSync formats message with info level to its shard and flushes it
+func (writer *ShardedWriter) InfofSync(format string, args ...interface{}) {
+	message := writer.format.sprintf(format, args...)
+	if w := writer.shard(INFO, message); nil != w {
+		w.InfofSync(format, args...)
+		writer.fire(timeCache.Now(), INFO, message)
+	}
+}
+
+// Entry starts building a structured message with specific level
+func (writer *ShardedWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
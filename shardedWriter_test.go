@@ -0,0 +1,63 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestShardedFileWriter(t *testing.T) {
+	// key is the user id leading messages
+	userID := func(message string) string {
+		return strings.SplitN(message, " ", 2)[0]
+	}
+
+	if _, err := NewShardedFileWriter("/tmp/shard", 0, userID); ErrInvalidShards != err {
+		t.Errorf("invalid shards not found. err: %v", err)
+	}
+
+	writer, err := NewShardedFileWriter("/tmp/shard", 4, userID)
+	if nil != err {
+		t.Fatalf("create sharded writer failed. err: %s", err.Error())
+	}
+	defer exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+
+	users := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7", "u8"}
+	for round := 0; round < 3; round++ {
+		for _, user := range users {
+			writer.Infof("%s round %d", user, round)
+		}
+	}
+	writer.Entry(INFO).Str("action", "login").Msg("u1 entry")
+	writer.Close()
+
+	shards := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		data, err := ioutil.ReadFile(ShardFileName("/tmp/shard", i))
+		if nil != err {
+			t.Fatalf("read shard failed. err: %s", err.Error())
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			if "" == line {
+				continue
+			}
+			user := userID(line[strings.Index(line, "[INFO] ")+len("[INFO] "):])
+			if shard, ok := shards[user]; ok && shard != i {
+				t.Errorf("same key should land in the same shard. user: %s, shards: %d, %d", user, shard, i)
+			}
+			shards[user] = i
+
+			if expected := writer.Shard(user); expected != i {
+				t.Errorf("line written to wrong shard. user: %s, expected: %d, got: %d", user, expected, i)
+			}
+		}
+	}
+
+	if len(users) != len(shards) {
+		t.Errorf("lines lost. got: %v", shards)
+	}
+}
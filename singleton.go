@@ -0,0 +1,96 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// singleton holds the instance use for blog().write/writef, it is read
+	// && swapped atomically so that logging never sees a partial state
+	singleton atomic.Value
+
+	// ReplaceGracePeriod is how long ReplaceSingleton waits before closing the
+	// old writer, letting values already holding it finish, such as Tx and
+	// Entry started with it
+	ReplaceGracePeriod = 1 * time.Second
+)
+
+// singletonHolder wraps the singleton, atomic.Value does not store nil
+type singletonHolder struct {
+	writer Writer
+
+	// logging calls in flight using writer
+	calls int64
+}
+
+// acquireSingleton get the holder of the singleton writer counting a
+// logging call in flight on it, release must be called once the call
+// returns. The holder is counted only if it is still the singleton after
+// counting, so that ReplaceSingleton never misses a call
+func acquireSingleton() *singletonHolder {
+	for {
+		holder, _ := singleton.Load().(*singletonHolder)
+		if nil == holder {
+			return &singletonHolder{}
+		}
+
+		atomic.AddInt64(&holder.calls, 1)
+		if holder == singleton.Load().(*singletonHolder) {
+			return holder
+		}
+		holder.release()
+	}
+}
+
+// release ends a logging call counted by acquireSingleton
+func (holder *singletonHolder) release() {
+	atomic.AddInt64(&holder.calls, -1)
+}
+
+// wait blocks until logging calls in flight on the holder return
+func (holder *singletonHolder) wait() {
+	for 0 < atomic.LoadInt64(&holder.calls) {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blog get the singleton writer, nil if not initialized
+func blog() Writer {
+	if holder, ok := singleton.Load().(*singletonHolder); ok {
+		return holder.writer
+	}
+	return nil
+}
+
+// setBlog set the singleton writer.
+// singltonLock must be held by the caller
+func setBlog(writer Writer) {
+	singleton.Store(&singletonHolder{writer: writer})
+}
+
+// ReplaceSingleton swaps the singleton writer with writer atomically for
+// reconfiguration without downtime, every logging call uses either the old
+// or the new one. The old one is closed after ReplaceGracePeriod, once
+// logging calls of package functions in flight on it have returned, and it
+// returns then. writer must not be nil
+func ReplaceSingleton(writer Writer) {
+	if nil == writer {
+		return
+	}
+
+	singltonLock.Lock()
+	old, _ := singleton.Load().(*singletonHolder)
+	setBlog(writer)
+	singltonLock.Unlock()
+
+	if nil == old || nil == old.writer || old.writer == writer {
+		return
+	}
+
+	time.Sleep(ReplaceGracePeriod)
+	old.wait()
+	old.writer.Close()
+}
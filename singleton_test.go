@@ -0,0 +1,208 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplaceSingleton(t *testing.T) {
+	singltonLock.Lock()
+	saved := blog()
+	oldSink, newSink := newMySink(), newMySink()
+	oldWriter := NewSinkWriter(oldSink)
+	setBlog(oldWriter)
+	singltonLock.Unlock()
+
+	defer func(grace time.Duration) {
+		ReplaceGracePeriod = grace
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}(ReplaceGracePeriod)
+	ReplaceGracePeriod = 100 * time.Millisecond
+
+	// log until stopped, lines written by each goroutine are counted
+	const goroutines = 4
+	var wg sync.WaitGroup
+	lines := make([]int, goroutines)
+	stop := make(chan bool)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				Infof("%d-%d", g, lines[g])
+				lines[g]++
+			}
+		}(g)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	newWriter := NewSinkWriter(newSink)
+	ReplaceSingleton(newWriter)
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	if newWriter != blog() {
+		t.Error("singleton not replaced")
+	}
+	oldSink.l.Lock()
+	if !oldSink.closed {
+		t.Error("old writer not closed after grace period")
+	}
+	oldSink.l.Unlock()
+
+	seen := make(map[string]int)
+	for _, sink := range []*mySink{oldSink, newSink} {
+		sink.l.Lock()
+		if 0 == len(sink.messages) {
+			t.Error("lines should be written to both writers")
+		}
+		for _, message := range sink.messages {
+			seen[message]++
+		}
+		sink.l.Unlock()
+	}
+
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < lines[g]; i++ {
+			if n := seen[fmt.Sprintf("%d-%d", g, i)]; 1 != n {
+				t.Fatalf("line %d-%d written %d times", g, i, n)
+			}
+		}
+	}
+	newWriter.Close()
+}
+
+func TestReplaceSingletonInFlight(t *testing.T) {
+	singltonLock.Lock()
+	saved := blog()
+	singltonLock.Unlock()
+
+	defer func(grace time.Duration) {
+		ReplaceGracePeriod = grace
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+
+		// clean logs
+		_, err := exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}(ReplaceGracePeriod)
+	// no grace period, calls in flight are waited for
+	ReplaceGracePeriod = 0
+
+	newWriter := func(i int) Writer {
+		writer, err := newBaseFileWriter(fmt.Sprintf("/tmp/replace%d.log", i), false)
+		if nil != err {
+			t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+		}
+		return writer
+	}
+	ReplaceSingleton(newWriter(0))
+
+	var wg sync.WaitGroup
+	stop := make(chan bool)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				Infof("%d-%d", g, i)
+				Warn("in flight")
+			}
+		}(g)
+	}
+
+	// closed writers are never written to
+	for i := 1; i <= 100; i++ {
+		ReplaceSingleton(newWriter(i))
+	}
+	close(stop)
+	wg.Wait()
+	blog().Close()
+}
+
+// blockingSink blocks Emit until released
+type blockingSink struct {
+	entered chan struct{}
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func (sink *blockingSink) Emit(t time.Time, level LevelType, message string) error {
+	close(sink.entered)
+	<-sink.release
+	return nil
+}
+
+func (sink *blockingSink) Flush() error {
+	return nil
+}
+
+func (sink *blockingSink) Close() error {
+	close(sink.closed)
+	return nil
+}
+
+func TestCloseInFlight(t *testing.T) {
+	singltonLock.Lock()
+	saved := blog()
+	sink := &blockingSink{entered: make(chan struct{}), release: make(chan struct{}), closed: make(chan struct{})}
+	setBlog(NewSinkWriter(sink))
+	singltonLock.Unlock()
+
+	defer func() {
+		singltonLock.Lock()
+		setBlog(saved)
+		singltonLock.Unlock()
+	}()
+
+	go Info("in flight")
+	<-sink.entered
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Close()
+	}()
+
+	// the singleton is removed at once, closed after the call returns
+	time.Sleep(20 * time.Millisecond)
+	if nil != blog() {
+		t.Error("singleton should be removed before waiting for calls in flight")
+	}
+	select {
+	case <-sink.closed:
+		t.Fatal("writer should not be closed while a call is in flight")
+	case <-done:
+		t.Fatal("Close should wait for calls in flight")
+	default:
+	}
+
+	close(sink.release)
+	<-done
+	select {
+	case <-sink.closed:
+	default:
+		t.Error("writer should be closed")
+	}
+}
@@ -0,0 +1,763 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Sink interface determines functions a destination should implement when it
+// receives formatted messages rather than bytes, such as log collecting
+// services. It lets packages outside blog4go offer writers, NewSinkWriter
+// wraps a Sink into a Writer.
+// Emit receives the message body without time and level prefix, the level
+// and the time associate with that logging action.
+type Sink interface {
+	Emit(t time.Time, level LevelType, message string) error
+	Flush() error
+	Close() error
+}
+
+// SinkWriter is a writer delivering every message to a Sink
+type SinkWriter struct {
+	name  string
+	level LevelType
+
+	closed bool
+
+	// stack traces written by ErrorStack
+	stacks stackDedup
+
+	// log hook
+	hook      Hook
+	hookLevel LevelType
+	hookAsync bool
+
+	// sink
+	sink Sink
+
+	// encoder used in InfoJSON
+	json *jsonEncoder
+
+	// formats messages of writef functions with the parser of BLog
+	format *BLog
+
+	// static fields written ahead every message
+	defaults defaultFields
+
+	// rules dropping messages, nil if none
+	drops *dropRules
+
+	// errorHandler is called when sink fails to emit a message
+	errorHandler ErrorHandler
+
+	lock *sync.Mutex
+}
+
+// NewSinkWriter creates a writer delivering messages to sink, not singlton
+func NewSinkWriter(sink Sink) (sinkWriter *SinkWriter) {
+	sinkWriter = new(SinkWriter)
+	sinkWriter.name = "sink"
+	sinkWriter.level = DEBUG
+	sinkWriter.closed = false
+	sinkWriter.lock = new(sync.Mutex)
+
+	// log hook
+	sinkWriter.hook = nil
+	sinkWriter.hookLevel = DEBUG
+	sinkWriter.hookAsync = true
+
+	sinkWriter.sink = sink
+	sinkWriter.json = newJSONEncoder()
+	sinkWriter.format = newFormatBLog()
+	return sinkWriter
+}
+
+// emit delivers message to sink and calls hook
+func (writer *SinkWriter) emit(level LevelType, message string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.emitLocked(timeCache.Now(), level, message)
+}
+
+// emitLocked delivers message with time t to sink and return false if it is
+// not delivered. writer.lock must be held by the caller
+func (writer *SinkWriter) emitLocked(t time.Time, level LevelType, message string) bool {
+	if writer.closed || writer.drops.drop([]byte(message)) {
+		return false
+	}
+
+	defer func() {
+		// call log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, message)
+		}
+	}()
+
+	if err := writer.sink.Emit(t, level, message); nil != err && nil != writer.errorHandler {
+		writer.errorHandler(err)
+	}
+	return true
+}
+
+func (writer *SinkWriter) write(level LevelType, args ...interface{}) {
+	writer.emit(level, writer.defaults.prefix+fmt.Sprint(args...))
+}
+
+func (writer *SinkWriter) writef(level LevelType, format string, args ...interface{}) {
+	writer.emit(level, writer.defaults.prefix+writer.format.sprintf(format, args...))
+}
+
+// emitJSON delivers fields encoded as json without EOL to sink
+func (writer *SinkWriter) emitJSON(level LevelType, fields map[string]interface{}) {
+	writer.lock.Lock()
+	if writer.closed {
+		writer.lock.Unlock()
+		return
+	}
+	line := writer.json.encode(timeCache.Now(), level, fields)
+	message := string(line[:len(line)-1])
+	writer.lock.Unlock()
+
+	writer.emit(level, message)
+}
+
+// Sink return the sink messages delivered to
+func (writer *SinkWriter) Sink() Sink {
+	return writer.sink
+}
+
+// Level get level
+func (writer *SinkWriter) Level() LevelType {
+	return writer.level
+}
+
+// Name get name of the writer, "sink" by default
+func (writer *SinkWriter) Name() string {
+	return writer.name
+}
+
+// SetName set name of the writer
+func (writer *SinkWriter) SetName(name string) {
+	writer.name = name
+}
+
+// SetLevel set logger level
+func (writer *SinkWriter) SetLevel(level LevelType) Writer {
+	writer.level = level
+	return writer
+}
+
+// SetHook set hook for logging action
+func (writer *SinkWriter) SetHook(hook Hook) {
+	writer.hook = hook
+}
+
+// SetHookAsync set hook async for sink writer
+func (writer *SinkWriter) SetHookAsync(async bool) {
+	writer.hookAsync = async
+}
+
+// SetHookLevel set when hook will be called
+func (writer *SinkWriter) SetHookLevel(level LevelType) {
+	writer.hookLevel = level
+}
+
+// TimeRotated do nothing
+func (writer *SinkWriter) TimeRotated() bool {
+	return false
+}
+
+// SetTimeRotated do nothing
+func (writer *SinkWriter) SetTimeRotated(timeRotated bool) {
+	return
+}
+
+// Retentions do nothing
+func (writer *SinkWriter) Retentions() int64 {
+	return 0
+}
+
+// SetRetentions do nothing
+func (writer *SinkWriter) SetRetentions(retentions int64) {
+	return
+}
+
+// Rotate do nothing
+func (writer *SinkWriter) Rotate() error {
+	return nil
+}
+
+// MaxTotalSize do nothing
+func (writer *SinkWriter) MaxTotalSize() int64 {
+	return 0
+}
+
+// SetMaxTotalSize do nothing
+func (writer *SinkWriter) SetMaxTotalSize(maxTotalSize int64) {
+	return
+}
+
+// SetRotateHook do nothing
+func (writer *SinkWriter) SetRotateHook(hook RotateHook) {
+	return
+}
+
+// FileMode do nothing
+func (writer *SinkWriter) FileMode() os.FileMode {
+	return 0
+}
+
+// SetFileMode do nothing
+func (writer *SinkWriter) SetFileMode(mode os.FileMode) error {
+	return nil
+}
+
+// SetFileOwner do nothing
+func (writer *SinkWriter) SetFileOwner(uid, gid int) error {
+	return nil
+}
+
+// SetOpenBanner do nothing
+func (writer *SinkWriter) SetOpenBanner(banner bool) {
+	return
+}
+
+// RotateSize do nothing
+func (writer *SinkWriter) RotateSize() int64 {
+	return 0
+}
+
+// SetRotateSize do nothing
+func (writer *SinkWriter) SetRotateSize(rotateSize int64) {
+	return
+}
+
+// RotateLines do nothing
+func (writer *SinkWriter) RotateLines() int {
+	return 0
+}
+
+// SetRotateLines do nothing
+func (writer *SinkWriter) SetRotateLines(rotateLines int) {
+	return
+}
+
+// Colored do nothing
+func (writer *SinkWriter) Colored() bool {
+	return false
+}
+
+// SetColored do nothing
+func (writer *SinkWriter) SetColored(colored bool) {
+	return
+}
+
+// StrictFormat get whether it is in strict format mode
+func (writer *SinkWriter) StrictFormat() bool {
+	return writer.format.StrictFormat()
+}
+
+// SetStrictFormat toggle strict format mode, mistakes are reported to error
+// handler
+func (writer *SinkWriter) SetStrictFormat(strict bool) {
+	writer.format.SetStrictFormat(strict)
+}
+
+// SetErrorHandler set handler called when sink fails to emit a message
+func (writer *SinkWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.errorHandler = handler
+	writer.format.SetErrorHandler(handler)
+}
+
+// SetWriteTimeout do nothing
+func (writer *SinkWriter) SetWriteTimeout(d time.Duration) {
+	return
+}
+
+// Placeholder get placeholder character used in formatting
+func (writer *SinkWriter) Placeholder() byte {
+	return writer.format.Placeholder()
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func (writer *SinkWriter) SetPlaceholder(placeholder byte) {
+	writer.format.SetPlaceholder(placeholder)
+}
+
+// SetEOL is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetEOL(eol byte) {
+	unsupported(writer.name, "SetEOL")
+}
+
+// SetEscape set escape character used in formatting
+func (writer *SinkWriter) SetEscape(escape byte) {
+	writer.format.SetEscape(escape)
+}
+
+// SetNilString set the token nil args are written as in formatting
+func (writer *SinkWriter) SetNilString(s string) {
+	writer.format.SetNilString(s)
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with
+// recover
+func (writer *SinkWriter) SetSafeStringer(safe bool) {
+	writer.format.SetSafeStringer(safe)
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args
+func (writer *SinkWriter) SetRawStringer(raw bool) {
+	writer.format.SetRawStringer(raw)
+}
+
+// SetDebounce is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetDebounce(format string, window time.Duration) {
+	unsupported(writer.name, "SetDebounce")
+}
+
+// SetSamplerPolicy is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	unsupported(writer.name, "SetSamplerPolicy")
+}
+
+// SetPrintSequence is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetPrintSequence(sequence bool) {
+	unsupported(writer.name, "SetPrintSequence")
+}
+
+// SetPrintElapsed is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetPrintElapsed(elapsed bool) {
+	unsupported(writer.name, "SetPrintElapsed")
+}
+
+// SetSequenceWidth is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetSequenceWidth(width int) {
+	unsupported(writer.name, "SetSequenceWidth")
+}
+
+// SetDefaultFields sets static fields prepended to every message
+func (writer *SinkWriter) SetDefaultFields(fields map[string]string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.set(fields)
+}
+
+// AddDefaultField appends a static field prepended to every message
+func (writer *SinkWriter) AddDefaultField(key, value string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.add(key, value)
+}
+
+// separators return separators in use
+func (writer *SinkWriter) separators() *fieldSeparator {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.separator()
+}
+
+// SetFieldSeparator set what is written between structured fields
+func (writer *SinkWriter) SetFieldSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.setFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields
+func (writer *SinkWriter) SetKVSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.setKVSeparator(sep)
+}
+
+// SetPrintHostname toggle writing hostname ahead every message
+func (writer *SinkWriter) SetPrintHostname(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintHost(print)
+}
+
+// SetHostname overrides hostname written ahead messages
+func (writer *SinkWriter) SetHostname(name string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setHost(name)
+}
+
+// SetPrintPID toggle writing process id ahead every message
+func (writer *SinkWriter) SetPrintPID(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintPID(print)
+}
+
+// SetPrintProgram toggle writing program name ahead every message
+func (writer *SinkWriter) SetPrintProgram(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintProgram(print)
+}
+
+// SetPrintCallerFunc is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetPrintCallerFunc(print bool) {
+	unsupported(writer.name, "SetPrintCallerFunc")
+}
+
+// SetPrintCallerLine is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetPrintCallerLine(print bool) {
+	unsupported(writer.name, "SetPrintCallerLine")
+}
+
+// AddDropSubstring drops messages containing s
+func (writer *SinkWriter) AddDropSubstring(s string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if nil == writer.drops {
+		writer.drops = new(dropRules)
+	}
+	writer.drops.addSubstring(s)
+}
+
+// AddDropRegexp drops messages matching re
+func (writer *SinkWriter) AddDropRegexp(re *regexp.Regexp) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if nil == writer.drops {
+		writer.drops = new(dropRules)
+	}
+	writer.drops.addRegexp(re)
+}
+
+// Dropped return number of messages dropped by drop rules
+func (writer *SinkWriter) Dropped() int64 {
+	return writer.drops.count()
+}
+
+// SetTimeFormat is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetTimeFormat(layout string) {
+	unsupported(writer.name, "SetTimeFormat")
+}
+
+// SetTimeFormatPreset is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetTimeFormatPreset(preset TimePreset) {
+	unsupported(writer.name, "SetTimeFormatPreset")
+}
+
+// SetFraming is not supported, messages are delivered with their level.
+// calling it is reported as an internal error
+func (writer *SinkWriter) SetFraming(framing Framing) {
+	unsupported(writer.name, "SetFraming")
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly
+func (writer *SinkWriter) SetSmartTimeVerb(smart bool) {
+	writer.format.SetSmartTimeVerb(smart)
+}
+
+// SetFormatter is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetFormatter(formatter Formatter) {
+	unsupported(writer.name, "SetFormatter")
+}
+
+// FlushLevel always TRACE, every message is delivered to sink immediately
+func (writer *SinkWriter) FlushLevel() LevelType {
+	return TRACE
+}
+
+// SetFlushLevel do nothing
+func (writer *SinkWriter) SetFlushLevel(level LevelType) {
+	return
+}
+
+// SetBufferSize do nothing
+func (writer *SinkWriter) SetBufferSize(size int) {
+	return
+}
+
+// AtomicWrite always true, every message is delivered to sink as a whole
+func (writer *SinkWriter) AtomicWrite() bool {
+	return true
+}
+
+// SetAtomicWrite do nothing
+func (writer *SinkWriter) SetAtomicWrite(atomic bool) {
+	return
+}
+
+// SetReorderWindow is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetReorderWindow(d time.Duration) {
+	unsupported(writer.name, "SetReorderWindow")
+}
+
+// SetDevMode is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetDevMode(dev bool) {
+	unsupported(writer.name, "SetDevMode")
+}
+
+// MultilinePrefix do nothing
+func (writer *SinkWriter) MultilinePrefix() bool {
+	return false
+}
+
+// SetMultilinePrefix is not supported, calling it is reported as an internal error
+func (writer *SinkWriter) SetMultilinePrefix(multiline bool) {
+	unsupported(writer.name, "SetMultilinePrefix")
+}
+
+// Close will close the writer and the sink
+func (writer *SinkWriter) Close() {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return
+	}
+
+	writer.sink.Flush()
+	writer.sink.Close()
+	writer.closed = true
+}
+
+// Drain flushes the sink
+func (writer *SinkWriter) Drain() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	return writer.sink.Flush()
+}
+
+// Ping flushes the sink, reporting whether it accepts messages
+func (writer *SinkWriter) Ping() error {
+	return writer.Drain()
+}
+
+// flush flush sink
+func (writer *SinkWriter) flush() {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return
+	}
+
+	writer.sink.Flush()
+}
+
+// Trace trace
+func (writer *SinkWriter) Trace(args ...interface{}) {
+	if TRACE < CompileLevel || TRACE < writer.level {
+		return
+	}
+
+	writer.write(TRACE, args...)
+}
+
+// Tracef tracef
+func (writer *SinkWriter) Tracef(format string, args ...interface{}) {
+	if TRACE < CompileLevel || TRACE < writer.level {
+		return
+	}
+
+	writer.writef(TRACE, format, args...)
+}
+
+// Debug debug
+func (writer *SinkWriter) Debug(args ...interface{}) {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
+		return
+	}
+
+	writer.write(DEBUG, args...)
+}
+
+// Debugf debugf
+func (writer *SinkWriter) Debugf(format string, args ...interface{}) {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
+		return
+	}
+
+	writer.writef(DEBUG, format, args...)
+}
+
+// DebugPretty debug pretty
+func (writer *SinkWriter) DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
+		return
+	}
+
+	writer.emit(DEBUG, prettyFormat(v))
+}
+
+// ErrorStack delivers message with stack trace of the caller, a stack
+// delivered before is delivered as its fingerprint
+func (writer *SinkWriter) ErrorStack(message string) {
+	if ERROR < CompileLevel || ERROR < writer.level {
+		return
+	}
+
+	writer.emit(ERROR, writer.stacks.message(message))
+}
+
+// Entry starts building a structured message with specific level
+func (writer *SinkWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
+// Info info
+func (writer *SinkWriter) Info(args ...interface{}) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.write(INFO, args...)
+}
+
+// InfoJSON delivers fields as a json message with info level
+func (writer *SinkWriter) InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.emitJSON(INFO, fields)
+}
+
+// WriteBatch delivers messages with specific level in order, taking the lock
+// once. It return total size of messages delivered
+func (writer *SinkWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	if level < CompileLevel || !level.valid() || level < writer.level {
+		return 0
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	for _, message := range messages {
+		message = writer.defaults.prefix + message
+		if writer.emitLocked(timeCache.Now(), level, message) {
+			size += len(message)
+		}
+	}
+	return
+}
+
+// LogAt delivers message with specific level and time t instead of now
+func (writer *SinkWriter) LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel || !level.valid() || level < writer.level {
+		return
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.emitLocked(t, level, writer.defaults.prefix+message)
+}
+
+// LogfAt formats message with specific level and delivers it with time t
+// instead of now
+func (writer *SinkWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	writer.LogAt(t, level, writer.format.sprintf(format, args...))
+}
+
+// Raw delivers message as it is with RawLevel, without default fields
+func (writer *SinkWriter) Raw(message string) {
+	if RawLevel < CompileLevel || RawLevel < writer.level {
+		return
+	}
+
+	writer.emit(RawLevel, message)
+}
+
+// Infof infof
+func (writer *SinkWriter) Infof(format string, args ...interface{}) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.writef(INFO, format, args...)
+}
+
+// InfoSync delivers message with info level and flushes the sink
+func (writer *SinkWriter) InfoSync(message string) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.write(INFO, message)
+	writer.flush()
+}
+
+// InfofSync delivers formatted message with info level and flushes the sink
+func (writer *SinkWriter) InfofSync(format string, args ...interface{}) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.writef(INFO, format, args...)
+	writer.flush()
+}
+
+// Warn warn
+func (writer *SinkWriter) Warn(args ...interface{}) {
+	if WARNING < CompileLevel || WARNING < writer.level {
+		return
+	}
+
+	writer.write(WARNING, args...)
+}
+
+// Warnf warnf
+func (writer *SinkWriter) Warnf(format string, args ...interface{}) {
+	if WARNING < CompileLevel || WARNING < writer.level {
+		return
+	}
+
+	writer.writef(WARNING, format, args...)
+}
+
+// Error error
+func (writer *SinkWriter) Error(args ...interface{}) {
+	if ERROR < CompileLevel || ERROR < writer.level {
+		return
+	}
+
+	writer.write(ERROR, args...)
+}
+
+// Errorf error
+func (writer *SinkWriter) Errorf(format string, args ...interface{}) {
+	if ERROR < CompileLevel || ERROR < writer.level {
+		return
+	}
+
+	writer.writef(ERROR, format, args...)
+}
+
+// Critical critical
+func (writer *SinkWriter) Critical(args ...interface{}) {
+	if CRITICAL < CompileLevel || CRITICAL < writer.level {
+		return
+	}
+
+	writer.write(CRITICAL, args...)
+}
+
+// Criticalf criticalf
+func (writer *SinkWriter) Criticalf(format string, args ...interface{}) {
+	if CRITICAL < CompileLevel || CRITICAL < writer.level {
+		return
+	}
+
+	writer.writef(CRITICAL, format, args...)
+}
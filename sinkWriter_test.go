@@ -0,0 +1,136 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mySink struct {
+	levels   []LevelType
+	messages []string
+	flushed  int
+	closed   bool
+
+	l *sync.Mutex
+}
+
+func newMySink() *mySink {
+	return &mySink{l: new(sync.Mutex)}
+}
+
+func (sink *mySink) Emit(t time.Time, level LevelType, message string) error {
+	sink.l.Lock()
+	defer sink.l.Unlock()
+	sink.levels = append(sink.levels, level)
+	sink.messages = append(sink.messages, message)
+	return nil
+}
+
+func (sink *mySink) Flush() error {
+	sink.l.Lock()
+	defer sink.l.Unlock()
+	sink.flushed++
+	return nil
+}
+
+func (sink *mySink) Close() error {
+	sink.l.Lock()
+	defer sink.l.Unlock()
+	sink.closed = true
+	return nil
+}
+
+func TestSinkWriterBasicOperation(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+
+	hook := NewMyHook()
+	writer.SetHook(hook)
+	writer.SetHookLevel(INFO)
+	writer.SetHookAsync(false)
+
+	writer.SetLevel(INFO)
+	writer.Debug("dropped")
+	writer.Info("Info", 3)
+	writer.Warnf("%s %d", "Warn", 4)
+
+	if 2 != len(sink.messages) {
+		t.Fatalf("sink messages count wrong. count: %d", len(sink.messages))
+	}
+
+	if INFO != sink.levels[0] || "Info3" != sink.messages[0] {
+		t.Errorf("sink message wrong. level: %s, message: %s", sink.levels[0].String(), sink.messages[0])
+	}
+
+	if WARNING != sink.levels[1] || "Warn 4" != sink.messages[1] {
+		t.Errorf("sink message wrong. level: %s, message: %s", sink.levels[1].String(), sink.messages[1])
+	}
+
+	if 2 != hook.Cnt() || "Warn 4" != hook.Message() {
+		t.Errorf("hook parameters wrong. cnt: %d, message: %s", hook.Cnt(), hook.Message())
+	}
+
+	writer.flush()
+	writer.Close()
+	if 2 != sink.flushed || !sink.closed {
+		t.Errorf("sink not flushed or closed. flushed: %d, closed: %t", sink.flushed, sink.closed)
+	}
+
+	// closed
+	writer.Error("dropped")
+	if 2 != len(sink.messages) {
+		t.Error("closed writer should not emit messages.")
+	}
+}
+
+func TestSinkWriterPlaceholder(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetPlaceholder('@')
+	writer.SetNilString("-")
+
+	// format as a variable, placeholder is not known to vet
+	format := "name LIKE '%foo%' AND id = @d AND owner = @v"
+	writer.Infof(format, 1, nil)
+
+	// Tx formats by the writer at commit
+	tx := NewTx(writer)
+	tx.Warnf(format, 2, nil)
+	tx.Commit()
+
+	expected := "name LIKE '%foo%' AND id = 1 AND owner = -"
+	if 2 != len(sink.messages) || expected != sink.messages[0] {
+		t.Fatalf("placeholder of sink writer not applied. got: %q", sink.messages)
+	}
+	if "name LIKE '%foo%' AND id = 2 AND owner = -" != sink.messages[1] {
+		t.Errorf("placeholder of writer not applied to Tx. got: %q", sink.messages[1])
+	}
+}
+
+func TestSinkWriterUnsupported(t *testing.T) {
+	buf := new(bytes.Buffer)
+	SetInternalErrorWriter(buf)
+	defer SetInternalErrorWriter(os.Stderr)
+
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetName("audit")
+	writer.SetMultilinePrefix(true)
+	writer.SetFormatter(JSONFormatter)
+
+	if !strings.Contains(buf.String(), " blog4go: SetMultilinePrefix is not supported by writer \"audit\", ignored") ||
+		!strings.Contains(buf.String(), " blog4go: SetFormatter is not supported by writer \"audit\", ignored") {
+		t.Errorf("unsupported settings should be reported. content: %s", buf.String())
+	}
+
+	writer.Info("plain")
+	if 1 != len(sink.messages) || "plain" != sink.messages[0] {
+		t.Errorf("message should be delivered as it is. got: %q", sink.messages)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"io"
+)
+
+// snapshotWriter is the input io of bufio.Writer in snapshot mode. bytes
+// flushed by bufio.Writer are dropped from the head of shadow buffer, so that
+// shadow buffer always keeps what is still buffered
+type snapshotWriter struct {
+	in     io.Writer
+	shadow *bytes.Buffer
+}
+
+func (w *snapshotWriter) Write(p []byte) (int, error) {
+	n, err := w.in.Write(p)
+	w.shadow.Next(n)
+	return n, err
+}
+
+// teeLineWriter copies a line into shadow buffer before buffering it
+type teeLineWriter struct {
+	shadow *bytes.Buffer
+	writer lineWriter
+}
+
+func (w *teeLineWriter) Write(p []byte) (int, error) {
+	w.shadow.Write(p)
+	return w.writer.Write(p)
+}
+
+func (w *teeLineWriter) WriteString(s string) (int, error) {
+	w.shadow.WriteString(s)
+	return w.writer.WriteString(s)
+}
+
+func (w *teeLineWriter) WriteByte(c byte) error {
+	w.shadow.WriteByte(c)
+	return w.writer.WriteByte(c)
+}
+
+// SetSnapshot toggle snapshot mode. bufio.Writer does not expose buffered
+// bytes, in snapshot mode BLog keeps a shadow copy of them for Snapshot.
+// It costs a copy of every line, use it in tests only
+func (blog *BLog) SetSnapshot(snapshot bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if snapshot == (nil != blog.shadow) || nil == blog.writer {
+		return blog
+	}
+
+	blog.writer.Flush()
+	if snapshot {
+		blog.shadow = new(bytes.Buffer)
+		blog.tee = &teeLineWriter{shadow: blog.shadow, writer: blog.writer}
+	} else {
+		blog.shadow = nil
+		blog.tee = nil
+	}
+	blog.relay.w = blog.bufferedIn()
+	blog.writer.Reset(blog.relay)
+	return blog
+}
+
+// Snapshot return a copy of bytes written but not flushed yet, without
+// flushing them. It works in snapshot mode only and return nil otherwise.
+// Lines written in atomic mode are never buffered
+func (blog *BLog) Snapshot() []byte {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if nil == blog.shadow {
+		return nil
+	}
+
+	return append([]byte(nil), blog.shadow.Bytes()...)
+}
+
+// bufferedIn return input io of bufio.Writer
+func (blog *BLog) bufferedIn() io.Writer {
+	if nil != blog.shadow {
+		return &snapshotWriter{in: blog.in, shadow: blog.shadow}
+	}
+	return blog.in
+}
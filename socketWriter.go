@@ -6,22 +6,65 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"os"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // SocketWriter is a socket logger
 type SocketWriter struct {
+	// writes failed, accessed atomically, keep it first for 64-bit alignment
+	sendErrors int64
+
+	name string
+
 	level LevelType
 
 	closed bool
 
+	// stack traces written by ErrorStack
+	stacks stackDedup
+
 	// log hook
 	hook      Hook
 	hookLevel LevelType
 	hookAsync bool
 
-	// socket
-	writer net.Conn
+	// socket, and where it is dialed to. writer dialed by address is
+	// reconnected when a write times out or a reconnect failed
+	writer  net.Conn
+	network string
+	address string
+	broken  bool
+	// every write is bounded by timeout, 0 means no limit
+	timeout time.Duration
+	// lines longer than maxPacket bytes are truncated, 0 means no limit
+	maxPacket int
+
+	// encoder used in writeJSON
+	json *jsonEncoder
+
+	// formats messages of writef functions with the parser of BLog
+	format *BLog
+
+	// static fields written ahead every message
+	defaults defaultFields
+
+	// rules dropping messages, nil if none
+	drops *dropRules
+
+	// failed writes are retried by retry, nil means no retry. errorHandler
+	// is called when a line is failed to be sent at last
+	retry        *retryPolicy
+	errorHandler ErrorHandler
+
+	// lines failed to be sent are written to fallback, nil if none.
+	// failing tells whether the last line is failed to be sent
+	fallback Writer
+	failing  bool
 
 	lock *sync.Mutex
 }
@@ -30,7 +73,7 @@ type SocketWriter struct {
 func NewSocketWriter(network string, address string) (err error) {
 	singltonLock.Lock()
 	defer singltonLock.Unlock()
-	if nil != blog {
+	if nil != blog() {
 		return ErrAlreadyInit
 	}
 
@@ -39,29 +82,125 @@ func NewSocketWriter(network string, address string) (err error) {
 		return err
 	}
 
-	blog = socketWriter
+	setBlog(socketWriter)
 	return nil
 }
 
 // newSocketWriter creates a socket writer, not singlton
 func newSocketWriter(network string, address string) (socketWriter *SocketWriter, err error) {
+	conn, err := net.Dial(network, address)
+	if nil != err {
+		return nil, err
+	}
+
+	socketWriter = newConnWriter(conn)
+	socketWriter.network = network
+	socketWriter.address = address
+	setBlog(socketWriter)
+	return socketWriter, nil
+}
+
+// newConnWriter creates a socket writer writing to conn
+func newConnWriter(conn net.Conn) (socketWriter *SocketWriter) {
 	socketWriter = new(SocketWriter)
 	socketWriter.level = DEBUG
 	socketWriter.closed = false
 	socketWriter.lock = new(sync.Mutex)
+	socketWriter.format = newFormatBLog()
 
 	// log hook
 	socketWriter.hook = nil
 	socketWriter.hookLevel = DEBUG
 
-	conn, err := net.Dial(network, address)
+	socketWriter.writer = conn
+	if addr := conn.RemoteAddr(); nil != addr {
+		socketWriter.name = addr.String()
+	}
+	socketWriter.maxPacket = 0
+	socketWriter.json = newJSONEncoder()
+	return socketWriter
+}
+
+// send writes p to socket, truncated to maxPacket bytes if limited. p is
+// written to fallback writer if it is failed to be sent
+func (writer *SocketWriter) send(p []byte) {
+	if err := writer.deliver(p); nil != err && nil != writer.fallback {
+		writer.fallback.Raw(strings.TrimSuffix(string(p), string(EOL)))
+	}
+}
+
+// deliver writes p to socket, retried by retry policy, and return the error
+// if it is failed at last
+func (writer *SocketWriter) deliver(p []byte) error {
+	if 0 < writer.maxPacket && len(p) > writer.maxPacket {
+		p = p[:writer.maxPacket]
+	}
+
+	line := p
+	deliver := func() error {
+		if 0 < writer.timeout {
+			writer.writer.SetWriteDeadline(time.Now().Add(writer.timeout))
+		}
+
+		// bytes sent are not sent again
+		n, err := writer.writer.Write(p)
+		p = p[n:]
+
+		if ne, ok := err.(net.Error); (ok && ne.Timeout()) || writer.broken {
+			// 新连接上重新发送整行
+			if writer.reconnect() {
+				p = line
+			}
+		}
+		return err
+	}
+
+	var err error
+	if nil == writer.retry {
+		err = deliver()
+	} else {
+		err = writer.retry.do(deliver)
+	}
+
+	writer.failing = nil != err
 	if nil != err {
-		return nil, err
+		atomic.AddInt64(&writer.sendErrors, 1)
+		if nil != writer.errorHandler {
+			writer.errorHandler(err)
+		}
 	}
-	socketWriter.writer = conn
+	return err
+}
 
-	blog = socketWriter
-	return socketWriter, nil
+// reconnect replaces the connection with a new one dialed to address,
+// it return whether the connection is replaced
+func (writer *SocketWriter) reconnect() bool {
+	if "" == writer.address {
+		return false
+	}
+
+	var conn net.Conn
+	var err error
+	if 0 < writer.timeout {
+		conn, err = net.DialTimeout(writer.network, writer.address, writer.timeout)
+	} else {
+		conn, err = net.Dial(writer.network, writer.address)
+	}
+
+	writer.broken = nil != err
+	if writer.broken {
+		internalError("reconnect %s failed: %s", writer.address, err.Error())
+		return false
+	}
+
+	writer.writer.Close()
+	writer.writer = conn
+	return true
+}
+
+// SendErrors return number of lines failed to be sent
+func (writer *SocketWriter) SendErrors() int64 {
+	return atomic.LoadInt64(&writer.sendErrors)
 }
 
 func (writer *SocketWriter) write(level LevelType, args ...interface{}) {
@@ -72,25 +211,23 @@ func (writer *SocketWriter) write(level LevelType, args ...interface{}) {
 		return
 	}
 
+	message := fmt.Sprint(args...)
+	if writer.drops.drop([]byte(message)) {
+		return
+	}
+
 	defer func() {
 		// call log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, args ...interface{}) {
-					writer.hook.Fire(level, args...)
-				}(level, args...)
-
-			} else {
-				writer.hook.Fire(level, args...)
-
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, fmt.Sprint(args...))
 		}
 	}()
 
 	buffer := bytes.NewBuffer(timeCache.Format())
 	buffer.WriteString(level.prefix())
-	buffer.WriteString(fmt.Sprint(args...))
-	writer.writer.Write(buffer.Bytes())
+	buffer.WriteString(writer.defaults.prefix)
+	buffer.WriteString(message)
+	writer.send(buffer.Bytes())
 }
 
 func (writer *SocketWriter) writef(level LevelType, format string, args ...interface{}) {
@@ -101,25 +238,101 @@ func (writer *SocketWriter) writef(level LevelType, format string, args ...inter
 		return
 	}
 
+	message := writer.format.sprintf(format, args...)
+	if writer.drops.drop([]byte(message)) {
+		return
+	}
+
 	defer func() {
 
 		// call log hook
 		if nil != writer.hook && !(level < writer.hookLevel) {
-			if writer.hookAsync {
-				go func(level LevelType, format string, args ...interface{}) {
-					writer.hook.Fire(level, fmt.Sprintf(format, args...))
-				}(level, format, args...)
-
-			} else {
-				writer.hook.Fire(level, fmt.Sprintf(format, args...))
-			}
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
 		}
 	}()
 
 	buffer := bytes.NewBuffer(timeCache.Format())
 	buffer.WriteString(level.prefix())
-	buffer.WriteString(fmt.Sprintf(format, args...))
-	writer.writer.Write(buffer.Bytes())
+	buffer.WriteString(writer.defaults.prefix)
+	buffer.WriteString(message)
+	writer.send(buffer.Bytes())
+}
+
+func (writer *SocketWriter) writeLines(level LevelType, message string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed || writer.drops.drop([]byte(message)) {
+		return
+	}
+
+	defer func() {
+		// call log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+		}
+	}()
+
+	buffer := new(bytes.Buffer)
+	for _, line := range strings.Split(message, string(EOL)) {
+		buffer.Write(timeCache.Format())
+		buffer.WriteString(level.prefix())
+		buffer.WriteString(writer.defaults.prefix)
+		buffer.WriteString(line)
+		buffer.WriteByte(EOL)
+
+		// every line is a packet when packet size is limited
+		if 0 < writer.maxPacket {
+			writer.send(buffer.Bytes())
+			buffer.Reset()
+		}
+	}
+
+	if 0 < buffer.Len() {
+		writer.send(buffer.Bytes())
+	}
+}
+
+func (writer *SocketWriter) writeJSON(level LevelType, fields map[string]interface{}) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return
+	}
+
+	line := writer.json.encode(timeCache.Now(), level, fields)
+	if writer.drops.drop(bytes.TrimSuffix(line, []byte{EOL})) {
+		return
+	}
+
+	defer func() {
+		// call log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, jsonFields(fields))
+		}
+	}()
+
+	writer.send(line)
+}
+
+// writeRaw sends message verbatim with RawLevel
+func (writer *SocketWriter) writeRaw(message string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed || writer.drops.drop([]byte(message)) {
+		return
+	}
+
+	defer func() {
+		// call log hook
+		if nil != writer.hook && !(RawLevel < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), RawLevel, message)
+		}
+	}()
+
+	writer.send([]byte(message))
 }
 
 // Level get level
@@ -127,9 +340,20 @@ func (writer *SocketWriter) Level() LevelType {
 	return writer.level
 }
 
+// Name get name of the writer, remote address by default
+func (writer *SocketWriter) Name() string {
+	return writer.name
+}
+
+// SetName set name of the writer
+func (writer *SocketWriter) SetName(name string) {
+	writer.name = name
+}
+
 // SetLevel set logger level
-func (writer *SocketWriter) SetLevel(level LevelType) {
+func (writer *SocketWriter) SetLevel(level LevelType) Writer {
 	writer.level = level
+	return writer
 }
 
 // SetHook set hook for logging action
@@ -167,6 +391,46 @@ func (writer *SocketWriter) SetRetentions(retentions int64) {
 	return
 }
 
+// Rotate do nothing
+func (writer *SocketWriter) Rotate() error {
+	return nil
+}
+
+// MaxTotalSize do nothing
+func (writer *SocketWriter) MaxTotalSize() int64 {
+	return 0
+}
+
+// SetMaxTotalSize do nothing
+func (writer *SocketWriter) SetMaxTotalSize(maxTotalSize int64) {
+	return
+}
+
+// SetRotateHook do nothing
+func (writer *SocketWriter) SetRotateHook(hook RotateHook) {
+	return
+}
+
+// FileMode do nothing
+func (writer *SocketWriter) FileMode() os.FileMode {
+	return 0
+}
+
+// SetFileMode do nothing
+func (writer *SocketWriter) SetFileMode(mode os.FileMode) error {
+	return nil
+}
+
+// SetFileOwner do nothing
+func (writer *SocketWriter) SetFileOwner(uid, gid int) error {
+	return nil
+}
+
+// SetOpenBanner do nothing
+func (writer *SocketWriter) SetOpenBanner(banner bool) {
+	return
+}
+
 // RotateSize do nothing
 func (writer *SocketWriter) RotateSize() int64 {
 	return 0
@@ -197,6 +461,272 @@ func (writer *SocketWriter) SetColored(colored bool) {
 	return
 }
 
+// StrictFormat get whether it is in strict format mode
+func (writer *SocketWriter) StrictFormat() bool {
+	return writer.format.StrictFormat()
+}
+
+// SetStrictFormat toggle strict format mode, mistakes are reported to error
+// handler
+func (writer *SocketWriter) SetStrictFormat(strict bool) {
+	writer.format.SetStrictFormat(strict)
+}
+
+// SetErrorHandler set handler called when a line is failed to be sent
+func (writer *SocketWriter) SetErrorHandler(handler ErrorHandler) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.errorHandler = handler
+	writer.format.SetErrorHandler(handler)
+}
+
+// SetWriteTimeout bound every write to socket with d, so that a stalled
+// collector does not block callers. a write timed out is abandoned and
+// reported to error handler, and the socket is reconnected. d <= 0 means
+// no limit
+func (writer *SocketWriter) SetWriteTimeout(d time.Duration) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if d < 0 {
+		d = 0
+	}
+	writer.timeout = d
+}
+
+// Placeholder get placeholder character used in formatting
+func (writer *SocketWriter) Placeholder() byte {
+	return writer.format.Placeholder()
+}
+
+// SetPlaceholder set placeholder character used in formatting
+func (writer *SocketWriter) SetPlaceholder(placeholder byte) {
+	writer.format.SetPlaceholder(placeholder)
+}
+
+// SetEOL is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetEOL(eol byte) {
+	unsupported(writer.name, "SetEOL")
+}
+
+// SetEscape set escape character used in formatting
+func (writer *SocketWriter) SetEscape(escape byte) {
+	writer.format.SetEscape(escape)
+}
+
+// SetNilString set the token nil args are written as in formatting
+func (writer *SocketWriter) SetNilString(s string) {
+	writer.format.SetNilString(s)
+}
+
+// SetSafeStringer toggle calling String and Error methods of args with
+// recover
+func (writer *SocketWriter) SetSafeStringer(safe bool) {
+	writer.format.SetSafeStringer(safe)
+}
+
+// SetRawStringer toggle bypassing String and Error methods of args
+func (writer *SocketWriter) SetRawStringer(raw bool) {
+	writer.format.SetRawStringer(raw)
+}
+
+// SetDebounce is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetDebounce(format string, window time.Duration) {
+	unsupported(writer.name, "SetDebounce")
+}
+
+// SetSamplerPolicy is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetSamplerPolicy(first, thereafter int, tick time.Duration) {
+	unsupported(writer.name, "SetSamplerPolicy")
+}
+
+// SetPrintSequence is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetPrintSequence(sequence bool) {
+	unsupported(writer.name, "SetPrintSequence")
+}
+
+// SetPrintElapsed is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetPrintElapsed(elapsed bool) {
+	unsupported(writer.name, "SetPrintElapsed")
+}
+
+// SetSequenceWidth is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetSequenceWidth(width int) {
+	unsupported(writer.name, "SetSequenceWidth")
+}
+
+// SetDefaultFields sets static fields prepended to every message
+func (writer *SocketWriter) SetDefaultFields(fields map[string]string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.set(fields)
+}
+
+// AddDefaultField appends a static field prepended to every message
+func (writer *SocketWriter) AddDefaultField(key, value string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.add(key, value)
+}
+
+// separators return separators in use
+func (writer *SocketWriter) separators() *fieldSeparator {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.separator()
+}
+
+// SetFieldSeparator set what is written between structured fields
+func (writer *SocketWriter) SetFieldSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.setFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields
+func (writer *SocketWriter) SetKVSeparator(sep string) error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.defaults.setKVSeparator(sep)
+}
+
+// SetPrintHostname toggle writing hostname ahead every message
+func (writer *SocketWriter) SetPrintHostname(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintHost(print)
+}
+
+// SetHostname overrides hostname written ahead messages
+func (writer *SocketWriter) SetHostname(name string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setHost(name)
+}
+
+// SetPrintPID toggle writing process id ahead every message
+func (writer *SocketWriter) SetPrintPID(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintPID(print)
+}
+
+// SetPrintProgram toggle writing program name ahead every message
+func (writer *SocketWriter) SetPrintProgram(print bool) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	writer.defaults.setPrintProgram(print)
+}
+
+// SetPrintCallerFunc is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetPrintCallerFunc(print bool) {
+	unsupported(writer.name, "SetPrintCallerFunc")
+}
+
+// SetPrintCallerLine is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetPrintCallerLine(print bool) {
+	unsupported(writer.name, "SetPrintCallerLine")
+}
+
+// AddDropSubstring drops messages containing s
+func (writer *SocketWriter) AddDropSubstring(s string) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if nil == writer.drops {
+		writer.drops = new(dropRules)
+	}
+	writer.drops.addSubstring(s)
+}
+
+// AddDropRegexp drops messages matching re
+func (writer *SocketWriter) AddDropRegexp(re *regexp.Regexp) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if nil == writer.drops {
+		writer.drops = new(dropRules)
+	}
+	writer.drops.addRegexp(re)
+}
+
+// Dropped return number of messages dropped by drop rules
+func (writer *SocketWriter) Dropped() int64 {
+	return writer.drops.count()
+}
+
+// SetTimeFormat is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetTimeFormat(layout string) {
+	unsupported(writer.name, "SetTimeFormat")
+}
+
+// SetTimeFormatPreset is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetTimeFormatPreset(preset TimePreset) {
+	unsupported(writer.name, "SetTimeFormatPreset")
+}
+
+// SetFraming is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetFraming(framing Framing) {
+	unsupported(writer.name, "SetFraming")
+}
+
+// SetSmartTimeVerb toggle formatting time and duration args smartly
+func (writer *SocketWriter) SetSmartTimeVerb(smart bool) {
+	writer.format.SetSmartTimeVerb(smart)
+}
+
+// SetFormatter is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetFormatter(formatter Formatter) {
+	unsupported(writer.name, "SetFormatter")
+}
+
+// FlushLevel always TRACE, socket writer writes every line immediately
+func (writer *SocketWriter) FlushLevel() LevelType {
+	return TRACE
+}
+
+// SetFlushLevel do nothing
+func (writer *SocketWriter) SetFlushLevel(level LevelType) {
+	return
+}
+
+// SetBufferSize do nothing
+func (writer *SocketWriter) SetBufferSize(size int) {
+	return
+}
+
+// AtomicWrite always true, socket writer writes every line with a single
+// Write call
+func (writer *SocketWriter) AtomicWrite() bool {
+	return true
+}
+
+// SetAtomicWrite do nothing
+func (writer *SocketWriter) SetAtomicWrite(atomic bool) {
+	return
+}
+
+// SetReorderWindow is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetReorderWindow(d time.Duration) {
+	unsupported(writer.name, "SetReorderWindow")
+}
+
+// SetDevMode is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetDevMode(dev bool) {
+	unsupported(writer.name, "SetDevMode")
+}
+
+// MultilinePrefix do nothing
+func (writer *SocketWriter) MultilinePrefix() bool {
+	return false
+}
+
+// SetMultilinePrefix is not supported, calling it is reported as an internal error
+func (writer *SocketWriter) SetMultilinePrefix(multiline bool) {
+	unsupported(writer.name, "SetMultilinePrefix")
+}
+
 // Close will close the writer
 func (writer *SocketWriter) Close() {
 	writer.lock.Lock()
@@ -215,9 +745,27 @@ func (writer *SocketWriter) flush() {
 	return
 }
 
+// Drain do nothing, socket writer writes every line immediately
+func (writer *SocketWriter) Drain() error {
+	return nil
+}
+
+// Ping verifies the connection with a zero-byte write
+func (writer *SocketWriter) Ping() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	_, err := writer.writer.Write(nil)
+	return err
+}
+
 // Trace trace
 func (writer *SocketWriter) Trace(args ...interface{}) {
-	if TRACE < writer.level {
+	if TRACE < CompileLevel || TRACE < writer.level {
 		return
 	}
 
@@ -226,7 +774,7 @@ func (writer *SocketWriter) Trace(args ...interface{}) {
 
 // Tracef tracef
 func (writer *SocketWriter) Tracef(format string, args ...interface{}) {
-	if TRACE < writer.level {
+	if TRACE < CompileLevel || TRACE < writer.level {
 		return
 	}
 
@@ -235,7 +783,7 @@ func (writer *SocketWriter) Tracef(format string, args ...interface{}) {
 
 // Debug debug
 func (writer *SocketWriter) Debug(args ...interface{}) {
-	if DEBUG < writer.level {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
 		return
 	}
 
@@ -244,34 +792,155 @@ func (writer *SocketWriter) Debug(args ...interface{}) {
 
 // Debugf debugf
 func (writer *SocketWriter) Debugf(format string, args ...interface{}) {
-	if DEBUG < writer.level {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
 		return
 	}
 
 	writer.writef(DEBUG, format, args...)
 }
 
+// DebugPretty debug pretty
+func (writer *SocketWriter) DebugPretty(v interface{}) {
+	if DEBUG < CompileLevel || DEBUG < writer.level {
+		return
+	}
+
+	writer.writeLines(DEBUG, prettyFormat(v))
+}
+
+// ErrorStack writes message with stack trace of the caller, a stack
+// written before is written as its fingerprint
+func (writer *SocketWriter) ErrorStack(message string) {
+	if ERROR < CompileLevel || ERROR < writer.level {
+		return
+	}
+
+	writer.writeLines(ERROR, writer.stacks.message(message))
+}
+
+// Entry starts building a structured message with specific level
+func (writer *SocketWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
 // Info info
 func (writer *SocketWriter) Info(args ...interface{}) {
-	if INFO < writer.level {
+	if INFO < CompileLevel || INFO < writer.level {
 		return
 	}
 
 	writer.write(INFO, args...)
 }
 
+// InfoJSON writes fields as a json line with info level
+func (writer *SocketWriter) InfoJSON(fields map[string]interface{}) {
+	if INFO < CompileLevel || INFO < writer.level {
+		return
+	}
+
+	writer.writeJSON(INFO, fields)
+}
+
+// WriteBatch sends messages with specific level in order, taking the lock
+// once. It return total size sent
+func (writer *SocketWriter) WriteBatch(level LevelType, messages []string) (size int) {
+	if level < CompileLevel || !level.valid() || level < writer.level {
+		return 0
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed {
+		return 0
+	}
+
+	buffer := new(bytes.Buffer)
+	for _, message := range messages {
+		if writer.drops.drop([]byte(message)) {
+			continue
+		}
+
+		buffer.Reset()
+		buffer.Write(timeCache.Format())
+		buffer.WriteString(level.prefix())
+		buffer.WriteString(writer.defaults.prefix)
+		buffer.WriteString(message)
+		writer.send(buffer.Bytes())
+		size += buffer.Len()
+
+		// call log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, timeCache.Now(), level, message)
+		}
+	}
+	return
+}
+
+// LogAt sends message with specific level prefixed with time t instead of now
+func (writer *SocketWriter) LogAt(t time.Time, level LevelType, message string) {
+	if level < CompileLevel || !level.valid() || level < writer.level {
+		return
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if writer.closed || writer.drops.drop([]byte(message)) {
+		return
+	}
+
+	defer func() {
+		// call log hook
+		if nil != writer.hook && !(level < writer.hookLevel) {
+			fireHook(writer.hook, writer.hookAsync, t, level, message)
+		}
+	}()
+
+	buffer := bytes.NewBufferString(t.Format(PrefixTimeFormat))
+	buffer.WriteString(level.prefix())
+	buffer.WriteString(writer.defaults.prefix)
+	buffer.WriteString(message)
+	writer.send(buffer.Bytes())
+}
+
+// LogfAt formats message with specific level and sends it prefixed with time
+// t instead of now
+func (writer *SocketWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	writer.LogAt(t, level, writer.format.sprintf(format, args...))
+}
+
+// Raw sends message verbatim without time && level prefix
+func (writer *SocketWriter) Raw(message string) {
+	if RawLevel < CompileLevel || RawLevel < writer.level {
+		return
+	}
+
+	writer.writeRaw(message)
+}
+
 // Infof infof
 func (writer *SocketWriter) Infof(format string, args ...interface{}) {
-	if INFO < writer.level {
+	if INFO < CompileLevel || INFO < writer.level {
 		return
 	}
 
 	writer.writef(INFO, format, args...)
 }
 
+// InfoSync info, socket writer writes every line immediately
+func (writer *SocketWriter) InfoSync(message string) {
+	writer.Info(message)
+}
+
+// InfofSync infof, socket writer writes every line immediately
+func (writer *SocketWriter) InfofSync(format string, args ...interface{}) {
+	writer.Infof(format, args...)
+}
+
 // Warn warn
 func (writer *SocketWriter) Warn(args ...interface{}) {
-	if WARNING < writer.level {
+	if WARNING < CompileLevel || WARNING < writer.level {
 		return
 	}
 
@@ -280,7 +949,7 @@ func (writer *SocketWriter) Warn(args ...interface{}) {
 
 // Warnf warnf
 func (writer *SocketWriter) Warnf(format string, args ...interface{}) {
-	if WARNING < writer.level {
+	if WARNING < CompileLevel || WARNING < writer.level {
 		return
 	}
 
@@ -289,7 +958,7 @@ func (writer *SocketWriter) Warnf(format string, args ...interface{}) {
 
 // Error error
 func (writer *SocketWriter) Error(args ...interface{}) {
-	if ERROR < writer.level {
+	if ERROR < CompileLevel || ERROR < writer.level {
 		return
 	}
 
@@ -298,7 +967,7 @@ func (writer *SocketWriter) Error(args ...interface{}) {
 
 // Errorf error
 func (writer *SocketWriter) Errorf(format string, args ...interface{}) {
-	if ERROR < writer.level {
+	if ERROR < CompileLevel || ERROR < writer.level {
 		return
 	}
 
@@ -307,7 +976,7 @@ func (writer *SocketWriter) Errorf(format string, args ...interface{}) {
 
 // Critical critical
 func (writer *SocketWriter) Critical(args ...interface{}) {
-	if CRITICAL < writer.level {
+	if CRITICAL < CompileLevel || CRITICAL < writer.level {
 		return
 	}
 
@@ -316,7 +985,7 @@ func (writer *SocketWriter) Critical(args ...interface{}) {
 
 // Criticalf criticalf
 func (writer *SocketWriter) Criticalf(format string, args ...interface{}) {
-	if CRITICAL < writer.level {
+	if CRITICAL < CompileLevel || CRITICAL < writer.level {
 		return
 	}
 
@@ -21,11 +21,11 @@ func TestSocketWriterBasicOperation(t *testing.T) {
 	// test socket writer hook
 	hook := NewMyHook()
 
-	blog.SetHook(hook)
-	blog.SetHookLevel(INFO)
+	blog().SetHook(hook)
+	blog().SetHookLevel(INFO)
 
-	blog.Debug("something")
-	blog.Debugf("%s", "something")
+	blog().Debug("something")
+	blog().Debugf("%s", "something")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 0 != hook.Cnt() {
@@ -37,7 +37,7 @@ func TestSocketWriterBasicOperation(t *testing.T) {
 	}
 
 	// async
-	blog.Info("yes")
+	blog().Info("yes")
 	// wait for hook called
 	time.Sleep(1 * time.Millisecond)
 	if 1 != hook.Cnt() {
@@ -49,8 +49,8 @@ func TestSocketWriterBasicOperation(t *testing.T) {
 	}
 
 	// sync
-	blog.SetHookAsync(false)
-	blog.Warn("warn")
+	blog().SetHookAsync(false)
+	blog().Warn("warn")
 	// wait for hook called
 	if 2 != hook.Cnt() {
 		t.Error("hook not called")
@@ -61,49 +61,49 @@ func TestSocketWriterBasicOperation(t *testing.T) {
 	}
 
 	// test basic operations
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s\\", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-	blog.flush()
-
-	blog.Colored()
-	blog.SetColored(true)
-	blog.TimeRotated()
-	blog.SetTimeRotated(true)
-	blog.Level()
-	blog.SetLevel(CRITICAL)
-	blog.Retentions()
-	blog.SetRetentions(7)
-	blog.RotateLines()
-	blog.SetRotateLines(100000)
-	blog.RotateSize()
-	blog.SetRotateSize(1024 * 1024 * 500)
-
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
-	blog.Trace("Trace", 2)
-	blog.Tracef("%s", "Trace")
-	blog.Info("Info", 3)
-	blog.Infof("%s", "Info")
-	blog.Warn("Warn", 4)
-	blog.Warnf("%s", "Warn")
-	blog.Error("Error", 5)
-	blog.Errorf("%s", "Error")
-	blog.Critical("Critical", 6)
-	blog.Criticalf("%s", "Critical")
-
-	blog.Close()
-	blog.Debug("Debug", 1)
-	blog.Debugf("%s", "Debug")
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s\\", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+	blog().flush()
+
+	blog().Colored()
+	blog().SetColored(true)
+	blog().TimeRotated()
+	blog().SetTimeRotated(true)
+	blog().Level()
+	blog().SetLevel(CRITICAL)
+	blog().Retentions()
+	blog().SetRetentions(7)
+	blog().RotateLines()
+	blog().SetRotateLines(100000)
+	blog().RotateSize()
+	blog().SetRotateSize(1024 * 1024 * 500)
+
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
+	blog().Trace("Trace", 2)
+	blog().Tracef("%s", "Trace")
+	blog().Info("Info", 3)
+	blog().Infof("%s", "Info")
+	blog().Warn("Warn", 4)
+	blog().Warnf("%s", "Warn")
+	blog().Error("Error", 5)
+	blog().Errorf("%s", "Error")
+	blog().Critical("Critical", 6)
+	blog().Criticalf("%s", "Critical")
+
+	blog().Close()
+	blog().Debug("Debug", 1)
+	blog().Debugf("%s", "Debug")
 }
 
 func TestSignleSocketWriter(t *testing.T) {
@@ -154,7 +154,7 @@ func TestSignleSocketWriter(t *testing.T) {
 	}()
 
 	wgListen.Wait()
-	blog.Debug("haha")
+	blog().Debug("haha")
 	wg.Wait()
 
 	// chekc init socket writer multi time
@@ -174,6 +174,65 @@ func BenchmarkSocketWriter(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		blog.Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+		blog().Debugf("haha %s. en\\en, always %d and %f", "eddie", 18, 3.1415)
+	}
+}
+
+func TestSocketWriterWriteTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// connections are accepted but never read
+	accepted := make(chan net.Conn, 8)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if nil != err {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if nil != err {
+		t.Fatal(err)
+	}
+	writer := newConnWriter(conn)
+	writer.network = "tcp"
+	writer.address = ln.Addr().String()
+	defer writer.Close()
+
+	var errs int
+	timeout := 100 * time.Millisecond
+	writer.SetWriteTimeout(timeout)
+	writer.SetErrorHandler(func(err error) {
+		errs++
+	})
+
+	line := strings.Repeat("x", 1<<20)
+	for i := 0; i < 64 && 0 == errs; i++ {
+		start := time.Now()
+		writer.Info(line)
+		if elapsed := time.Since(start); elapsed > timeout+time.Second {
+			t.Fatalf("write should be bounded by timeout. elapsed: %v", elapsed)
+		}
+	}
+
+	if 0 == errs {
+		t.Fatal("timed out write should be reported to error handler.")
+	}
+
+	// socket is reconnected after timeout
+	for i := 0; i < 2; i++ {
+		select {
+		case c := <-accepted:
+			defer c.Close()
+		case <-time.After(time.Second):
+			t.Fatal("socket should be reconnected after timeout.")
+		}
 	}
 }
@@ -0,0 +1,46 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+// NewSpillWriter initialize a file writer keeping logs in memory, singlton.
+// logs are written to file only when more than memLimit bytes are buffered,
+// when Flush is called or message level reaches flush level, and when the
+// writer is closed. it reduces disk I/O for burst traffic while bounding
+// memory. logs in memory are lost if the program exits without Close
+func NewSpillWriter(path string, memLimit int) (err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+
+	if nil != blog() {
+		return ErrAlreadyInit
+	}
+
+	spillWriter, err := newSpillWriter(path, memLimit)
+	if nil != err {
+		return err
+	}
+
+	setBlog(spillWriter)
+	return nil
+}
+
+// newSpillWriter create a file writer keeping at most memLimit bytes in
+// memory, not singlton. memLimit less than DefaultBufferSize is raised to it
+func newSpillWriter(path string, memLimit int) (spillWriter *baseFileWriter, err error) {
+	spillWriter, err = newBaseFileWriter(path, false)
+	if nil != err {
+		return nil, err
+	}
+
+	if memLimit < DefaultBufferSize {
+		memLimit = DefaultBufferSize
+	}
+
+	spillWriter.lock.Lock()
+	defer spillWriter.lock.Unlock()
+
+	spillWriter.autoFlush = false
+	spillWriter.blog = newBLogSize(spillWriter.file, memLimit)
+	spillWriter.blog.name = path
+	return spillWriter, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestSpillWriter(t *testing.T) {
+	memLimit := 2 * DefaultBufferSize
+	writer, err := newSpillWriter("/tmp/spill.log", memLimit)
+	if nil != err {
+		t.Fatalf("Failed when initializing spill writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	line := strings.Repeat("x", 100)
+
+	// below memory limit, nothing on disk
+	size, lines := 0, 0
+	for size < memLimit/2 {
+		writer.Info(line)
+		size += 100
+		lines++
+	}
+
+	content, _ := ioutil.ReadFile("/tmp/spill.log")
+	if 0 != len(content) {
+		t.Errorf("logs below memory limit should keep in memory. size: %d", len(content))
+	}
+
+	// above memory limit, spilled to disk
+	for size < 2*memLimit {
+		writer.Info(line)
+		size += 100
+		lines++
+	}
+
+	content, _ = ioutil.ReadFile("/tmp/spill.log")
+	if 0 == len(content) || len(content) > size+size/2 {
+		t.Errorf("logs above memory limit should be spilled. size: %d", len(content))
+	}
+
+	writer.Info("last")
+	writer.Close()
+
+	content, _ = ioutil.ReadFile("/tmp/spill.log")
+	if !strings.HasSuffix(string(content), " last\n") || lines+1 != strings.Count(string(content), "\n") {
+		t.Errorf("all logs should be written after close. lines: %d", strings.Count(string(content), "\n"))
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"sync"
+)
+
+// newFormatBLog create a BLog which writes nothing, used by writers not
+// writing through a BLog to format messages with the same parser, so that
+// placeholder, escape character, nil string and custom verbs apply to them
+func newFormatBLog() *BLog {
+	blog := new(BLog)
+	blog.lock = new(sync.Mutex)
+	blog.placeholder = PLACEHOLDER
+	blog.escape = ESCAPE
+	blog.eol = EOL
+	blog.message = new(bytes.Buffer)
+	return blog
+}
+
+// sprintf formats args by format with the parser of BLog into a message
+// body, the same as the body BLog writes
+func (blog *BLog) sprintf(format string, args ...interface{}) string {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	return blog.sprintfLocked(format, args...)
+}
+
+// sprintfLocked is sprintf called under lock of BLog
+func (blog *BLog) sprintfLocked(format string, args ...interface{}) string {
+	// 没有参数时无需解析，按原样输出
+	if 0 == len(args) && !blog.strict {
+		return format
+	}
+
+	blog.message.Reset()
+	blog.short.reset(blog.message)
+	blog.format(blog.message, format, args)
+	return blog.message.String()
+}
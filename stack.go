@@ -0,0 +1,107 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxStackDepth is the maximum number of frames of a stack trace
+	maxStackDepth = 32
+
+	// StackPrefix prefixes fingerprints of stack traces, e.g. stack#1a2b3c4d
+	StackPrefix = "stack#"
+)
+
+var (
+	// StackDedupLimit is how many stack fingerprints a writer remembers, they
+	// are forgotten all together beyond it and full stacks are written again
+	StackDedupLimit = 1024
+)
+
+// stackDedup remembers fingerprints of stack traces written. the zero value
+// is ready to use
+type stackDedup struct {
+	seen map[string]bool
+	lock sync.Mutex
+}
+
+// message return message followed by stack trace of the caller. The full
+// stack is written on first occurrence with its fingerprint as legend,
+// identical stacks later are written as the fingerprint only
+func (dedup *stackDedup) message(message string) string {
+	frames := callerStack()
+	fingerprint := stackFingerprint(frames)
+
+	dedup.lock.Lock()
+	repeated := dedup.seen[fingerprint]
+	if !repeated {
+		if nil == dedup.seen || len(dedup.seen) >= StackDedupLimit {
+			dedup.seen = make(map[string]bool)
+		}
+		dedup.seen[fingerprint] = true
+	}
+	dedup.lock.Unlock()
+
+	if repeated {
+		return fmt.Sprintf("%s %s%s (repeated)", message, StackPrefix, fingerprint)
+	}
+
+	buf := bytes.NewBufferString(message)
+	fmt.Fprintf(buf, " %s%s", StackPrefix, fingerprint)
+	for _, frame := range frames {
+		fmt.Fprintf(buf, "%c\t%s\n\t\t%s:%d", EOL, frame.Function, frame.File, frame.Line)
+	}
+	return buf.String()
+}
+
+// callerStack return frames from the first one calling into blog4go
+func callerStack() (frames []runtime.Frame) {
+	var pcs [maxStackDepth + maxCallerDepth]uintptr
+	it := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+
+	inside := true
+	for len(frames) < maxStackDepth {
+		frame, more := it.Next()
+		if inside && filepath.Dir(frame.File) == sourceDir && !strings.HasSuffix(frame.File, "_test.go") {
+			if !more {
+				break
+			}
+			continue
+		}
+		inside = false
+
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return
+}
+
+// stackFingerprint return hash of functions && lines of frames
+func stackFingerprint(frames []runtime.Frame) string {
+	h := fnv.New32a()
+	for _, frame := range frames {
+		fmt.Fprintf(h, "%s:%d;", frame.Function, frame.Line)
+	}
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// ErrorStack static function for ErrorStack
+func ErrorStack(message string) {
+	if ERROR < CompileLevel {
+		return
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.ErrorStack(message)
+}
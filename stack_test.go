@@ -0,0 +1,43 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorStackDedup(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	defer writer.Close()
+
+	for i := 0; i < 2; i++ {
+		writer.ErrorStack("query failed")
+	}
+	writer.ErrorStack("query failed")
+
+	if 3 != len(sink.messages) {
+		t.Fatalf("messages delivered wrong. got: %d", len(sink.messages))
+	}
+
+	first := sink.messages[0]
+	lines := strings.Split(first, "\n")
+	if !strings.HasPrefix(lines[0], "query failed "+StackPrefix) || len(lines) < 3 {
+		t.Fatalf("full stack should be written first time. got: %q", first)
+	}
+	if !strings.Contains(lines[1], "TestErrorStackDedup") {
+		t.Errorf("stack should start at the caller. got: %q", lines[1])
+	}
+
+	fingerprint := strings.TrimPrefix(lines[0], "query failed ")
+	if expected := fmt.Sprintf("query failed %s (repeated)", fingerprint); expected != sink.messages[1] {
+		t.Errorf("identical stack should be written as fingerprint. expected: %q, got: %q", expected, sink.messages[1])
+	}
+
+	// called from another line, it is another stack
+	if third := sink.messages[2]; !strings.Contains(third, "\n") || strings.Contains(third, fingerprint) {
+		t.Errorf("another stack should be written in full. got: %q", third)
+	}
+}
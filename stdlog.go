@@ -0,0 +1,50 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io"
+	"log"
+	"strings"
+)
+
+// stdLogWriter writes every Write as a message to the singleton writer
+type stdLogWriter struct {
+	level LevelType
+}
+
+// Write logs p with level, trailing EOL added by log package is trimmed so
+// that lines are not doubled
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	if w.level < CompileLevel {
+		return len(p), nil
+	}
+
+	holder := acquireSingleton()
+	defer holder.release()
+	logs(holder.writer, w.level, strings.TrimSuffix(string(p), string(EOL)))
+	return len(p), nil
+}
+
+// WriterAt return an io.Writer logging everything written to it with level
+// to the singleton writer, every Write makes a message
+func WriterAt(level LevelType) io.Writer {
+	return &stdLogWriter{level: level}
+}
+
+// RedirectStdLog makes the default logger of log package write to the
+// singleton writer with level, so that output of dependencies calling
+// log.Println flows into blog4go. Flags are cleared because time prefix is
+// written by blog4go. restore gives the default logger back its output and
+// flags
+func RedirectStdLog(level LevelType) (restore func()) {
+	out, flags := log.Writer(), log.Flags()
+
+	log.SetOutput(WriterAt(level))
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	}
+}
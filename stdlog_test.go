@@ -0,0 +1,49 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	writer, err := newBaseFileWriter("/tmp/stdlog.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+	setBlog(writer)
+
+	restore := RedirectStdLog(WARNING)
+	log.Print("x")
+	log.Println("from dependency")
+	restore()
+
+	// default logger restored
+	if os.Stderr != log.Writer() || log.LstdFlags != log.Flags() {
+		t.Errorf("default logger should be restored. flags: %d", log.Flags())
+	}
+	writer.Close()
+
+	content, _ := ioutil.ReadFile("/tmp/stdlog.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 2 != len(lines) {
+		t.Fatalf("std log lines should be written once. got: %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], "[WARN] x") || !strings.HasSuffix(lines[1], "[WARN] from dependency") {
+		t.Errorf("std log lines written wrong. got: %q", lines)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// StringerPanic is written instead of an arg whose String or Error method
+	// panics in safe stringer mode
+	StringerPanic = "<stringer panic>"
+)
+
+// stringerValue carries the string of a Stringer or error arg. it is a
+// Stringer itself, so that fmt formats and spaces it like the original arg
+type stringerValue struct {
+	s string
+}
+
+// String implements fmt.Stringer
+func (v stringerValue) String() string {
+	return v.s
+}
+
+// safeString calls Error or String of arg, StringerPanic is returned if it
+// panics
+func safeString(arg interface{}) (s string) {
+	defer func() {
+		if nil != recover() {
+			s = StringerPanic
+		}
+	}()
+
+	switch v := arg.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(arg)
+}
+
+// stringerArg return what arg is formatted as with verb, arg is replaced only
+// if it is a non nil Stringer or error and verb calls its methods
+func (blog *BLog) stringerArg(verb string, arg interface{}) interface{} {
+	if !blog.safeStringer && !blog.rawStringer {
+		return arg
+	}
+
+	switch arg.(type) {
+	case error, fmt.Stringer:
+	default:
+		return arg
+	}
+
+	if isNil(arg) {
+		// fmt handles nil receivers
+		return arg
+	}
+
+	switch verb[len(verb)-1] {
+	case 'v':
+		if strings.Contains(verb, "#") {
+			// %#v calls GoString rather than String
+			return arg
+		}
+	case 's', 'q', 'x', 'X':
+	default:
+		return arg
+	}
+
+	if blog.rawStringer {
+		return stringerValue{s: fmt.Sprintf("%#v", arg)}
+	}
+	return stringerValue{s: safeString(arg)}
+}
+
+// stringerArgs return args with Stringer and error args replaced in write
+func (blog *BLog) stringerArgs(args []interface{}) []interface{} {
+	if !blog.safeStringer && !blog.rawStringer {
+		return args
+	}
+
+	replaced := make([]interface{}, len(args))
+	for i, arg := range args {
+		replaced[i] = blog.stringerArg("v", arg)
+	}
+	return replaced
+}
+
+// SafeStringer get whether String and Error methods of args are called safely
+func (blog *BLog) SafeStringer() bool {
+	return blog.safeStringer
+}
+
+// SetSafeStringer toggle safe stringer mode, default false. In safe mode
+// String and Error methods of args are called with recover, an arg whose
+// method panics is written as StringerPanic
+func (blog *BLog) SetSafeStringer(safe bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.safeStringer = safe
+	return blog
+}
+
+// RawStringer get whether String and Error methods of args are bypassed
+func (blog *BLog) RawStringer() bool {
+	return blog.rawStringer
+}
+
+// SetRawStringer toggle raw stringer mode, default false. In raw mode String
+// and Error methods of args are never called, such args are written as %#v
+// does instead. It takes precedence over safe stringer mode
+func (blog *BLog) SetRawStringer(raw bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.rawStringer = raw
+	return blog
+}
+
+// SetSafeStringer toggle safe stringer mode of the singleton writer
+func SetSafeStringer(safe bool) {
+	blog().SetSafeStringer(safe)
+}
+
+// SetRawStringer toggle raw stringer mode of the singleton writer
+func SetRawStringer(raw bool) {
+	blog().SetRawStringer(raw)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+type panicStringer struct {
+	ID int
+}
+
+func (p panicStringer) String() string {
+	panic("boom")
+}
+
+type goodStringer struct {
+	ID int
+}
+
+func (g goodStringer) String() string {
+	return "good"
+}
+
+func TestBaseFileWriterSafeStringer(t *testing.T) {
+	initPrefix(false)
+	writer, err := newBaseFileWriter("/tmp/stringer.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer.SetSafeStringer(true)
+	writer.Infof("value %v id %d %s", panicStringer{ID: 1}, 7, goodStringer{})
+	writer.Info(panicStringer{ID: 2}, goodStringer{})
+	writer.Infof("go syntax %#v", goodStringer{ID: 3})
+
+	writer.SetRawStringer(true)
+	writer.Infof("raw %v", panicStringer{ID: 4})
+	writer.Info(goodStringer{ID: 5})
+	writer.flush()
+
+	content, _ := ioutil.ReadFile("/tmp/stringer.log")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if 5 != len(lines) {
+		t.Fatalf("lines count wrong. content: %s", string(content))
+	}
+
+	expected := []string{
+		" [INFO] value <stringer panic> id 7 good",
+		" [INFO] <stringer panic> good",
+		" [INFO] go syntax blog4go.goodStringer{ID:3}",
+		" [INFO] raw blog4go.panicStringer{ID:4}",
+		" [INFO] blog4go.goodStringer{ID:5}",
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("stringer arg wrong. line: %s, expected: %s", line, expected[i])
+		}
+	}
+}
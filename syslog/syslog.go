@@ -0,0 +1,120 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+// Package syslog provides a blog4go writer sending every message to a syslog
+// daemon in RFC 3164 format, such as <30>Jul 17 08:05:02 host app[123]: msg.
+// It does not use log/syslog, so that it builds on every platform.
+package syslog
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// Facility is the syslog facility of messages, the priority written is
+// facility * 8 + severity
+type Facility int
+
+const (
+	// LOG_USER user-level messages
+	LOG_USER Facility = 1
+	// LOG_DAEMON system daemons
+	LOG_DAEMON Facility = 3
+	// LOG_LOCAL0 local use 0, LOG_LOCAL0 + n is local use n up to 7
+	LOG_LOCAL0 Facility = 16
+)
+
+var (
+	// Severities maps blog4go levels to syslog severities, which can be
+	// replaced by blog4go.SetSeverityMapper
+	Severities = map[blog4go.LevelType]int{
+		blog4go.TRACE:    7, // debug
+		blog4go.DEBUG:    7, // debug
+		blog4go.INFO:     6, // informational
+		blog4go.WARNING:  4, // warning
+		blog4go.ERROR:    3, // error
+		blog4go.CRITICAL: 2, // critical
+	}
+)
+
+// sink writes every message as a syslog line
+type sink struct {
+	w        io.WriteCloser
+	facility Facility
+
+	// hostname and tag[pid] are resolved once when the sink is built
+	hostname string
+	tag      string
+
+	lock *sync.Mutex
+}
+
+// Emit writes message as a syslog line with priority of level
+func (s *sink) Emit(t time.Time, level blog4go.LevelType, message string) error {
+	priority := int(s.facility)*8 + blog4go.Severity(level, Severities[level])
+
+	line := make([]byte, 0, len(message)+len(s.hostname)+len(s.tag)+32)
+	line = append(line, '<')
+	line = strconv.AppendInt(line, int64(priority), 10)
+	line = append(line, '>')
+	line = t.AppendFormat(line, time.Stamp)
+	line = append(line, ' ')
+	line = append(line, s.hostname...)
+	line = append(line, ' ')
+	line = append(line, s.tag...)
+	line = append(line, ": "...)
+	line = append(line, message...)
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.w.Write(line)
+	return err
+}
+
+// Flush do nothing, every line is written immediately
+func (s *sink) Flush() error {
+	return nil
+}
+
+// Close closes the connection to the syslog daemon
+func (s *sink) Close() error {
+	return s.w.Close()
+}
+
+// NewSyslogWriter creates a writer sending messages with facility and tag to
+// the syslog daemon at raddr, e.g. ("udp", "localhost:514"). tag is the
+// program name if empty
+func NewSyslogWriter(network, raddr string, facility Facility, tag string) (blog4go.Writer, error) {
+	conn, err := net.Dial(network, raddr)
+	if nil != err {
+		return nil, err
+	}
+	return NewSyslogWriterWithConn(conn, facility, tag), nil
+}
+
+// NewSyslogWriterWithConn creates a writer writing syslog lines to w, w is
+// closed when the writer is closed
+func NewSyslogWriterWithConn(w io.WriteCloser, facility Facility, tag string) blog4go.Writer {
+	if "" == tag {
+		tag = filepath.Base(os.Args[0])
+	}
+	hostname, _ := os.Hostname()
+	if "" == hostname {
+		hostname = "-"
+	}
+
+	return blog4go.NewSinkWriter(&sink{
+		w:        w,
+		facility: facility,
+		hostname: hostname,
+		tag:      tag + "[" + strconv.Itoa(os.Getpid()) + "]",
+		lock:     new(sync.Mutex),
+	})
+}
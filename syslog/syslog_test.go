@@ -0,0 +1,72 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package syslog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/YoungPioneers/blog4go"
+)
+
+// bufferConn keeps every line written
+type bufferConn struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (conn *bufferConn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+func TestSyslogWriter(t *testing.T) {
+	conn := new(bufferConn)
+	writer := NewSyslogWriterWithConn(conn, LOG_LOCAL0, "app")
+
+	writer.Info("started")
+	writer.Errorf("failed %d", 1)
+
+	lines := strings.Split(strings.TrimSuffix(conn.String(), "\n"), "\n")
+	if 2 != len(lines) {
+		t.Fatalf("lines count wrong. content: %q", conn.String())
+	}
+	// local0 is 16, info is 6 and error is 3
+	if !strings.HasPrefix(lines[0], "<134>") || !strings.Contains(lines[0], " app[") || !strings.HasSuffix(lines[0], "]: started") {
+		t.Errorf("info line wrong. line: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "<131>") || !strings.HasSuffix(lines[1], "]: failed 1") {
+		t.Errorf("error line wrong. line: %s", lines[1])
+	}
+
+	writer.Close()
+	if !conn.closed {
+		t.Error("connection should be closed with the writer.")
+	}
+}
+
+func TestSyslogWriterSeverityMapper(t *testing.T) {
+	defer blog4go.SetSeverityMapper(nil)
+
+	conn := new(bufferConn)
+	writer := NewSyslogWriterWithConn(conn, LOG_USER, "app")
+	writer.SetLevel(blog4go.TRACE)
+
+	// trace is written as notice instead of debug
+	blog4go.SetSeverityMapper(func(level blog4go.LevelType) int {
+		if blog4go.TRACE == level {
+			return 5
+		}
+		return Severities[level]
+	})
+	writer.Trace("trace")
+	writer.Warn("warn")
+
+	// user is 1, notice is 5 and warning is 4
+	lines := strings.Split(strings.TrimSuffix(conn.String(), "\n"), "\n")
+	if 2 != len(lines) || !strings.HasPrefix(lines[0], "<13>") || !strings.HasPrefix(lines[1], "<12>") {
+		t.Errorf("priority should be mapped by the severity mapper. content: %q", conn.String())
+	}
+	writer.Close()
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// ThrottleNoticeInterval is the minimum interval between notices of lines
+	// throttled, written with warning level
+	ThrottleNoticeInterval = time.Second
+
+	// ThrottleNoticeFormat is the format of notices of lines throttled
+	ThrottleNoticeFormat = "blog4go: throttled %d lines"
+)
+
+// ThrottleWriter caps lines per second written to the writer wrapped with a
+// token bucket, across all levels. Lines beyond it are dropped, or wait for
+// tokens refilled in blocking mode, in order of arrival. Lines at or above
+// exempt level are never throttled, default ERROR
+type ThrottleWriter struct {
+	Writer
+
+	// tokens refilled per second, also size of the bucket
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	block  bool
+	exempt LevelType
+
+	// lines dropped since last notice
+	throttled int64
+	noticed   time.Time
+
+	lock *sync.Mutex
+}
+
+// NewThrottleWriter wraps w to write at most maxLinesPerSecond lines per
+// second, protecting downstream systems from log storms. Unlike sampler
+// policy it limits all messages of w together. maxLinesPerSecond <= 0 returns
+// w as it is
+func NewThrottleWriter(w Writer, maxLinesPerSecond int) Writer {
+	if maxLinesPerSecond <= 0 {
+		return w
+	}
+
+	throttleWriter := new(ThrottleWriter)
+	throttleWriter.Writer = w
+	throttleWriter.rate = float64(maxLinesPerSecond)
+	throttleWriter.tokens = throttleWriter.rate
+	throttleWriter.last = now()
+	throttleWriter.noticed = throttleWriter.last
+	throttleWriter.exempt = ERROR
+	throttleWriter.lock = new(sync.Mutex)
+	return throttleWriter
+}
+
+// SetBlocking makes lines beyond limit wait for tokens instead of being
+// dropped, default false
+func (writer *ThrottleWriter) SetBlocking(block bool) *ThrottleWriter {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.block = block
+	return writer
+}
+
+// SetExemptLevel set level at or above which lines are never throttled, a
+// level above CRITICAL throttles all lines
+func (writer *ThrottleWriter) SetExemptLevel(level LevelType) *ThrottleWriter {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.exempt = level
+	return writer
+}
+
+// SetLevel set logging level threshold of the writer wrapped and return the
+// throttle writer for chaining
+func (writer *ThrottleWriter) SetLevel(level LevelType) Writer {
+	writer.Writer.SetLevel(level)
+	return writer
+}
+
+// admit takes a token for a line with level, and return false if the line
+// should be dropped. It waits for the token in blocking mode
+func (writer *ThrottleWriter) admit(level LevelType) bool {
+	if level < CompileLevel || level < writer.Writer.Level() {
+		return false
+	}
+
+	writer.lock.Lock()
+	if !(level < writer.exempt) {
+		writer.lock.Unlock()
+		return true
+	}
+
+	t := now()
+	writer.tokens += t.Sub(writer.last).Seconds() * writer.rate
+	if writer.tokens > writer.rate {
+		writer.tokens = writer.rate
+	}
+	writer.last = t
+
+	if writer.tokens < 1 && !writer.block {
+		writer.throttled++
+		writer.lock.Unlock()
+		CountDropped(DropThrottled)
+		return false
+	}
+
+	// token may be borrowed in blocking mode, lines wait in order of arrival
+	writer.tokens--
+	wait := time.Duration(-writer.tokens / writer.rate * float64(time.Second))
+
+	var throttled int64
+	if 0 < writer.throttled && t.Sub(writer.noticed) >= ThrottleNoticeInterval {
+		throttled = writer.throttled
+		writer.throttled = 0
+		writer.noticed = t
+	}
+	writer.lock.Unlock()
+
+	if 0 < throttled {
+		writer.Writer.Warnf(ThrottleNoticeFormat, throttled)
+	}
+	if 0 < wait {
+		time.Sleep(wait)
+	}
+	return true
+}
+
+func (writer *ThrottleWriter) write(level LevelType, args ...interface{}) {
+	if writer.admit(level) {
+		writer.Writer.write(level, args...)
+	}
+}
+
+func (writer *ThrottleWriter) writef(level LevelType, format string, args ...interface{}) {
+	if writer.admit(level) {
+		writer.Writer.writef(level, format, args...)
+	}
+}
+
+// Trace trace
+func (writer *ThrottleWriter) Trace(args ...interface{}) {
+	if writer.admit(TRACE) {
+		writer.Writer.Trace(args...)
+	}
+}
+
+// Tracef tracef
+func (writer *ThrottleWriter) Tracef(format string, args ...interface{}) {
+	if writer.admit(TRACE) {
+		writer.Writer.Tracef(format, args...)
+	}
+}
+
+// Debug debug
+func (writer *ThrottleWriter) Debug(args ...interface{}) {
+	if writer.admit(DEBUG) {
+		writer.Writer.Debug(args...)
+	}
+}
+
+// Debugf debugf
+func (writer *ThrottleWriter) Debugf(format string, args ...interface{}) {
+	if writer.admit(DEBUG) {
+		writer.Writer.Debugf(format, args...)
+	}
+}
+
+// Info info
+func (writer *ThrottleWriter) Info(args ...interface{}) {
+	if writer.admit(INFO) {
+		writer.Writer.Info(args...)
+	}
+}
+
+// Infof infof
+func (writer *ThrottleWriter) Infof(format string, args ...interface{}) {
+	if writer.admit(INFO) {
+		writer.Writer.Infof(format, args...)
+	}
+}
+
+// Warn warn
+func (writer *ThrottleWriter) Warn(args ...interface{}) {
+	if writer.admit(WARNING) {
+		writer.Writer.Warn(args...)
+	}
+}
+
+// Warnf warnf
+func (writer *ThrottleWriter) Warnf(format string, args ...interface{}) {
+	if writer.admit(WARNING) {
+		writer.Writer.Warnf(format, args...)
+	}
+}
+
+// Error error
+func (writer *ThrottleWriter) Error(args ...interface{}) {
+	if writer.admit(ERROR) {
+		writer.Writer.Error(args...)
+	}
+}
+
+// Errorf errorf
+func (writer *ThrottleWriter) Errorf(format string, args ...interface{}) {
+	if writer.admit(ERROR) {
+		writer.Writer.Errorf(format, args...)
+	}
+}
+
+// Critical critical
+func (writer *ThrottleWriter) Critical(args ...interface{}) {
+	if writer.admit(CRITICAL) {
+		writer.Writer.Critical(args...)
+	}
+}
+
+// Criticalf criticalf
+func (writer *ThrottleWriter) Criticalf(format string, args ...interface{}) {
+	if writer.admit(CRITICAL) {
+		writer.Writer.Criticalf(format, args...)
+	}
+}
+
+// DebugPretty debug pretty, the block takes one token
+func (writer *ThrottleWriter) DebugPretty(v interface{}) {
+	if writer.admit(DEBUG) {
+		writer.Writer.DebugPretty(v)
+	}
+}
+
+// ErrorStack error with stack trace of the caller, the stack takes one token
+func (writer *ThrottleWriter) ErrorStack(message string) {
+	if writer.admit(ERROR) {
+		writer.Writer.ErrorStack(message)
+	}
+}
+
+// InfoJSON writes fields as a json line with info level
+func (writer *ThrottleWriter) InfoJSON(fields map[string]interface{}) {
+	if writer.admit(INFO) {
+		writer.Writer.InfoJSON(fields)
+	}
+}
+
+// WriteBatch writes messages admitted contiguously, every message takes one
+// token
+func (writer *ThrottleWriter) WriteBatch(level LevelType, messages []string) int {
+	admitted := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if writer.admit(level) {
+			admitted = append(admitted, message)
+		}
+	}
+
+	if 0 == len(admitted) {
+		return 0
+	}
+	return writer.Writer.WriteBatch(level, admitted)
+}
+
+// LogAt writes message prefixed with time t instead of now
+func (writer *ThrottleWriter) LogAt(t time.Time, level LevelType, message string) {
+	if writer.admit(level) {
+		writer.Writer.LogAt(t, level, message)
+	}
+}
+
+// LogfAt formats message prefixed with time t instead of now
+func (writer *ThrottleWriter) LogfAt(t time.Time, level LevelType, format string, args ...interface{}) {
+	if writer.admit(level) {
+		writer.Writer.LogfAt(t, level, format, args...)
+	}
+}
+
+// Raw writes message verbatim, throttled with RawLevel
+func (writer *ThrottleWriter) Raw(message string) {
+	if writer.admit(RawLevel) {
+		writer.Writer.Raw(message)
+	}
+}
+
+// InfoSync writes message with info level and flushes it immediately
+func (writer *ThrottleWriter) InfoSync(message string) {
+	if writer.admit(INFO) {
+		writer.Writer.InfoSync(message)
+	}
+}
+
+// InfofSync formats message with info level and flushes it immediately
+func (writer *ThrottleWriter) InfofSync(format string, args ...interface{}) {
+	if writer.admit(INFO) {
+		writer.Writer.InfofSync(format, args...)
+	}
+}
+
+// Entry starts building a structured message with specific level
+func (writer *ThrottleWriter) Entry(level LevelType) *Entry {
+	return newEntry(writer, level)
+}
+
+// separators return separators of the writer throttled
+func (writer *ThrottleWriter) separators() *fieldSeparator {
+	return separatorsOf(writer.Writer)
+}
+
+// SetFieldSeparator set what is written between structured fields of the
+// writer throttled
+func (writer *ThrottleWriter) SetFieldSeparator(sep string) error {
+	s, ok := writer.Writer.(separated)
+	if !ok {
+		return ErrSeparatorNotSupported
+	}
+	return s.SetFieldSeparator(sep)
+}
+
+// SetKVSeparator set what is written between key and value of structured
+// fields of the writer throttled
+func (writer *ThrottleWriter) SetKVSeparator(sep string) error {
+	s, ok := writer.Writer.(separated)
+	if !ok {
+		return ErrSeparatorNotSupported
+	}
+	return s.SetKVSeparator(sep)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestThrottleWriter(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+	ResetDroppedStats()
+
+	sink := newMySink()
+	writer := NewThrottleWriter(NewSinkWriter(sink), 100)
+
+	// flood, errors are exempt
+	for i := 0; i < 1000; i++ {
+		writer.Infof("flood %d", i)
+		if 0 == i%100 {
+			writer.Error("error")
+		}
+	}
+
+	infos, errors := 0, 0
+	for _, level := range sink.levels {
+		switch level {
+		case INFO:
+			infos++
+		case ERROR:
+			errors++
+		}
+	}
+	if 100 != infos || 10 != errors {
+		t.Errorf("lines should be capped at rate. infos: %d, errors: %d", infos, errors)
+	}
+	if 900 != DroppedStats().Throttled {
+		t.Errorf("lines throttled should be counted. got: %d", DroppedStats().Throttled)
+	}
+
+	// tokens refilled, notice of lines throttled comes first
+	c.Add(time.Second)
+	sink.messages = nil
+	for i := 0; i < 1000; i++ {
+		writer.Infof("flood %d", i)
+	}
+	if 101 != len(sink.messages) || fmt.Sprintf(ThrottleNoticeFormat, 900) != sink.messages[0] {
+		t.Errorf("lines should be capped at rate after notice. lines: %d, first: %s", len(sink.messages), sink.messages[0])
+	}
+}
+
+func TestThrottleWriterBlocking(t *testing.T) {
+	sink := newMySink()
+	writer := NewThrottleWriter(NewSinkWriter(sink), 100).(*ThrottleWriter).SetBlocking(true)
+
+	start := time.Now()
+	for i := 0; i < 150; i++ {
+		writer.Info("blocked")
+	}
+
+	// the bucket holds 100 lines, the rest wait for tokens
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("lines beyond limit should wait. elapsed: %s", elapsed)
+	}
+	if 150 != len(sink.messages) {
+		t.Errorf("no line should be dropped in blocking mode. got: %d", len(sink.messages))
+	}
+}
@@ -26,11 +26,16 @@ type timeFormatCacheType struct {
 	// yesterdate
 	dateYesterday string
 
+	// BLog instances open, a single goroutine updates the cache every second
+	// while it is positive. the cache is updated on read otherwise
+	refs int
+	stop chan struct{}
+
 	// lock for read && write
 	lock *sync.RWMutex
 }
 
-// global time cache instance used for every log writer
+// global time cache instance shared by every log writer
 var timeCache = timeFormatCacheType{}
 
 func init() {
@@ -39,24 +44,71 @@ func init() {
 	timeCache.date = timeCache.now.Format(DateFormat)
 	timeCache.format = []byte(timeCache.now.Format(PrefixTimeFormat))
 	timeCache.dateYesterday = timeCache.now.Add(-24 * time.Hour).Format(DateFormat)
+}
+
+// acquire is called when a BLog is created, the first one starts the update
+// goroutine
+func (timeCache *timeFormatCacheType) acquire() {
+	timeCache.lock.Lock()
+	defer timeCache.lock.Unlock()
+
+	timeCache.refs++
+	if 1 != timeCache.refs {
+		return
+	}
 
-	// update timeCache every seconds
-	go func() {
-		// tick every seconds
-		t := time.Tick(1 * time.Second)
-
-		//UpdateTimeCacheLoop:
-		for {
-			select {
-			case <-t:
-				timeCache.fresh()
-			}
+	timeCache.update()
+	timeCache.stop = make(chan struct{})
+	go timeCache.loop(timeCache.stop)
+}
+
+// release is called when a BLog is closed, the last one stops the update
+// goroutine
+func (timeCache *timeFormatCacheType) release() {
+	timeCache.lock.Lock()
+	defer timeCache.lock.Unlock()
+
+	if 0 == timeCache.refs {
+		return
+	}
+
+	timeCache.refs--
+	if 0 == timeCache.refs {
+		close(timeCache.stop)
+		timeCache.stop = nil
+	}
+}
+
+// loop updates timeCache every second until stop is closed
+func (timeCache *timeFormatCacheType) loop(stop chan struct{}) {
+	// tick every seconds
+	t := time.NewTicker(1 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			timeCache.fresh()
+		case <-stop:
+			return
 		}
-	}()
+	}
+}
+
+// sync updates timeCache on read while no goroutine updates it
+func (timeCache *timeFormatCacheType) sync() {
+	timeCache.lock.RLock()
+	stale := 0 == timeCache.refs && time.Now().Unix() != timeCache.now.Unix()
+	timeCache.lock.RUnlock()
+
+	if stale {
+		timeCache.fresh()
+	}
 }
 
 // Now now
 func (timeCache *timeFormatCacheType) Now() time.Time {
+	timeCache.sync()
 	timeCache.lock.RLock()
 	defer timeCache.lock.RUnlock()
 	return timeCache.now
@@ -64,6 +116,7 @@ func (timeCache *timeFormatCacheType) Now() time.Time {
 
 // Date date
 func (timeCache *timeFormatCacheType) Date() string {
+	timeCache.sync()
 	timeCache.lock.RLock()
 	defer timeCache.lock.RUnlock()
 	return timeCache.date
@@ -71,6 +124,7 @@ func (timeCache *timeFormatCacheType) Date() string {
 
 // DateYesterday date
 func (timeCache *timeFormatCacheType) DateYesterday() string {
+	timeCache.sync()
 	timeCache.lock.RLock()
 	defer timeCache.lock.RUnlock()
 	return timeCache.dateYesterday
@@ -78,6 +132,7 @@ func (timeCache *timeFormatCacheType) DateYesterday() string {
 
 // Format format
 func (timeCache *timeFormatCacheType) Format() []byte {
+	timeCache.sync()
 	timeCache.lock.RLock()
 	defer timeCache.lock.RUnlock()
 	return timeCache.format
@@ -88,6 +143,11 @@ func (timeCache *timeFormatCacheType) fresh() {
 	timeCache.lock.Lock()
 	defer timeCache.lock.Unlock()
 
+	timeCache.update()
+}
+
+// update timeCache with current time, lock must be held by the caller
+func (timeCache *timeFormatCacheType) update() {
 	// get current time and update timeCache
 	now := time.Now()
 	timeCache.now = now
@@ -3,6 +3,8 @@
 package blog4go
 
 import (
+	"bytes"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -37,3 +39,39 @@ func TestTimeCacheUpdate(t *testing.T) {
 		t.Error("time cache not correct when updated, dateYesterday wrong")
 	}
 }
+
+func TestTimeCacheShared(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	blogs := make([]*BLog, 100)
+	for i := range blogs {
+		blogs[i] = NewBLog(new(bytes.Buffer))
+	}
+
+	// a single goroutine updates the cache for all of them
+	if runtime.NumGoroutine() > before+1 {
+		t.Errorf("writers should share time cache goroutine. before: %d, after: %d", before, runtime.NumGoroutine())
+	}
+
+	timeCache.lock.RLock()
+	refs := timeCache.refs
+	timeCache.lock.RUnlock()
+	if refs < len(blogs) {
+		t.Errorf("time cache should count writers open. got: %d", refs)
+	}
+
+	for _, blog := range blogs {
+		blog.Close()
+	}
+
+	timeCache.lock.RLock()
+	if refs-len(blogs) != timeCache.refs {
+		t.Errorf("time cache should be released by writers closed. got: %d", timeCache.refs)
+	}
+	timeCache.lock.RUnlock()
+
+	// cache is kept current without the goroutine
+	if timeCache.Date() != time.Now().Format(DateFormat) {
+		t.Error("time cache not correct after writers closed, date wrong")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimePreset is a named time format of message prefix
+type TimePreset int
+
+const (
+	// TimeDefault is PrefixTimeFormat, formatted once a second by time cache
+	TimeDefault TimePreset = iota
+	// TimeISO8601 is ISO8601 with milliseconds, e.g. 2016-07-17T08:05:02.123+08:00
+	TimeISO8601
+	// TimeRFC3339 is time.RFC3339, e.g. 2016-07-17T08:05:02+08:00
+	TimeRFC3339
+	// TimeRFC3339Nano is time.RFC3339Nano, e.g. 2016-07-17T08:05:02.123456789+08:00
+	TimeRFC3339Nano
+	// TimeUnix is seconds since epoch, e.g. 1468713902
+	TimeUnix
+	// TimeUnixMs is milliseconds since epoch, e.g. 1468713902123
+	TimeUnixMs
+
+	// ISO8601Format is the layout of TimeISO8601
+	ISO8601Format = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// timeFormatter formats time of message prefix when time format is changed.
+// it is used under lock of BLog
+type timeFormatter struct {
+	// layout used in time.Format, empty for numeric presets
+	layout string
+	// unit of numeric presets
+	unit time.Duration
+
+	// layouts without sub second part are formatted once a second
+	subSecond bool
+	second    int64
+
+	buf []byte
+}
+
+// newTimeFormatter create a timeFormatter with layout
+func newTimeFormatter(layout string) *timeFormatter {
+	return &timeFormatter{
+		layout:    layout,
+		subSecond: strings.Contains(layout, ".0") || strings.Contains(layout, ".9"),
+		second:    -1,
+		buf:       make([]byte, 0, 64),
+	}
+}
+
+// newPresetFormatter create a timeFormatter of preset,
+// nil for TimeDefault or unknown presets
+func newPresetFormatter(preset TimePreset) *timeFormatter {
+	switch preset {
+	case TimeISO8601:
+		return newTimeFormatter(ISO8601Format)
+	case TimeRFC3339:
+		return newTimeFormatter(time.RFC3339)
+	case TimeRFC3339Nano:
+		return newTimeFormatter(time.RFC3339Nano)
+	case TimeUnix:
+		return &timeFormatter{unit: time.Second, second: -1, buf: make([]byte, 0, 20)}
+	case TimeUnixMs:
+		return &timeFormatter{unit: time.Millisecond, subSecond: true, second: -1, buf: make([]byte, 0, 20)}
+	}
+	return nil
+}
+
+// format return formatted t, it is valid until next call
+func (f *timeFormatter) format(t time.Time) []byte {
+	if !f.subSecond && t.Unix() == f.second {
+		return f.buf
+	}
+	f.second = t.Unix()
+
+	if "" == f.layout {
+		// write digits directly
+		f.buf = strconv.AppendInt(f.buf[:0], t.UnixNano()/int64(f.unit), 10)
+	} else {
+		f.buf = t.AppendFormat(f.buf[:0], f.layout)
+	}
+	return f.buf
+}
+
+// timestamp return time prefix of a new line
+func (blog *BLog) timestamp() []byte {
+	if !blog.at.IsZero() {
+		if nil == blog.timeFormat {
+			return []byte(blog.at.Format(PrefixTimeFormat))
+		}
+		return blog.timeFormat.format(blog.at)
+	}
+
+	if nil == blog.timeFormat {
+		return timeCache.Format()
+	}
+	return blog.timeFormat.format(now())
+}
+
+// now return time of the line being written, the one given to LogAt if any
+func (blog *BLog) now() time.Time {
+	if !blog.at.IsZero() {
+		return blog.at
+	}
+	return now()
+}
+
+// SetTimeFormat set layout of time prefix, the same as time.Format.
+// layouts without sub second part are formatted once a second.
+// empty layout restores PrefixTimeFormat
+func (blog *BLog) SetTimeFormat(layout string) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	if "" == layout {
+		blog.timeFormat = nil
+	} else {
+		blog.timeFormat = newTimeFormatter(layout)
+	}
+	return blog
+}
+
+// SetTimeFormatPreset set time prefix as one of the presets above
+func (blog *BLog) SetTimeFormatPreset(preset TimePreset) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.timeFormat = newPresetFormatter(preset)
+	return blog
+}
+
+// appendTime appends t formatted like time prefix to buf
+func (blog *BLog) appendTime(buf []byte, t time.Time) []byte {
+	switch {
+	case nil == blog.timeFormat:
+		return t.AppendFormat(buf, PrefixTimeFormat)
+	case "" == blog.timeFormat.layout:
+		return strconv.AppendInt(buf, t.UnixNano()/int64(blog.timeFormat.unit), 10)
+	}
+	return t.AppendFormat(buf, blog.timeFormat.layout)
+}
+
+// isTimeArg determines whether arg is a time.Time or time.Duration
+func isTimeArg(arg interface{}) bool {
+	switch arg.(type) {
+	case time.Time, time.Duration:
+		return true
+	}
+	return false
+}
+
+// writeTimeArg writes time.Time or time.Duration arg in smart time verb mode,
+// and return bytes written
+func (blog *BLog) writeTimeArg(w lineWriter, arg interface{}) (n int) {
+	switch v := arg.(type) {
+	case time.Time:
+		blog.timeBuf = blog.appendTime(blog.timeBuf[:0], v)
+		n, _ = w.Write(blog.timeBuf)
+	case time.Duration:
+		n, _ = w.WriteString(humanDuration(v))
+	}
+	return
+}
+
+// SmartTimeVerb get whether times and durations are formatted smartly
+func (blog *BLog) SmartTimeVerb() bool {
+	return blog.smartTime
+}
+
+// SetSmartTimeVerb toggle smart time verb mode, default false. In this mode
+// time.Time args of %v and %s in writef are formatted with the time format
+// of prefix, and time.Duration args in compact form such as 12.3ms
+func (blog *BLog) SetSmartTimeVerb(smart bool) *BLog {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+	blog.smartTime = smart
+	return blog
+}
+
+// SetSmartTimeVerb toggle smart time verb mode of the singleton writer
+func SetSmartTimeVerb(smart bool) {
+	blog().SetSmartTimeVerb(smart)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFormatPreset(t *testing.T) {
+	initPrefix(false)
+	c := newFakeClock()
+	c.now = time.Date(2016, 7, 17, 8, 5, 2, 123456789, time.UTC)
+	SetClock(c)
+	defer SetClock(nil)
+
+	cases := map[TimePreset]string{
+		TimeISO8601:     "2016-07-17T08:05:02.123Z",
+		TimeRFC3339:     "2016-07-17T08:05:02Z",
+		TimeRFC3339Nano: "2016-07-17T08:05:02.123456789Z",
+		TimeUnix:        "1468742702",
+		TimeUnixMs:      "1468742702123",
+	}
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	for preset, expected := range cases {
+		buf.Reset()
+		blog.SetTimeFormatPreset(preset)
+		blog.writef(INFO, "preset %d", preset)
+		blog.flush()
+
+		if expected+" [INFO] preset "+string('0'+byte(preset))+"\n" != buf.String() {
+			t.Errorf("preset format wrong. preset: %d, content: %s", preset, buf.String())
+		}
+	}
+
+	// default
+	buf.Reset()
+	blog.SetTimeFormatPreset(TimeDefault)
+	blog.write(INFO, "default")
+	blog.flush()
+	if !strings.HasPrefix(buf.String(), "[") || len(PrefixTimeFormat) != strings.Index(buf.String(), " [INFO] ") {
+		t.Errorf("default format wrong. content: %s", buf.String())
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	initPrefix(false)
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetTimeFormat("15:04:05")
+
+	blog.write(INFO, "first")
+	c.Add(500 * time.Millisecond)
+	blog.write(INFO, "second")
+	c.Add(time.Second)
+	blog.write(INFO, "third")
+	blog.flush()
+
+	expected := "00:00:00 [INFO] first\n00:00:00 [INFO] second\n00:00:01 [INFO] third\n"
+	if expected != buf.String() {
+		t.Errorf("time format wrong. content: %s", buf.String())
+	}
+
+	buf.Reset()
+	blog.SetTimeFormat("")
+	blog.write(INFO, "default")
+	blog.flush()
+	if !strings.HasPrefix(buf.String(), "[") || len(PrefixTimeFormat) != strings.Index(buf.String(), " [INFO] ") {
+		t.Errorf("empty layout should restore default format. content: %s", buf.String())
+	}
+}
+
+func TestSmartTimeVerb(t *testing.T) {
+	initPrefix(false)
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+
+	at := time.Date(2016, 7, 17, 8, 5, 2, 0, time.UTC)
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.SetTimeFormat("15:04:05")
+
+	// off by default
+	blog.writef(INFO, "at %v took %v", at, 12300*time.Microsecond)
+	blog.flush()
+	if expected := "00:00:00 [INFO] at " + at.String() + " took 12.3ms\n"; expected != buf.String() {
+		t.Errorf("smart time verb should be off by default. content: %s", buf.String())
+	}
+
+	buf.Reset()
+	blog.SetSmartTimeVerb(true)
+	blog.writef(INFO, "at %v took %s, %d", at, 12300*time.Microsecond, 3*time.Nanosecond)
+	blog.flush()
+	if expected := "00:00:00 [INFO] at 08:05:02 took 12.3ms, 3\n"; expected != buf.String() {
+		t.Errorf("smart time verb wrong. content: %s", buf.String())
+	}
+
+	buf.Reset()
+	blog.SetTimeFormatPreset(TimeUnix)
+	blog.writef(INFO, "at %s", at)
+	blog.flush()
+	if !strings.HasSuffix(buf.String(), " [INFO] at 1468742702\n") {
+		t.Errorf("smart time verb should follow time preset. content: %s", buf.String())
+	}
+}
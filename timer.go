@@ -0,0 +1,47 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// TimerLevel is the level Timer logs at
+	TimerLevel = INFO
+)
+
+// Timer starts timing an operation with the singleton writer.
+// Calling the returned function logs "name took 12.3ms" at TimerLevel, e.g.
+//
+//	defer blog4go.Timer("operation")()
+func Timer(name string) func() {
+	return NewTimer(blog(), TimerLevel, name)
+}
+
+// NewTimer starts timing an operation, calling the returned function logs how
+// long the operation took with writer at level.
+// durations are measured with the clock set by SetClock
+func NewTimer(writer Writer, level LevelType, name string) func() {
+	start := now()
+	return func() {
+		logf(writer, level, "%s took %s", name, humanDuration(now().Sub(start)))
+	}
+}
+
+// humanDuration formats d with at most one decimal for sub second durations
+// and two decimals for seconds, e.g. 850ns, 12.3ms, 1.25s
+func humanDuration(d time.Duration) string {
+	switch {
+	case d < time.Microsecond:
+		return fmt.Sprintf("%dns", d.Nanoseconds())
+	case d < time.Millisecond:
+		return fmt.Sprintf("%.1fµs", float64(d)/float64(time.Microsecond))
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return (d - d%time.Second).String()
+}
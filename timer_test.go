@@ -0,0 +1,67 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+	l   *sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2016, 7, 17, 0, 0, 0, 0, time.UTC), l: new(sync.Mutex)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTimer(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+
+	stop := NewTimer(writer, WARNING, "operation")
+	c.Add(12300 * time.Microsecond)
+	stop()
+
+	if 1 != len(sink.messages) {
+		t.Fatalf("timer message count wrong. count: %d", len(sink.messages))
+	}
+
+	if WARNING != sink.levels[0] || "operation took 12.3ms" != sink.messages[0] {
+		t.Errorf("timer message wrong. level: %s, message: %s", sink.levels[0].String(), sink.messages[0])
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		850 * time.Nanosecond:    "850ns",
+		1500 * time.Nanosecond:   "1.5µs",
+		12345 * time.Microsecond: "12.3ms",
+		1250 * time.Millisecond:  "1.25s",
+		90500 * time.Millisecond: "1m30s",
+	}
+
+	for d, expected := range cases {
+		if s := humanDuration(d); expected != s {
+			t.Errorf("human duration wrong. expected: %s, got: %s", expected, s)
+		}
+	}
+}
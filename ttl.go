@@ -0,0 +1,45 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"time"
+)
+
+const (
+	// TTLKey is the key of retention hint of a message
+	TTLKey = "ttl"
+	// ExpiresKey is the key of absolute expiry of a message, RFC3339
+	ExpiresKey = "expires"
+)
+
+// TTL adds retention hint fields "ttl" and "expires", expires is the time
+// of clock plus ttl, so that downstream systems can purge the message
+func (entry *Entry) TTL(ttl time.Duration) *Entry {
+	if nil == entry {
+		return nil
+	}
+
+	return entry.Dur(TTLKey, ttl).Str(ExpiresKey, now().Add(ttl).UTC().Format(time.RFC3339))
+}
+
+// TTLFields adds retention hint fields "ttl" and "expires" into fields of
+// json functions, such as InfoJSON(TTLFields(fields, ttl)). fields is
+// modified and returned, nil fields is created
+func TTLFields(fields map[string]interface{}, ttl time.Duration) map[string]interface{} {
+	if nil == fields {
+		fields = make(map[string]interface{}, 2)
+	}
+
+	fields[TTLKey] = ttl.String()
+	fields[ExpiresKey] = now().Add(ttl).UTC().Format(time.RFC3339)
+	return fields
+}
+
+// InfoTTL writes message with info level and retention hint fields of ttl
+// with the singleton writer, such as "msg ttl=720h0m0s expires=..."
+func InfoTTL(ttl time.Duration, message string) {
+	holder := acquireSingleton()
+	defer holder.release()
+	holder.writer.Entry(INFO).TTL(ttl).Msg(message)
+}
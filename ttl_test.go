@@ -0,0 +1,44 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTTL(t *testing.T) {
+	c := newFakeClock()
+	SetClock(c)
+	defer SetClock(nil)
+
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	defer writer.Close()
+
+	c.Add(90 * time.Minute)
+	writer.Entry(INFO).Str("user", "eddie").TTL(30 * 24 * time.Hour).Msg("login")
+
+	if 1 != len(sink.messages) {
+		t.Fatalf("message count wrong. count: %d", len(sink.messages))
+	}
+	if message := sink.messages[0]; "login user=eddie ttl=720h0m0s expires=2016-08-16T01:30:00Z" != message {
+		t.Errorf("ttl fields wrong. message: %s", message)
+	}
+
+	fields := TTLFields(nil, time.Hour)
+	if "1h0m0s" != fields[TTLKey] || "2016-07-17T02:30:00Z" != fields[ExpiresKey] {
+		t.Errorf("ttl json fields wrong. fields: %v", fields)
+	}
+
+	initPrefix(false)
+	buf := new(bytes.Buffer)
+	blog := NewBLog(buf)
+	blog.writeJSON(INFO, TTLFields(map[string]interface{}{"id": 1}, time.Hour))
+	blog.flush()
+	if !strings.Contains(buf.String(), `"expires":"2016-07-17T02:30:00Z","id":1`) || !strings.Contains(buf.String(), `"ttl":"1h0m0s"`) {
+		t.Errorf("ttl should be written in json. content: %s", buf.String())
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// txLine is a message buffered in a transaction
+type txLine struct {
+	t       time.Time
+	level   LevelType
+	message string
+
+	// message is a format of args formatted by the writer at Commit
+	formatted bool
+	args      []interface{}
+}
+
+// Tx buffers messages of a request until Commit writes them to its writer,
+// or Discard drops them, e.g. keeping full detail of failed requests only.
+// Messages keep the time they are logged at. Every Tx has a buffer of its
+// own, it is goroutine safe. Args of f functions are formatted at Commit by
+// the writer, with its placeholder and verbs, so they should not be changed
+// after being logged
+type Tx struct {
+	writer Writer
+	lines  []txLine
+
+	lock *sync.Mutex
+}
+
+// Begin starts a transaction buffering messages for the singleton writer
+func Begin() *Tx {
+	return NewTx(blog())
+}
+
+// NewTx starts a transaction buffering messages for writer
+func NewTx(writer Writer) *Tx {
+	return &Tx{writer: writer, lock: new(sync.Mutex)}
+}
+
+// log buffers message with level, messages below level of the writer are
+// dropped right away
+func (tx *Tx) log(level LevelType, message string) {
+	tx.buffer(txLine{level: level, message: message})
+}
+
+// logf buffers format and args with level, formatted at Commit
+func (tx *Tx) logf(level LevelType, format string, args []interface{}) {
+	tx.buffer(txLine{level: level, message: format, formatted: true, args: args})
+}
+
+// buffer buffers line with time now
+func (tx *Tx) buffer(line txLine) {
+	if line.level < CompileLevel || !line.level.valid() || line.level < tx.writer.Level() {
+		return
+	}
+
+	line.t = now()
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.lines = append(tx.lines, line)
+}
+
+// Len return count of messages buffered
+func (tx *Tx) Len() int {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	return len(tx.lines)
+}
+
+// Commit writes messages buffered to the writer in order, with the time
+// they were logged at, and empties the buffer
+func (tx *Tx) Commit() {
+	tx.lock.Lock()
+	lines := tx.lines
+	tx.lines = nil
+	tx.lock.Unlock()
+
+	for _, line := range lines {
+		if line.formatted {
+			tx.writer.LogfAt(line.t, line.level, line.message, line.args...)
+			continue
+		}
+		tx.writer.LogAt(line.t, line.level, line.message)
+	}
+}
+
+// Discard drops messages buffered
+func (tx *Tx) Discard() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.lines = nil
+}
+
+// Trace trace
+func (tx *Tx) Trace(args ...interface{}) {
+	tx.log(TRACE, fmt.Sprint(args...))
+}
+
+// Tracef tracef
+func (tx *Tx) Tracef(format string, args ...interface{}) {
+	tx.logf(TRACE, format, args)
+}
+
+// Debug debug
+func (tx *Tx) Debug(args ...interface{}) {
+	tx.log(DEBUG, fmt.Sprint(args...))
+}
+
+// Debugf debugf
+func (tx *Tx) Debugf(format string, args ...interface{}) {
+	tx.logf(DEBUG, format, args)
+}
+
+// Info info
+func (tx *Tx) Info(args ...interface{}) {
+	tx.log(INFO, fmt.Sprint(args...))
+}
+
+// Infof infof
+func (tx *Tx) Infof(format string, args ...interface{}) {
+	tx.logf(INFO, format, args)
+}
+
+// Warn warn
+func (tx *Tx) Warn(args ...interface{}) {
+	tx.log(WARNING, fmt.Sprint(args...))
+}
+
+// Warnf warnf
+func (tx *Tx) Warnf(format string, args ...interface{}) {
+	tx.logf(WARNING, format, args)
+}
+
+// Error error
+func (tx *Tx) Error(args ...interface{}) {
+	tx.log(ERROR, fmt.Sprint(args...))
+}
+
+// Errorf errorf
+func (tx *Tx) Errorf(format string, args ...interface{}) {
+	tx.logf(ERROR, format, args)
+}
+
+// Critical critical
+func (tx *Tx) Critical(args ...interface{}) {
+	tx.log(CRITICAL, fmt.Sprint(args...))
+}
+
+// Criticalf criticalf
+func (tx *Tx) Criticalf(format string, args ...interface{}) {
+	tx.logf(CRITICAL, format, args)
+}
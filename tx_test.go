@@ -0,0 +1,46 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTx(t *testing.T) {
+	sink := newMySink()
+	writer := NewSinkWriter(sink)
+	writer.SetLevel(INFO)
+
+	// successful request
+	tx := NewTx(writer)
+	tx.Infof("request %d", 1)
+	tx.Warn("slow")
+	tx.Discard()
+	tx.Commit()
+	if 0 != len(sink.messages) {
+		t.Fatalf("discarded messages should not be written. got: %q", sink.messages)
+	}
+
+	// failed request
+	tx = NewTx(writer)
+	tx.Debug("filtered")
+	tx.Infof("request %d", 2)
+	tx.Warn("slow")
+	tx.Errorf("failed: %s", "timeout")
+	if 3 != tx.Len() || 0 != len(sink.messages) {
+		t.Fatalf("messages should be buffered until commit. buffered: %d, written: %d", tx.Len(), len(sink.messages))
+	}
+	tx.Commit()
+
+	expected := []string{"request 2", "slow", "failed: timeout"}
+	if fmt.Sprint(expected) != fmt.Sprint(sink.messages) {
+		t.Errorf("committed messages should be written in order. got: %q", sink.messages)
+	}
+	if fmt.Sprint([]LevelType{INFO, WARNING, ERROR}) != fmt.Sprint(sink.levels) {
+		t.Errorf("committed messages should keep their levels. got: %v", sink.levels)
+	}
+	if 0 != tx.Len() {
+		t.Errorf("buffer should be emptied by commit. got: %d", tx.Len())
+	}
+}
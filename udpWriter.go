@@ -0,0 +1,33 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"net"
+)
+
+const (
+	// MaxDatagramSize is the maximum size of a line sent by udp writer,
+	// 1500 bytes of ethernet MTU minus 28 bytes of IPv4 and UDP headers.
+	// longer lines are truncated rather than split, so that a datagram is
+	// always a whole line
+	MaxDatagramSize = 1472
+)
+
+// NewUDPWriter creates a socket writer sending every line as a single UDP
+// datagram to address, not singlton. it is best effort, lines lost on the
+// way are never known, failed sends are counted in SendErrors.
+// Lines longer than MaxDatagramSize are truncated, and every line of a
+// multi-line message such as DebugPretty is sent as its own datagram
+func NewUDPWriter(address string) (*SocketWriter, error) {
+	conn, err := net.Dial("udp", address)
+	if nil != err {
+		return nil, err
+	}
+
+	writer := newConnWriter(conn)
+	writer.network = "udp"
+	writer.address = address
+	writer.maxPacket = MaxDatagramSize
+	return writer, nil
+}
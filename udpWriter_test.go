@@ -0,0 +1,75 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUDPWriter(t *testing.T) {
+	initPrefix(false)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatalf("listen udp failed. err: %s", err.Error())
+	}
+	defer conn.Close()
+
+	writer, err := NewUDPWriter(conn.LocalAddr().String())
+	if nil != err {
+		t.Fatalf("create udp writer failed. err: %s", err.Error())
+	}
+	defer writer.Close()
+
+	writer.Info("first")
+	writer.Errorf("second %d", 2)
+	writer.Warn(strings.Repeat("x", 2*MaxDatagramSize))
+
+	var datagrams []string
+	buf := make([]byte, 4*MaxDatagramSize)
+	for i := 0; i < 3; i++ {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if nil != err {
+			t.Fatalf("read datagram failed. err: %s", err.Error())
+		}
+		datagrams = append(datagrams, string(buf[:n]))
+	}
+
+	if !strings.HasSuffix(datagrams[0], " [INFO] first") || !strings.HasSuffix(datagrams[1], " [ERROR] second 2") {
+		t.Errorf("every line should be a datagram. datagrams: %q", datagrams[:2])
+	}
+	if MaxDatagramSize != len(datagrams[2]) || !strings.Contains(datagrams[2], " [WARN] xxx") {
+		t.Errorf("long line should be truncated. length: %d", len(datagrams[2]))
+	}
+	if 0 != writer.SendErrors() {
+		t.Errorf("no send should fail. errors: %d", writer.SendErrors())
+	}
+}
+
+func TestUDPWriterSendErrors(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatalf("listen udp failed. err: %s", err.Error())
+	}
+
+	writer, err := NewUDPWriter(conn.LocalAddr().String())
+	if nil != err {
+		t.Fatalf("create udp writer failed. err: %s", err.Error())
+	}
+	defer writer.Close()
+
+	// nobody listens, sends fail once port unreachable is reported
+	conn.Close()
+	for i := 0; i < 10 && 0 == writer.SendErrors(); i++ {
+		writer.Info("lost")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if 0 == writer.SendErrors() {
+		t.Error("failed sends should be counted.")
+	}
+}
@@ -0,0 +1,454 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	// ErrConfigNotSupported show that the writer does not keep its settings
+	// in a BLog, such as socket and sink writers
+	ErrConfigNotSupported = errors.New("writer does not support reading back its config")
+)
+
+// WriterConfig is a snapshot of settings of a writer, returned by Config and
+// applied back by ApplyConfig. It can be serialized as json, so that
+// settings are saved and restored, or reconfigured from outside as a whole.
+// Settings a writer does not support are zero and ignored when applied
+type WriterConfig struct {
+	// logging level, such as "INFO"
+	Level string `json:"level"`
+	// level at or above which messages are flushed immediately, empty
+	// means disabled
+	FlushLevel string `json:"flushLevel,omitempty"`
+
+	// level and mode of hook
+	HookLevel string `json:"hookLevel"`
+	HookAsync bool   `json:"hookAsync"`
+
+	// layout of time prefix, empty means PrefixTimeFormat.
+	// TimePreset is used instead if it is not TimeDefault
+	TimeFormat string     `json:"timeFormat,omitempty"`
+	TimePreset TimePreset `json:"timePreset,omitempty"`
+
+	// formatting, empty Placeholder, Escape and EOL mean PLACEHOLDER,
+	// ESCAPE and EOL
+	Placeholder  string `json:"placeholder,omitempty"`
+	Escape       string `json:"escape,omitempty"`
+	EOL          string `json:"eol,omitempty"`
+	NilString    string `json:"nilString,omitempty"`
+	ReplaceNil   bool   `json:"replaceNil,omitempty"`
+	StrictFormat bool   `json:"strictFormat,omitempty"`
+	SafeStringer bool   `json:"safeStringer,omitempty"`
+	RawStringer  bool   `json:"rawStringer,omitempty"`
+	SmartTime    bool   `json:"smartTime,omitempty"`
+
+	PrintSequence bool `json:"printSequence,omitempty"`
+	SequenceWidth int  `json:"sequenceWidth,omitempty"`
+	// write milliseconds since start ahead every message
+	PrintElapsed bool `json:"printElapsed,omitempty"`
+	// write hostname ahead every message, Hostname overrides the machine
+	// hostname when not empty
+	PrintHostname bool   `json:"printHostname,omitempty"`
+	Hostname      string `json:"hostname,omitempty"`
+	// write program[pid] ahead every message
+	PrintPID     bool `json:"printPID,omitempty"`
+	PrintProgram bool `json:"printProgram,omitempty"`
+
+	Colored         bool `json:"colored,omitempty"`
+	AtomicWrite     bool `json:"atomicWrite,omitempty"`
+	MultilinePrefix bool `json:"multilinePrefix,omitempty"`
+
+	// size of buffer, 0 keeps current one
+	BufferSize int `json:"bufferSize,omitempty"`
+
+	// logrotate
+	TimeRotated  bool  `json:"timeRotated,omitempty"`
+	RotateSize   int64 `json:"rotateSize,omitempty"`
+	RotateLines  int   `json:"rotateLines,omitempty"`
+	Retentions   int64 `json:"retentions,omitempty"`
+	MaxTotalSize int64 `json:"maxTotalSize,omitempty"`
+
+	// permission of log files, 0 keeps current one
+	FileMode os.FileMode `json:"fileMode,omitempty"`
+}
+
+// levelString return string of level, empty for invalid levels
+func levelString(level LevelType) string {
+	if !level.valid() {
+		return ""
+	}
+	return level.String()
+}
+
+// characters return placeholder, escape character and EOL of config
+func (cfg *WriterConfig) characters() (placeholder, escape, eol byte) {
+	placeholder, escape, eol = PLACEHOLDER, ESCAPE, EOL
+	if "" != cfg.Placeholder {
+		placeholder = cfg.Placeholder[0]
+	}
+	if "" != cfg.Escape {
+		escape = cfg.Escape[0]
+	}
+	if "" != cfg.EOL {
+		eol = cfg.EOL[0]
+	}
+	return
+}
+
+// valid checks every setting of config, so that nothing is changed if any of
+// them is wrong
+func (cfg *WriterConfig) valid() error {
+	if !LevelFromString(cfg.Level).valid() {
+		return fmt.Errorf("blog4go: invalid level %q in config", cfg.Level)
+	}
+	if "" != cfg.FlushLevel && !LevelFromString(cfg.FlushLevel).valid() {
+		return fmt.Errorf("blog4go: invalid flush level %q in config", cfg.FlushLevel)
+	}
+	if !LevelFromString(cfg.HookLevel).valid() {
+		return fmt.Errorf("blog4go: invalid hook level %q in config", cfg.HookLevel)
+	}
+
+	if TimeDefault > cfg.TimePreset || TimeUnixMs < cfg.TimePreset {
+		return fmt.Errorf("blog4go: invalid time preset %d in config", cfg.TimePreset)
+	}
+
+	if 1 < len(cfg.Placeholder) || 1 < len(cfg.Escape) || 1 < len(cfg.EOL) {
+		return fmt.Errorf("blog4go: placeholder, escape and eol must be a single character in config")
+	}
+	if placeholder, escape, eol := cfg.characters(); placeholder == escape || placeholder == eol || escape == eol {
+		return fmt.Errorf("blog4go: placeholder %q, escape %q and eol %q must differ in config", placeholder, escape, eol)
+	}
+
+	if cfg.SequenceWidth < 0 || cfg.BufferSize < 0 || cfg.RotateSize < 0 || cfg.RotateLines < 0 || cfg.Retentions < 0 || cfg.MaxTotalSize < 0 {
+		return fmt.Errorf("blog4go: negative size in config")
+	}
+
+	if cfg.FileMode != cfg.FileMode.Perm() {
+		return fmt.Errorf("blog4go: invalid file mode %s in config", cfg.FileMode)
+	}
+
+	return nil
+}
+
+// config fills settings of BLog into cfg
+func (blog *BLog) config(cfg *WriterConfig) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	cfg.Level = levelString(blog.level)
+	cfg.FlushLevel = levelString(blog.flushLevel)
+
+	if nil != blog.timeFormat {
+		switch blog.timeFormat.unit {
+		case time.Second:
+			cfg.TimePreset = TimeUnix
+		case time.Millisecond:
+			cfg.TimePreset = TimeUnixMs
+		default:
+			cfg.TimeFormat = blog.timeFormat.layout
+		}
+	}
+
+	cfg.Placeholder = string(blog.placeholder)
+	cfg.Escape = string(blog.escape)
+	cfg.EOL = string(blog.eol)
+	cfg.NilString = blog.nilString
+	cfg.ReplaceNil = blog.replaceNil
+	cfg.SafeStringer = blog.safeStringer
+	cfg.RawStringer = blog.rawStringer
+	cfg.SmartTime = blog.smartTime
+	cfg.StrictFormat = blog.strict
+	cfg.PrintSequence = blog.sequence
+	cfg.SequenceWidth = blog.seqWidth
+	cfg.PrintElapsed = blog.elapsed
+	cfg.PrintHostname = blog.defaults.printHost
+	cfg.Hostname = blog.defaults.host
+	cfg.PrintPID = blog.defaults.printPID
+	cfg.PrintProgram = blog.defaults.printProgram
+	cfg.AtomicWrite = blog.atomic
+	cfg.MultilinePrefix = blog.multiline
+	cfg.BufferSize = blog.writer.Size()
+}
+
+// applyConfig applies a valid cfg to BLog under a single lock
+func (blog *BLog) applyConfig(cfg *WriterConfig) {
+	blog.lock.Lock()
+	defer blog.lock.Unlock()
+
+	blog.level = LevelFromString(cfg.Level)
+	blog.flushLevel = noFlushLevel
+	if "" != cfg.FlushLevel {
+		blog.flushLevel = LevelFromString(cfg.FlushLevel)
+	}
+
+	if TimeDefault != cfg.TimePreset {
+		blog.timeFormat = newPresetFormatter(cfg.TimePreset)
+	} else if "" != cfg.TimeFormat {
+		blog.timeFormat = newTimeFormatter(cfg.TimeFormat)
+	} else {
+		blog.timeFormat = nil
+	}
+
+	blog.placeholder, blog.escape, blog.eol = cfg.characters()
+	blog.nilString = cfg.NilString
+	blog.replaceNil = cfg.ReplaceNil
+	blog.safeStringer = cfg.SafeStringer
+	blog.rawStringer = cfg.RawStringer
+	blog.smartTime = cfg.SmartTime
+	blog.strict = cfg.StrictFormat
+	blog.sequence = cfg.PrintSequence
+	blog.seqWidth = cfg.SequenceWidth
+	blog.elapsed = cfg.PrintElapsed
+	blog.defaults.printHost = cfg.PrintHostname
+	blog.defaults.printPID = cfg.PrintPID
+	blog.defaults.printProgram = cfg.PrintProgram
+	blog.defaults.setHost(cfg.Hostname)
+	blog.atomic = cfg.AtomicWrite
+	blog.multiline = cfg.MultilinePrefix
+
+	if 0 < cfg.BufferSize && cfg.BufferSize != blog.writer.Size() {
+		blog.writer.Flush()
+		blog.resize(cfg.BufferSize)
+	}
+}
+
+// Config return settings of the base file writer, zero config if it is closed
+func (writer *baseFileWriter) Config() (cfg WriterConfig) {
+	writer.lock.RLock()
+	defer writer.lock.RUnlock()
+	if writer.closed {
+		return
+	}
+
+	writer.blog.config(&cfg)
+	cfg.HookLevel = levelString(writer.hookLevel)
+	cfg.HookAsync = writer.hookAsync
+	cfg.Colored = writer.colored
+
+	cfg.TimeRotated = writer.timeRotated
+	if writer.sizeRotated {
+		cfg.RotateSize = writer.rotateSize
+	}
+	if writer.lineRotated {
+		cfg.RotateLines = writer.rotateLines
+	}
+	cfg.Retentions = writer.retentions
+	cfg.MaxTotalSize = writer.maxTotalSize
+	cfg.FileMode = writer.fileMode
+	return
+}
+
+// ApplyConfig validates cfg and applies all of it, nothing is changed if
+// it fails
+func (writer *baseFileWriter) ApplyConfig(cfg WriterConfig) error {
+	if err := cfg.valid(); nil != err {
+		return err
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	if writer.closed {
+		return ErrWriterClosed
+	}
+
+	if 0 != cfg.FileMode && cfg.FileMode != writer.fileMode {
+		// the only setting may fail, apply it first
+		previous := writer.fileMode
+		writer.fileMode = cfg.FileMode
+		if err := writer.applyFileAttributes(); nil != err {
+			writer.fileMode = previous
+			return err
+		}
+	}
+
+	writer.blog.applyConfig(&cfg)
+	writer.hookLevel = LevelFromString(cfg.HookLevel)
+	writer.hookAsync = cfg.HookAsync
+	if cfg.Colored != writer.colored {
+		writer.colored = cfg.Colored
+		initPrefix(cfg.Colored)
+	}
+
+	writer.timeRotated = cfg.TimeRotated
+	writer.sizeRotated = 0 < cfg.RotateSize
+	if writer.sizeRotated {
+		writer.rotateSize = cfg.RotateSize
+	}
+	writer.lineRotated = 0 < cfg.RotateLines
+	if writer.lineRotated {
+		writer.rotateLines = cfg.RotateLines
+	}
+	if 0 < cfg.Retentions {
+		writer.retentions = cfg.Retentions
+	}
+	writer.maxTotalSize = cfg.MaxTotalSize
+	return nil
+}
+
+// Config return settings of the console writer
+func (writer *ConsoleWriter) Config() (cfg WriterConfig) {
+	writer.blog.config(&cfg)
+	cfg.HookLevel = levelString(writer.hookLevel)
+	cfg.HookAsync = writer.hookAsync
+	cfg.Colored = writer.colored
+	return
+}
+
+// ApplyConfig validates cfg and applies all of it, logrotate and file
+// settings are ignored
+func (writer *ConsoleWriter) ApplyConfig(cfg WriterConfig) error {
+	if err := cfg.valid(); nil != err {
+		return err
+	}
+
+	writer.blog.applyConfig(&cfg)
+	writer.hookLevel = LevelFromString(cfg.HookLevel)
+	writer.hookAsync = cfg.HookAsync
+	writer.SetColored(cfg.Colored)
+	return nil
+}
+
+// Config return settings of the socket writer, only level and hook are
+// supported
+func (writer *SocketWriter) Config() (cfg WriterConfig) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	cfg.Level = levelString(writer.level)
+	cfg.HookLevel = levelString(writer.hookLevel)
+	cfg.HookAsync = writer.hookAsync
+	return
+}
+
+// ApplyConfig validates cfg and applies level and hook settings
+func (writer *SocketWriter) ApplyConfig(cfg WriterConfig) error {
+	if err := cfg.valid(); nil != err {
+		return err
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.level = LevelFromString(cfg.Level)
+	writer.hookLevel = LevelFromString(cfg.HookLevel)
+	writer.hookAsync = cfg.HookAsync
+	return nil
+}
+
+// Config return settings of the sink writer, only level and hook are
+// supported
+func (writer *SinkWriter) Config() (cfg WriterConfig) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	cfg.Level = levelString(writer.level)
+	cfg.HookLevel = levelString(writer.hookLevel)
+	cfg.HookAsync = writer.hookAsync
+	return
+}
+
+// ApplyConfig validates cfg and applies level and hook settings
+func (writer *SinkWriter) ApplyConfig(cfg WriterConfig) error {
+	if err := cfg.valid(); nil != err {
+		return err
+	}
+
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	writer.level = LevelFromString(cfg.Level)
+	writer.hookLevel = LevelFromString(cfg.HookLevel)
+	writer.hookAsync = cfg.HookAsync
+	return nil
+}
+
+// Config return settings of the multi writer
+func (writer *MultiWriter) Config() (cfg WriterConfig) {
+	cfg.Level = levelString(writer.level)
+	cfg.FlushLevel = levelString(writer.flushLevel)
+	cfg.HookLevel = levelString(writer.hookLevel)
+	cfg.HookAsync = writer.hookAsync
+	cfg.Placeholder = string(writer.placeholder)
+	cfg.Escape = string(writer.escape)
+	cfg.EOL = string(writer.eol)
+	cfg.StrictFormat = writer.strict
+	cfg.Colored = writer.colored
+	cfg.AtomicWrite = writer.atomic
+	cfg.MultilinePrefix = writer.multiline
+	cfg.TimeRotated = writer.timeRotated
+	cfg.RotateSize = writer.rotateSize
+	cfg.RotateLines = writer.rotateLines
+	cfg.Retentions = writer.retentions
+	cfg.MaxTotalSize = writer.maxTotalSize
+	cfg.FileMode = writer.fileMode
+	return
+}
+
+// ApplyConfig validates cfg once and applies it to every writer. It stops at
+// the first error, writers already applied are restored to their previous
+// config and nothing of the multi writer is changed
+func (writer *MultiWriter) ApplyConfig(cfg WriterConfig) (err error) {
+	if err = cfg.valid(); nil != err {
+		return
+	}
+
+	applied := make(map[LevelType]WriterConfig, len(writer.writers))
+	for level, fileWriter := range writer.writers {
+		previous := fileWriter.Config()
+		if err = fileWriter.ApplyConfig(cfg); nil != err {
+			for level, previous := range applied {
+				writer.writers[level].ApplyConfig(previous)
+			}
+			return
+		}
+		applied[level] = previous
+	}
+
+	writer.level = LevelFromString(cfg.Level)
+	writer.flushLevel = noFlushLevel
+	if "" != cfg.FlushLevel {
+		writer.flushLevel = LevelFromString(cfg.FlushLevel)
+	}
+	writer.hookLevel = LevelFromString(cfg.HookLevel)
+	writer.hookAsync = cfg.HookAsync
+	writer.placeholder, writer.escape, writer.eol = cfg.characters()
+	writer.format.setCharacters(writer.placeholder, writer.escape, writer.eol)
+	writer.strict = cfg.StrictFormat
+	writer.colored = cfg.Colored
+	writer.atomic = cfg.AtomicWrite
+	writer.multiline = cfg.MultilinePrefix
+	writer.timeRotated = cfg.TimeRotated
+	writer.rotateSize = cfg.RotateSize
+	writer.rotateLines = cfg.RotateLines
+	writer.retentions = cfg.Retentions
+	writer.maxTotalSize = cfg.MaxTotalSize
+	if 0 != cfg.FileMode {
+		writer.fileMode = cfg.FileMode
+	}
+	return
+}
+
+// SingletonConfig return effective settings of the singleton writer, it fails
+// when the singleton is closed or is not backed by BLog, e.g. a socket writer
+func SingletonConfig() (cfg WriterConfig, err error) {
+	singltonLock.Lock()
+	defer singltonLock.Unlock()
+
+	switch blog().(type) {
+	case nil:
+		return cfg, ErrWriterClosed
+	case *SocketWriter, *SinkWriter:
+		return cfg, ErrConfigNotSupported
+	}
+	return blog().Config(), nil
+}
+
+// ApplyConfig validates cfg and applies all of it to the singleton writer
+func ApplyConfig(cfg WriterConfig) error {
+	return blog().ApplyConfig(cfg)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2015, huangjunwei <huangjunwei@youmi.net>. All rights reserved.
+
+package blog4go
+
+import (
+	"encoding/json"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestBaseFileWriterConfig(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/config.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	other, err := newBaseFileWriter("/tmp/config_other.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+		other.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	saved := writer.Config()
+
+	writer.SetLevel(WARNING)
+	writer.SetFlushLevel(ERROR)
+	writer.SetHookLevel(CRITICAL)
+	writer.SetHookAsync(false)
+	writer.SetTimeFormatPreset(TimeUnixMs)
+	writer.SetPlaceholder('$')
+	writer.SetEscape('!')
+	writer.SetEOL(0)
+	writer.SetNilString("-")
+	writer.SetStrictFormat(true)
+	writer.SetAtomicWrite(true)
+	writer.SetRotateSize(10 * MB)
+	writer.SetRotateLines(1000)
+	writer.SetRetentions(7)
+	writer.SetMaxTotalSize(100 * MB)
+	writer.SetFileMode(0600)
+
+	// round trip through json
+	data, err := json.Marshal(writer.Config())
+	if nil != err {
+		t.Fatalf("marshal config failed. err: %s", err.Error())
+	}
+	var cfg WriterConfig
+	if err = json.Unmarshal(data, &cfg); nil != err {
+		t.Fatalf("unmarshal config failed. err: %s", err.Error())
+	}
+
+	if err = other.ApplyConfig(cfg); nil != err {
+		t.Fatalf("apply config failed. err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(writer.Config(), other.Config()) {
+		t.Errorf("config should be the same after applied.\nexpected: %+v\ngot: %+v", writer.Config(), other.Config())
+	}
+	if WARNING != other.Level() || '$' != other.Placeholder() || '!' != other.blog.Escape() || 0 != other.blog.EOL() || 0600 != other.FileMode() || !other.AtomicWrite() {
+		t.Errorf("settings should be applied. config: %+v", other.Config())
+	}
+
+	// restore
+	if err = writer.ApplyConfig(saved); nil != err {
+		t.Fatalf("restore config failed. err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(saved, writer.Config()) {
+		t.Errorf("config should be restored.\nexpected: %+v\ngot: %+v", saved, writer.Config())
+	}
+}
+
+func TestBaseFileWriterApplyInvalidConfig(t *testing.T) {
+	writer, err := newBaseFileWriter("/tmp/config.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		writer.Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	saved := writer.Config()
+
+	cfg := writer.Config()
+	cfg.Level = "ERROR"
+	cfg.RotateLines = 100
+	cfg.Placeholder = "%%"
+	if nil == writer.ApplyConfig(cfg) {
+		t.Fatal("applying invalid placeholder should fail.")
+	}
+
+	cfg.Placeholder = "%"
+	cfg.EOL = "%"
+	if nil == writer.ApplyConfig(cfg) {
+		t.Fatal("applying eol same as placeholder should fail.")
+	}
+
+	cfg.EOL = ""
+	cfg.HookLevel = "LOUD"
+	if nil == writer.ApplyConfig(cfg) {
+		t.Fatal("applying invalid level should fail.")
+	}
+
+	if !reflect.DeepEqual(saved, writer.Config()) {
+		t.Errorf("nothing should be changed by invalid config.\nexpected: %+v\ngot: %+v", saved, writer.Config())
+	}
+}
+
+func TestMultiWriterApplyConfigFailed(t *testing.T) {
+	err := NewFileWriter("/tmp", false)
+	if nil != err {
+		t.Fatalf("initialize file writer faied. err: %s", err.Error())
+	}
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	writer := blog().(*MultiWriter)
+
+	// a closed level writer rejects the config
+	writer.writers[ERROR].Close()
+
+	saved := writer.Config()
+	previous := make(map[LevelType]WriterConfig)
+	for level, fileWriter := range writer.writers {
+		previous[level] = fileWriter.Config()
+	}
+
+	cfg := writer.Config()
+	cfg.Level = "CRITICAL"
+	cfg.Placeholder = "@"
+	cfg.RotateLines = 100
+	if ErrWriterClosed != writer.ApplyConfig(cfg) {
+		t.Fatal("applying config should fail on the closed writer.")
+	}
+
+	if !reflect.DeepEqual(saved, writer.Config()) {
+		t.Errorf("multi writer should not be changed.\nexpected: %+v\ngot: %+v", saved, writer.Config())
+	}
+	for level, fileWriter := range writer.writers {
+		if !reflect.DeepEqual(previous[level], fileWriter.Config()) {
+			t.Errorf("level writer should be restored. level: %s, config: %+v", level, fileWriter.Config())
+		}
+	}
+}
+
+func TestSingletonConfig(t *testing.T) {
+	err := NewBaseFileWriter("/tmp/singleton_config.log", false)
+	if nil != err {
+		t.Fatalf("Failed when initializing base file writer. err: %s", err.Error())
+	}
+	defer func() {
+		Close()
+
+		// clean logs
+		_, err = exec.Command("/bin/sh", "-c", "/bin/rm /tmp/*.log*").Output()
+		if nil != err {
+			t.Errorf("clean files failed. err: %s", err.Error())
+		}
+	}()
+
+	SetLevel(WARNING)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			Warnf("concurrent %d", i)
+		}
+	}()
+
+	cfg, err := SingletonConfig()
+	<-done
+	if nil != err {
+		t.Fatalf("singleton config should be read. err: %s", err.Error())
+	}
+	if WARNING.String() != cfg.Level {
+		t.Errorf("level wrong. expected: %s, got: %s", WARNING.String(), cfg.Level)
+	}
+}
+
+func TestSingletonConfigNotSupported(t *testing.T) {
+	defer func(singlton Writer) { setBlog(singlton) }(blog())
+
+	setBlog(NewSinkWriter(newMySink()))
+	if _, err := SingletonConfig(); ErrConfigNotSupported != err {
+		t.Errorf("sink writer should not support singleton config. err: %v", err)
+	}
+
+	setBlog(nil)
+	if _, err := SingletonConfig(); ErrWriterClosed != err {
+		t.Errorf("closed singleton should fail. err: %v", err)
+	}
+}